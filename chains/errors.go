@@ -20,6 +20,9 @@ var (
 	// ErrInvalidOutputValues is returned when expected output keys to a chain does
 	// not match the actual keys in the return output values map.
 	ErrInvalidOutputValues = errors.New("missing key in output values")
+	// ErrOutputParsing is returned when the output parser of a chain fails to
+	// parse the output of the llm.
+	ErrOutputParsing = errors.New("error parsing llm output")
 
 	// ErrMultipleInputsInRun is returned in the run function if the chain expects
 	// more then one input values.
@@ -2,6 +2,8 @@ package chains
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/tmc/langchaingo/callbacks"
 	"github.com/tmc/langchaingo/llms"
@@ -11,16 +13,40 @@ import (
 	"github.com/tmc/langchaingo/schema"
 )
 
-const _llmChainDefaultOutputKey = "text"
+const (
+	_llmChainDefaultOutputKey = "text"
+
+	// _llmChainFormatInstructionsKey is the key under which the format instructions
+	// of the output parser are passed to the prompt.
+	_llmChainFormatInstructionsKey = "format_instructions"
+)
 
 type LLMChain struct {
 	Prompt           prompts.FormatPrompter
 	LLM              llms.LanguageModel
 	Memory           schema.Memory
 	CallbacksHandler callbacks.Handler
-	OutputParser     schema.OutputParser[any]
+	// OutputParser parses the output of the llm. Its format instructions, if any,
+	// are available in the prompt as the "format_instructions" partial variable,
+	// unless a value for that variable is given in the inputs.
+	OutputParser schema.OutputParser[any]
 
 	OutputKey string
+	// RawOutputKey is, if set, the key under which the unparsed output of the llm
+	// is returned in addition to the parsed output. As the chain then has two
+	// output keys, a memory such as memory.ConversationBuffer must be given the
+	// output key to save, e.g. with memory.WithOutputKey.
+	RawOutputKey string
+	// ReturnRawOnParseError makes the chain return the unparsed output of the llm
+	// under the output key instead of an error when the output parser fails with
+	// an outputparser.ParseError. Other errors are still returned.
+	ReturnRawOnParseError bool
+}
+
+// contextParser is implemented by output parsers that call an llm when parsing,
+// such as outputparser.Retry.
+type contextParser interface {
+	ParseWithPromptContext(ctx context.Context, text string, prompt schema.PromptValue) (any, error)
 }
 
 var (
@@ -47,7 +73,7 @@ func NewLLMChain(llm llms.LanguageModel, prompt prompts.FormatPrompter) *LLMChai
 // directly, use rather the Call or Run function if the prompt only requires one input
 // value.
 func (c LLMChain) Call(ctx context.Context, values map[string]any, options ...ChainCallOption) (map[string]any, error) {
-	promptValue, err := c.Prompt.FormatPrompt(values)
+	promptValue, err := c.formatPrompt(values)
 	if err != nil {
 		return nil, err
 	}
@@ -61,12 +87,80 @@ func (c LLMChain) Call(ctx context.Context, values map[string]any, options ...Ch
 		return nil, err
 	}
 
-	finalOutput, err := c.OutputParser.ParseWithPrompt(result.Generations[0][0].Text, promptValue)
+	rawOutput := result.Generations[0][0].Text
+	finalOutput, err := c.parse(ctx, rawOutput, promptValue)
 	if err != nil {
-		return nil, err
+		if !c.ReturnRawOnParseError || !errors.As(err, &outputparser.ParseError{}) {
+			return nil, fmt.Errorf("%w: %w", ErrOutputParsing, err)
+		}
+		finalOutput = rawOutput
+	}
+
+	outputs := map[string]any{c.OutputKey: finalOutput}
+	if c.RawOutputKey != "" {
+		outputs[c.RawOutputKey] = rawOutput
 	}
 
-	return map[string]any{c.OutputKey: finalOutput}, nil
+	return outputs, nil
+}
+
+func (c LLMChain) parse(ctx context.Context, text string, promptValue schema.PromptValue) (any, error) {
+	if parser, ok := c.OutputParser.(contextParser); ok {
+		return parser.ParseWithPromptContext(ctx, text, promptValue)
+	}
+	return c.OutputParser.ParseWithPrompt(text, promptValue)
+}
+
+// formatPrompt formats the prompt with the values. The format instructions of the
+// output parser are given to the prompt as a partial variable, so that they can be
+// overridden by the values.
+func (c LLMChain) formatPrompt(values map[string]any) (schema.PromptValue, error) { //nolint:ireturn
+	if !c.providesFormatInstructions() {
+		return c.Prompt.FormatPrompt(values)
+	}
+
+	instructions := c.OutputParser.GetFormatInstructions()
+	switch prompt := c.Prompt.(type) {
+	case prompts.PromptTemplate:
+		prompt.PartialVariables = withPartialVariable(prompt.PartialVariables, _llmChainFormatInstructionsKey, instructions)
+		return prompt.FormatPrompt(values)
+	case prompts.ChatPromptTemplate:
+		prompt.PartialVariables = withPartialVariable(prompt.PartialVariables, _llmChainFormatInstructionsKey, instructions)
+		return prompt.FormatPrompt(values)
+	}
+
+	// Other prompts have no partial variables, so the instructions are passed as a value.
+	if _, ok := values[_llmChainFormatInstructionsKey]; ok {
+		return c.Prompt.FormatPrompt(values)
+	}
+	valuesWithInstructions := make(map[string]any, len(values)+1)
+	for key, value := range values {
+		valuesWithInstructions[key] = value
+	}
+	valuesWithInstructions[_llmChainFormatInstructionsKey] = instructions
+
+	return c.Prompt.FormatPrompt(valuesWithInstructions)
+}
+
+// providesFormatInstructions reports whether the output parser has format instructions.
+func (c LLMChain) providesFormatInstructions() bool {
+	return c.OutputParser != nil && c.OutputParser.GetFormatInstructions() != ""
+}
+
+// withPartialVariable returns a copy of the partial variables with the variable added,
+// unless it is already set.
+func withPartialVariable(partialVariables map[string]any, key string, value any) map[string]any {
+	if _, ok := partialVariables[key]; ok {
+		return partialVariables
+	}
+
+	newPartialVariables := make(map[string]any, len(partialVariables)+1)
+	for k, v := range partialVariables {
+		newPartialVariables[k] = v
+	}
+	newPartialVariables[key] = value
+
+	return newPartialVariables
 }
 
 // GetMemory returns the memory.
@@ -78,12 +172,23 @@ func (c LLMChain) GetCallbackHandler() callbacks.Handler { //nolint:ireturn
 	return c.CallbacksHandler
 }
 
-// GetInputKeys returns the expected input keys.
+// GetInputKeys returns the expected input keys. The format instructions are not
+// expected when they are provided by the output parser.
 func (c LLMChain) GetInputKeys() []string {
-	return append([]string{}, c.Prompt.GetInputVariables()...)
+	inputKeys := make([]string, 0, len(c.Prompt.GetInputVariables()))
+	for _, key := range c.Prompt.GetInputVariables() {
+		if key == _llmChainFormatInstructionsKey && c.providesFormatInstructions() {
+			continue
+		}
+		inputKeys = append(inputKeys, key)
+	}
+	return inputKeys
 }
 
 // GetOutputKeys returns the output keys the chain will return.
 func (c LLMChain) GetOutputKeys() []string {
+	if c.RawOutputKey != "" {
+		return []string{c.OutputKey, c.RawOutputKey}
+	}
 	return []string{c.OutputKey}
 }
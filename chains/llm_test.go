@@ -2,6 +2,7 @@ package chains
 
 import (
 	"context"
+	"errors"
 	"os"
 	"strings"
 	"testing"
@@ -9,7 +10,9 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/tmc/langchaingo/callbacks"
 	"github.com/tmc/langchaingo/llms/openai"
+	"github.com/tmc/langchaingo/outputparser"
 	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
 )
 
 func TestLLMChain(t *testing.T) {
@@ -55,3 +58,86 @@ func TestLLMChainWithChatPromptTemplate(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "AI: foo\nHuman: boo", result)
 }
+
+type testAnswer struct {
+	Answer string `json:"answer"`
+}
+
+func TestLLMChainWithOutputParser(t *testing.T) {
+	t.Parallel()
+
+	parser, err := outputparser.NewJSON[testAnswer]()
+	require.NoError(t, err)
+
+	llm := &testLanguageModel{expResult: `{"answer": "Paris"}`}
+	c := NewLLMChain(llm, prompts.NewPromptTemplate(
+		"What is the capital of {{.country}}?\n{{.format_instructions}}",
+		[]string{"country", "format_instructions"},
+	))
+	c.OutputParser = outputparser.NewAny[testAnswer](parser)
+	c.RawOutputKey = "raw"
+
+	require.Equal(t, []string{"country"}, c.GetInputKeys())
+	require.Equal(t, []string{"text", "raw"}, c.GetOutputKeys())
+
+	result, err := Call(context.Background(), c, map[string]any{"country": "France"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{
+		"text": testAnswer{Answer: "Paris"},
+		"raw":  `{"answer": "Paris"}`,
+	}, result)
+	require.True(t, strings.Contains(llm.recordedPrompt[0].String(), parser.GetFormatInstructions()))
+}
+
+func TestLLMChainParseError(t *testing.T) {
+	t.Parallel()
+
+	llm := &testLanguageModel{expResult: "Paris"}
+	c := NewLLMChain(llm, prompts.NewPromptTemplate("Capital of {{.country}}?", []string{"country"}))
+	c.OutputParser = outputparser.NewBooleanParser()
+
+	_, err := Call(context.Background(), c, map[string]any{"country": "France"})
+	require.ErrorIs(t, err, ErrOutputParsing)
+
+	c.ReturnRawOnParseError = true
+	result, err := Call(context.Background(), c, map[string]any{"country": "France"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"text": "Paris"}, result)
+
+	// Errors other than parse errors are not replaced by the raw output.
+	c.OutputParser = failingParser{}
+	_, err = Call(context.Background(), c, map[string]any{"country": "France"})
+	require.ErrorIs(t, err, errParserFailed)
+}
+
+var errParserFailed = errors.New("parser failed")
+
+type failingParser struct {
+	outputparser.Simple
+}
+
+func (failingParser) ParseWithPrompt(string, schema.PromptValue) (any, error) {
+	return nil, errParserFailed
+}
+
+func TestLLMChainFormatInstructionsWithoutParser(t *testing.T) {
+	t.Parallel()
+
+	llm := &testLanguageModel{expResult: "Paris"}
+	c := NewLLMChain(llm, prompts.NewPromptTemplate(
+		"Capital of {{.country}}? {{.format_instructions}}",
+		[]string{"country", "format_instructions"},
+	))
+	require.Equal(t, []string{"country", "format_instructions"}, c.GetInputKeys())
+
+	withoutParser := *c
+	withoutParser.OutputParser = nil
+	require.Equal(t, []string{"country", "format_instructions"}, withoutParser.GetInputKeys())
+
+	_, err := Call(context.Background(), c, map[string]any{
+		"country":             "France",
+		"format_instructions": "Answer in one word.",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Capital of France? Answer in one word.", llm.recordedPrompt[0].String())
+}
@@ -0,0 +1,9 @@
+// Package indexing keeps a vector store in sync with the documents of a source without
+// rewriting it on every run.
+//
+// Every document is identified by a hash of its content and metadata. A RecordManager
+// remembers the hashes written to the vector store, and the source each document comes
+// from, so Index only adds the documents that changed since the previous run and deletes
+// the documents that are no longer produced by their source. Records can be kept in memory,
+// with InMemoryRecordManager, or in a SQLite database, with SQLiteRecordManager.
+package indexing
@@ -0,0 +1,230 @@
+package indexing
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tmc/langchaingo/documentloaders"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+var (
+	// ErrUnsupportedVectorStore is returned when the vector store can not store documents
+	// under chosen ids or delete them, see vectorstores.Upserter and vectorstores.IDDeleter.
+	ErrUnsupportedVectorStore = errors.New("vector store does not support upserting and deleting by id")
+	// ErrMissingSource is returned when a document has no source and the cleanup is incremental.
+	ErrMissingSource = errors.New("document has no source")
+	// ErrUnknownCleanup is returned when the cleanup is not one of the Cleanup constants.
+	ErrUnknownCleanup = errors.New("unknown cleanup")
+	// ErrMismatchedSources is returned when the number of sources does not match the number of keys.
+	ErrMismatchedSources = errors.New("number of sources does not match number of keys")
+	// ErrInvalidTableName is returned when the table name of a record manager is not a valid identifier.
+	ErrInvalidTableName = errors.New("invalid table name")
+)
+
+// Result is the outcome of indexing documents.
+type Result struct {
+	// NumAdded is the number of documents written to the vector store.
+	NumAdded int
+	// NumSkipped is the number of documents already in the vector store.
+	NumSkipped int
+	// NumDeleted is the number of stale documents deleted from the vector store.
+	NumDeleted int
+}
+
+// vectorStore is a vector store documents can be upserted to and deleted from by id.
+type vectorStore interface {
+	vectorstores.Upserter
+	vectorstores.IDDeleter
+}
+
+// Index loads the documents of the loader and indexes them, see IndexDocuments. The documents
+// are split with the text splitter set with WithTextSplitter, if any.
+func Index(
+	ctx context.Context,
+	loader documentloaders.Loader,
+	recordManager RecordManager,
+	store vectorstores.VectorStore,
+	options ...Option,
+) (Result, error) {
+	opts := applyOptions(options...)
+
+	var docs []schema.Document
+	var err error
+	if opts.TextSplitter != nil {
+		docs, err = loader.LoadAndSplit(ctx, opts.TextSplitter)
+	} else {
+		docs, err = loader.Load(ctx)
+	}
+	if err != nil {
+		return Result{}, err
+	}
+
+	return IndexDocuments(ctx, docs, recordManager, store, options...)
+}
+
+// IndexDocuments writes the documents that are not yet in the vector store and deletes the
+// stale documents, according to the cleanup set with WithCleanup. Documents are identified by
+// a hash of their content and metadata, which is used as their id in the vector store, and
+// their source is read from the metadata key set with WithSourceKey. The vector store must
+// implement vectorstores.Upserter and vectorstores.IDDeleter.
+func IndexDocuments(
+	ctx context.Context,
+	docs []schema.Document,
+	recordManager RecordManager,
+	store vectorstores.VectorStore,
+	options ...Option,
+) (Result, error) {
+	opts := applyOptions(options...)
+
+	s, ok := store.(vectorStore)
+	if !ok {
+		return Result{}, ErrUnsupportedVectorStore
+	}
+	if opts.Cleanup != CleanupIncremental && opts.Cleanup != CleanupFull && opts.Cleanup != CleanupNone {
+		return Result{}, fmt.Errorf("%w: %q", ErrUnknownCleanup, opts.Cleanup)
+	}
+
+	idx := &indexer{store: s, recordManager: recordManager, opts: opts, indexStart: time.Now()}
+	seenKeys := make(map[string]bool, len(docs))
+	seenSources := make(map[string]bool)
+	sourceList := make([]string, 0)
+
+	for start := 0; start < len(docs); start += opts.BatchSize {
+		end := start + opts.BatchSize
+		if end > len(docs) {
+			end = len(docs)
+		}
+
+		keys := make([]string, 0, end-start)
+		sources := make([]string, 0, end-start)
+		batch := make([]schema.Document, 0, end-start)
+		for _, doc := range docs[start:end] {
+			key, err := hashDocument(doc)
+			if err != nil {
+				return idx.result, err
+			}
+			if seenKeys[key] {
+				idx.result.NumSkipped++
+				continue
+			}
+			seenKeys[key] = true
+
+			source, _ := doc.Metadata[opts.SourceKey].(string)
+			if source == "" && opts.Cleanup == CleanupIncremental {
+				return idx.result, fmt.Errorf("%w: metadata key %q is not set", ErrMissingSource, opts.SourceKey)
+			}
+			if !seenSources[source] {
+				seenSources[source] = true
+				sourceList = append(sourceList, source)
+			}
+
+			keys = append(keys, key)
+			sources = append(sources, source)
+			batch = append(batch, doc)
+		}
+
+		if err := idx.indexBatch(ctx, keys, sources, batch); err != nil {
+			return idx.result, err
+		}
+	}
+
+	var err error
+	switch opts.Cleanup {
+	case CleanupIncremental:
+		if len(sourceList) > 0 {
+			err = idx.cleanup(ctx, sourceList)
+		}
+	case CleanupFull:
+		err = idx.cleanup(ctx, nil)
+	case CleanupNone:
+	}
+
+	return idx.result, err
+}
+
+// indexer holds the state of a call to IndexDocuments.
+type indexer struct {
+	store         vectorStore
+	recordManager RecordManager
+	opts          Options
+	// indexStart is the time every document indexed is recorded with. Records written
+	// before are stale unless they are written again.
+	indexStart time.Time
+	result     Result
+}
+
+// indexBatch writes the documents of the batch that are not recorded yet and records all of them.
+func (idx *indexer) indexBatch(ctx context.Context, keys []string, sources []string, docs []schema.Document) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	exists, err := idx.recordManager.Exists(ctx, keys)
+	if err != nil {
+		return err
+	}
+
+	newKeys := make([]string, 0, len(keys))
+	newDocs := make([]schema.Document, 0, len(keys))
+	for i, key := range keys {
+		if exists[i] {
+			idx.result.NumSkipped++
+			continue
+		}
+		newKeys = append(newKeys, key)
+		newDocs = append(newDocs, docs[i])
+	}
+
+	if len(newDocs) > 0 {
+		if err := idx.store.UpsertDocuments(ctx, newKeys, newDocs, idx.opts.VectorStoreOptions...); err != nil {
+			return err
+		}
+		idx.result.NumAdded += len(newDocs)
+	}
+
+	return idx.recordManager.Update(ctx, keys, sources, idx.indexStart)
+}
+
+// cleanup deletes the documents of the sources, or of all sources if nil, that were not
+// indexed again.
+func (idx *indexer) cleanup(ctx context.Context, sources []string) error {
+	staleKeys, err := idx.recordManager.ListKeys(ctx, sources, idx.indexStart)
+	if err != nil {
+		return err
+	}
+	if len(staleKeys) == 0 {
+		return nil
+	}
+
+	if err := idx.store.DeleteDocumentsByID(ctx, staleKeys, idx.opts.VectorStoreOptions...); err != nil {
+		return err
+	}
+	if err := idx.recordManager.DeleteKeys(ctx, staleKeys); err != nil {
+		return err
+	}
+	idx.result.NumDeleted = len(staleKeys)
+
+	return nil
+}
+
+// hashDocument returns the hex encoded SHA-256 hash of the content and metadata of the
+// document. Metadata keys are sorted when encoded, so the hash does not depend on their order.
+func hashDocument(doc schema.Document) (string, error) {
+	data, err := json.Marshal(struct {
+		PageContent string         `json:"page_content"`
+		Metadata    map[string]any `json:"metadata"`
+	}{doc.PageContent, doc.Metadata})
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:]), nil
+}
@@ -0,0 +1,201 @@
+package indexing_test
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/documentloaders"
+	"github.com/tmc/langchaingo/indexing"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// testVectorStore stores the documents by id and records the upserted ids.
+type testVectorStore struct {
+	docs     map[string]schema.Document
+	upserted []string
+}
+
+var (
+	_ vectorstores.Upserter  = &testVectorStore{}
+	_ vectorstores.IDDeleter = &testVectorStore{}
+)
+
+func newTestVectorStore() *testVectorStore {
+	return &testVectorStore{docs: make(map[string]schema.Document)}
+}
+
+func (s *testVectorStore) AddDocuments(context.Context, []schema.Document, ...vectorstores.Option) error {
+	panic("documents must be added with ids")
+}
+
+func (s *testVectorStore) SimilaritySearch(
+	context.Context, string, int, ...vectorstores.Option,
+) ([]schema.Document, error) {
+	return nil, nil
+}
+
+func (s *testVectorStore) UpsertDocuments(
+	_ context.Context, ids []string, docs []schema.Document, _ ...vectorstores.Option,
+) error {
+	for i, id := range ids {
+		s.docs[id] = docs[i]
+	}
+	s.upserted = append(s.upserted, ids...)
+	return nil
+}
+
+func (s *testVectorStore) DeleteDocumentsByID(_ context.Context, ids []string, _ ...vectorstores.Option) error {
+	for _, id := range ids {
+		delete(s.docs, id)
+	}
+	return nil
+}
+
+func (s *testVectorStore) contents() []string {
+	contents := make([]string, 0, len(s.docs))
+	for _, doc := range s.docs {
+		contents = append(contents, doc.PageContent)
+	}
+	sort.Strings(contents)
+	return contents
+}
+
+func doc(content, source string) schema.Document {
+	return schema.Document{PageContent: content, Metadata: map[string]any{"source": source}}
+}
+
+func TestIndexDocumentsIncremental(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	store := newTestVectorStore()
+	recordManager := indexing.NewInMemoryRecordManager("test")
+
+	result, err := indexing.IndexDocuments(ctx, []schema.Document{
+		doc("a1", "a"), doc("a2", "a"), doc("b1", "b"), doc("b1", "b"),
+	}, recordManager, store, indexing.WithBatchSize(2))
+	require.NoError(t, err)
+	assert.Equal(t, indexing.Result{NumAdded: 3, NumSkipped: 1}, result)
+	assert.Equal(t, []string{"a1", "a2", "b1"}, store.contents())
+
+	// Re-indexing the same documents writes nothing.
+	store.upserted = nil
+	result, err = indexing.IndexDocuments(ctx, []schema.Document{
+		doc("a1", "a"), doc("a2", "a"), doc("b1", "b"),
+	}, recordManager, store)
+	require.NoError(t, err)
+	assert.Equal(t, indexing.Result{NumSkipped: 3}, result)
+	assert.Empty(t, store.upserted)
+
+	// Only the changed document of source a is written and its stale document deleted.
+	// Source b is not indexed, so its documents are kept.
+	result, err = indexing.IndexDocuments(ctx, []schema.Document{
+		doc("a1", "a"), doc("a2 updated", "a"),
+	}, recordManager, store)
+	require.NoError(t, err)
+	assert.Equal(t, indexing.Result{NumAdded: 1, NumSkipped: 1, NumDeleted: 1}, result)
+	assert.Equal(t, []string{"a1", "a2 updated", "b1"}, store.contents())
+
+	_, err = indexing.IndexDocuments(ctx, []schema.Document{{PageContent: "no source"}}, recordManager, store)
+	require.ErrorIs(t, err, indexing.ErrMissingSource)
+}
+
+func TestIndexDocumentsFull(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	store := newTestVectorStore()
+	recordManager := indexing.NewInMemoryRecordManager("test")
+
+	_, err := indexing.IndexDocuments(ctx, []schema.Document{doc("a1", "a"), doc("b1", "b")},
+		recordManager, store, indexing.WithCleanup(indexing.CleanupFull))
+	require.NoError(t, err)
+
+	result, err := indexing.IndexDocuments(ctx, []schema.Document{{PageContent: "c1"}},
+		recordManager, store, indexing.WithCleanup(indexing.CleanupFull))
+	require.NoError(t, err)
+	assert.Equal(t, indexing.Result{NumAdded: 1, NumDeleted: 2}, result)
+	assert.Equal(t, []string{"c1"}, store.contents())
+
+	result, err = indexing.IndexDocuments(ctx, []schema.Document{{PageContent: "d1"}},
+		recordManager, store, indexing.WithCleanup(indexing.CleanupNone))
+	require.NoError(t, err)
+	assert.Equal(t, indexing.Result{NumAdded: 1}, result)
+	assert.Equal(t, []string{"c1", "d1"}, store.contents())
+}
+
+func TestIndexDocumentsMetadataChange(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	store := newTestVectorStore()
+	recordManager := indexing.NewInMemoryRecordManager("test")
+
+	_, err := indexing.IndexDocuments(ctx, []schema.Document{doc("a1", "a")}, recordManager, store)
+	require.NoError(t, err)
+
+	changed := doc("a1", "a")
+	changed.Metadata["page"] = 2
+	result, err := indexing.IndexDocuments(ctx, []schema.Document{changed}, recordManager, store)
+	require.NoError(t, err)
+	assert.Equal(t, indexing.Result{NumAdded: 1, NumDeleted: 1}, result)
+	require.Len(t, store.docs, 1)
+}
+
+func TestIndexUnsupportedVectorStore(t *testing.T) {
+	t.Parallel()
+
+	_, err := indexing.IndexDocuments(context.Background(), nil, indexing.NewInMemoryRecordManager("test"),
+		unsupportedVectorStore{})
+	require.ErrorIs(t, err, indexing.ErrUnsupportedVectorStore)
+}
+
+type unsupportedVectorStore struct {
+	vectorstores.VectorStore
+}
+
+// sourceLoader loads the text of every source as a document with its source in the metadata.
+type sourceLoader map[string]string
+
+func (l sourceLoader) Load(ctx context.Context) ([]schema.Document, error) {
+	docs := make([]schema.Document, 0, len(l))
+	for source, text := range l {
+		loaded, err := documentloaders.NewText(strings.NewReader(text)).Load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range loaded {
+			d.Metadata["source"] = source
+			docs = append(docs, d)
+		}
+	}
+	return docs, nil
+}
+
+func (l sourceLoader) LoadAndSplit(ctx context.Context, splitter textsplitter.TextSplitter) ([]schema.Document, error) {
+	docs, err := l.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return textsplitter.SplitDocuments(splitter, docs)
+}
+
+func TestIndex(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	store := newTestVectorStore()
+	splitter := textsplitter.NewRecursiveCharacter(textsplitter.WithChunkSize(12), textsplitter.WithChunkOverlap(0))
+
+	result, err := indexing.Index(ctx, sourceLoader{"wiki/go.md": "Go is fun.\n\nGo is fast."},
+		indexing.NewInMemoryRecordManager("test"), store, indexing.WithTextSplitter(splitter))
+	require.NoError(t, err)
+	assert.Equal(t, indexing.Result{NumAdded: 2}, result)
+	assert.Equal(t, []string{"Go is fast.", "Go is fun."}, store.contents())
+}
@@ -0,0 +1,92 @@
+package indexing
+
+import (
+	"github.com/tmc/langchaingo/textsplitter"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+const (
+	_defaultSourceKey = "source"
+	_defaultBatchSize = 100
+)
+
+// Cleanup is the way Index deletes the documents previously written to the vector store
+// that were not produced again.
+type Cleanup string
+
+const (
+	// CleanupIncremental deletes the previous documents of the sources of the indexed
+	// documents. Documents of other sources are kept, so sources can be indexed separately.
+	CleanupIncremental Cleanup = "incremental"
+	// CleanupFull deletes every previous document that was not indexed again, so the
+	// indexed documents must be the complete content of the vector store.
+	CleanupFull Cleanup = "full"
+	// CleanupNone never deletes documents.
+	CleanupNone Cleanup = "none"
+)
+
+// Options is a set of options for Index and IndexDocuments.
+type Options struct {
+	Cleanup            Cleanup
+	SourceKey          string
+	BatchSize          int
+	TextSplitter       textsplitter.TextSplitter
+	VectorStoreOptions []vectorstores.Option
+}
+
+// Option is a function that configures an Options.
+type Option func(*Options)
+
+// WithCleanup sets the way stale documents are deleted. It defaults to CleanupIncremental.
+func WithCleanup(cleanup Cleanup) Option {
+	return func(o *Options) {
+		o.Cleanup = cleanup
+	}
+}
+
+// WithSourceKey sets the metadata key of the source of the documents. It defaults to "source".
+func WithSourceKey(sourceKey string) Option {
+	return func(o *Options) {
+		o.SourceKey = sourceKey
+	}
+}
+
+// WithBatchSize sets the number of documents written to the vector store at once. It
+// defaults to 100.
+func WithBatchSize(batchSize int) Option {
+	return func(o *Options) {
+		o.BatchSize = batchSize
+	}
+}
+
+// WithTextSplitter sets the text splitter used by Index to split the loaded documents.
+func WithTextSplitter(textSplitter textsplitter.TextSplitter) Option {
+	return func(o *Options) {
+		o.TextSplitter = textSplitter
+	}
+}
+
+// WithVectorStoreOptions sets the options passed to the vector store, such as its name space.
+func WithVectorStoreOptions(options ...vectorstores.Option) Option {
+	return func(o *Options) {
+		o.VectorStoreOptions = options
+	}
+}
+
+func applyOptions(options ...Option) Options {
+	o := Options{
+		Cleanup:   CleanupIncremental,
+		SourceKey: _defaultSourceKey,
+		BatchSize: _defaultBatchSize,
+	}
+
+	for _, option := range options {
+		option(&o)
+	}
+
+	if o.BatchSize <= 0 {
+		o.BatchSize = _defaultBatchSize
+	}
+
+	return o
+}
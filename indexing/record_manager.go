@@ -0,0 +1,130 @@
+package indexing
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RecordManager records the keys of the documents written to a vector store, with the
+// source of every document and the time it was last written.
+type RecordManager interface {
+	// Exists returns, for every key, whether it is recorded.
+	Exists(ctx context.Context, keys []string) ([]bool, error)
+	// Update records the keys, with the source of every key, as written at the given time.
+	Update(ctx context.Context, keys []string, sources []string, updatedAt time.Time) error
+	// ListKeys returns the keys written before the given time. If sources is not nil, only
+	// the keys of these sources are returned.
+	ListKeys(ctx context.Context, sources []string, before time.Time) ([]string, error)
+	// DeleteKeys removes the keys.
+	DeleteKeys(ctx context.Context, keys []string) error
+}
+
+type record struct {
+	source    string
+	updatedAt time.Time
+}
+
+// recordKey identifies the record of a key in a namespace.
+type recordKey struct {
+	namespace string
+	key       string
+}
+
+// inMemoryRecords are the records of all namespaces of in-memory record managers.
+type inMemoryRecords struct {
+	records map[recordKey]record
+	mu      sync.RWMutex
+}
+
+// InMemoryRecordManager is a RecordManager keeping the records in memory. It is safe for
+// concurrent use. Like the records of SQLiteRecordManager, the records of every namespace,
+// typically one per vector store collection, are kept apart.
+type InMemoryRecordManager struct {
+	*inMemoryRecords
+	namespace string
+}
+
+// Statically assert that InMemoryRecordManager implement the record manager interface.
+var _ RecordManager = &InMemoryRecordManager{}
+
+// NewInMemoryRecordManager creates a new empty InMemoryRecordManager for the namespace.
+func NewInMemoryRecordManager(namespace string) *InMemoryRecordManager {
+	return &InMemoryRecordManager{
+		inMemoryRecords: &inMemoryRecords{records: make(map[recordKey]record)},
+		namespace:       namespace,
+	}
+}
+
+// ForNamespace returns a record manager for another namespace, sharing the records of the
+// record manager.
+func (m *InMemoryRecordManager) ForNamespace(namespace string) *InMemoryRecordManager {
+	return &InMemoryRecordManager{inMemoryRecords: m.inMemoryRecords, namespace: namespace}
+}
+
+// Exists returns, for every key, whether it is recorded.
+func (m *InMemoryRecordManager) Exists(_ context.Context, keys []string) ([]bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	exists := make([]bool, len(keys))
+	for i, key := range keys {
+		_, exists[i] = m.records[recordKey{namespace: m.namespace, key: key}]
+	}
+	return exists, nil
+}
+
+// Update records the keys as written at the given time.
+func (m *InMemoryRecordManager) Update(
+	_ context.Context, keys []string, sources []string, updatedAt time.Time,
+) error {
+	if len(keys) != len(sources) {
+		return ErrMismatchedSources
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, key := range keys {
+		m.records[recordKey{namespace: m.namespace, key: key}] = record{source: sources[i], updatedAt: updatedAt}
+	}
+	return nil
+}
+
+// ListKeys returns the keys written before the given time, of the sources if not nil.
+func (m *InMemoryRecordManager) ListKeys(
+	_ context.Context, sources []string, before time.Time,
+) ([]string, error) {
+	var sourceSet map[string]bool
+	if sources != nil {
+		sourceSet = make(map[string]bool, len(sources))
+		for _, source := range sources {
+			sourceSet[source] = true
+		}
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	keys := make([]string, 0)
+	for key, r := range m.records {
+		if key.namespace != m.namespace {
+			continue
+		}
+		if r.updatedAt.Before(before) && (sourceSet == nil || sourceSet[r.source]) {
+			keys = append(keys, key.key)
+		}
+	}
+	return keys, nil
+}
+
+// DeleteKeys removes the keys.
+func (m *InMemoryRecordManager) DeleteKeys(_ context.Context, keys []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, key := range keys {
+		delete(m.records, recordKey{namespace: m.namespace, key: key})
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package indexing
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3" // sqlite3 driver
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testRecordManager checks the behavior common to all record managers.
+func testRecordManager(t *testing.T, m RecordManager) {
+	t.Helper()
+	ctx := context.Background()
+
+	first := time.Now()
+	second := first.Add(time.Second)
+
+	require.NoError(t, m.Update(ctx, []string{"a1", "a2", "b1"}, []string{"a", "a", "b"}, first))
+	require.NoError(t, m.Update(ctx, []string{"a1"}, []string{"a"}, second))
+	require.ErrorIs(t, m.Update(ctx, []string{"c1"}, nil, second), ErrMismatchedSources)
+
+	exists, err := m.Exists(ctx, []string{"a1", "c1", "b1"})
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true, false, true}, exists)
+
+	keys, err := m.ListKeys(ctx, []string{"a"}, second)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a2"}, keys)
+
+	keys, err = m.ListKeys(ctx, nil, second)
+	require.NoError(t, err)
+	sort.Strings(keys)
+	assert.Equal(t, []string{"a2", "b1"}, keys)
+
+	keys, err = m.ListKeys(ctx, []string{}, second)
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	require.NoError(t, m.DeleteKeys(ctx, []string{"a2", "b1"}))
+	keys, err = m.ListKeys(ctx, nil, second.Add(time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a1"}, keys)
+}
+
+func TestInMemoryRecordManager(t *testing.T) {
+	t.Parallel()
+	m := NewInMemoryRecordManager("wiki")
+	testRecordManager(t, m)
+
+	// Namespaces are isolated from each other.
+	other := m.ForNamespace("other")
+	exists, err := other.Exists(context.Background(), []string{"a1"})
+	require.NoError(t, err)
+	assert.Equal(t, []bool{false}, exists)
+	keys, err := other.ListKeys(context.Background(), nil, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+}
+
+func TestSQLiteRecordManager(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	m, err := NewSQLiteRecordManager(ctx, db, "wiki")
+	require.NoError(t, err)
+	testRecordManager(t, m)
+
+	// Namespaces are isolated from each other.
+	other, err := NewSQLiteRecordManager(ctx, db, "other")
+	require.NoError(t, err)
+	exists, err := other.Exists(ctx, []string{"a1"})
+	require.NoError(t, err)
+	assert.Equal(t, []bool{false}, exists)
+
+	_, err = NewSQLiteRecordManager(ctx, db, "wiki", WithTableName("records; DROP TABLE x"))
+	require.ErrorIs(t, err, ErrInvalidTableName)
+}
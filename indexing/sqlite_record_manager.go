@@ -0,0 +1,220 @@
+package indexing
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	_defaultSQLiteTableName = "langchaingo_index_records"
+	// _maxSQLiteVariables is the number of values bound in a single statement, below the
+	// default limit of SQLite.
+	_maxSQLiteVariables = 500
+)
+
+// nolint:gochecknoglobals
+var _sqlIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// SQLiteRecordManager is a RecordManager storing the records in a SQLite database, e.g.
+// opened with the github.com/mattn/go-sqlite3 driver. The records of every namespace,
+// typically one per vector store collection, are stored in the same table.
+type SQLiteRecordManager struct {
+	db        *sql.DB
+	namespace string
+	tableName string
+}
+
+// Statically assert that SQLiteRecordManager implement the record manager interface.
+var _ RecordManager = &SQLiteRecordManager{}
+
+// SQLiteRecordManagerOption is a function for creating a new SQLite record manager with
+// other than the default values.
+type SQLiteRecordManagerOption func(m *SQLiteRecordManager)
+
+// WithTableName is an option for setting the name of the table the records are stored in.
+// It defaults to "langchaingo_index_records".
+func WithTableName(tableName string) SQLiteRecordManagerOption {
+	return func(m *SQLiteRecordManager) {
+		m.tableName = tableName
+	}
+}
+
+// NewSQLiteRecordManager creates a record manager for the namespace stored in the database.
+// The table is created if it does not exist.
+func NewSQLiteRecordManager(
+	ctx context.Context,
+	db *sql.DB,
+	namespace string,
+	options ...SQLiteRecordManagerOption,
+) (*SQLiteRecordManager, error) {
+	m := &SQLiteRecordManager{
+		db:        db,
+		namespace: namespace,
+		tableName: _defaultSQLiteTableName,
+	}
+
+	for _, option := range options {
+		option(m)
+	}
+
+	if !_sqlIdentifier.MatchString(m.tableName) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidTableName, m.tableName)
+	}
+
+	statements := []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	namespace TEXT NOT NULL,
+	key TEXT NOT NULL,
+	source TEXT NOT NULL,
+	updated_at INTEGER NOT NULL,
+	PRIMARY KEY (namespace, key)
+)`, m.tableName),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %[1]s_source ON %[1]s (namespace, source)`, m.tableName),
+	}
+	for _, statement := range statements {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// Exists returns, for every key, whether it is recorded.
+func (m *SQLiteRecordManager) Exists(ctx context.Context, keys []string) ([]bool, error) {
+	found := make(map[string]bool, len(keys))
+	err := inBatches(keys, func(batch []string) error {
+		args := append([]any{m.namespace}, toArgs(batch)...)
+		rows, err := m.db.QueryContext(ctx, fmt.Sprintf(
+			"SELECT key FROM %s WHERE namespace = ? AND key IN (%s)",
+			m.tableName, placeholders(len(batch)),
+		), args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var key string
+			if err := rows.Scan(&key); err != nil {
+				return err
+			}
+			found[key] = true
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	exists := make([]bool, len(keys))
+	for i, key := range keys {
+		exists[i] = found[key]
+	}
+	return exists, nil
+}
+
+// Update records the keys as written at the given time, in a single transaction.
+func (m *SQLiteRecordManager) Update(
+	ctx context.Context, keys []string, sources []string, updatedAt time.Time,
+) error {
+	if len(keys) != len(sources) {
+		return ErrMismatchedSources
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	statement := fmt.Sprintf(
+		`INSERT INTO %s (namespace, key, source, updated_at) VALUES (?, ?, ?, ?)
+ON CONFLICT (namespace, key) DO UPDATE SET source = excluded.source, updated_at = excluded.updated_at`,
+		m.tableName,
+	)
+	for i, key := range keys {
+		if _, err := tx.ExecContext(ctx, statement, m.namespace, key, sources[i], updatedAt.UnixNano()); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ListKeys returns the keys written before the given time, of the sources if not nil.
+func (m *SQLiteRecordManager) ListKeys(
+	ctx context.Context, sources []string, before time.Time,
+) ([]string, error) {
+	keys := make([]string, 0)
+	query := func(condition string, args ...any) error {
+		rows, err := m.db.QueryContext(ctx, fmt.Sprintf(
+			"SELECT key FROM %s WHERE namespace = ? AND updated_at < ?%s",
+			m.tableName, condition,
+		), append([]any{m.namespace, before.UnixNano()}, args...)...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var key string
+			if err := rows.Scan(&key); err != nil {
+				return err
+			}
+			keys = append(keys, key)
+		}
+		return rows.Err()
+	}
+
+	if sources == nil {
+		return keys, query("")
+	}
+
+	err := inBatches(sources, func(batch []string) error {
+		return query(fmt.Sprintf(" AND source IN (%s)", placeholders(len(batch))), toArgs(batch)...)
+	})
+	return keys, err
+}
+
+// DeleteKeys removes the keys.
+func (m *SQLiteRecordManager) DeleteKeys(ctx context.Context, keys []string) error {
+	return inBatches(keys, func(batch []string) error {
+		args := append([]any{m.namespace}, toArgs(batch)...)
+		_, err := m.db.ExecContext(ctx, fmt.Sprintf(
+			"DELETE FROM %s WHERE namespace = ? AND key IN (%s)",
+			m.tableName, placeholders(len(batch)),
+		), args...)
+		return err
+	})
+}
+
+// inBatches calls f with consecutive batches of values small enough to be bound in a statement.
+func inBatches(values []string, f func(batch []string) error) error {
+	for start := 0; start < len(values); start += _maxSQLiteVariables {
+		end := start + _maxSQLiteVariables
+		if end > len(values) {
+			end = len(values)
+		}
+		if err := f(values[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+func toArgs(values []string) []any {
+	args := make([]any, 0, len(values))
+	for _, value := range values {
+		args = append(args, value)
+	}
+	return args
+}
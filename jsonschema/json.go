@@ -1,5 +1,7 @@
 // Package jsonschema provides very simple functionality for representing a JSON schema as a
 // (nested) struct. This struct can be used with the chat completion "function call" feature.
+// A Definition can also be derived from a Go type and its struct tags with Reflect, and
+// decoded JSON values can be checked against a Definition with Validate.
 // For more complicated schemas, it is recommended to use a dedicated JSON schema library
 // and/or pass in the schema in []byte format.
 package jsonschema
@@ -34,6 +36,30 @@ type Definition struct {
 	Required []string `json:"required,omitempty"`
 	// Items specifies which data type an array contains, if the schema type is Array.
 	Items *Definition `json:"items,omitempty"`
+	// AdditionalProperties controls properties of an object not listed in Properties.
+	// It is either a bool, or a Definition (or *Definition) that additional property
+	// values must match. If nil, any additional property is allowed.
+	AdditionalProperties any `json:"additionalProperties,omitempty"`
+
+	// Format is a semantic format of a string value, e.g. "date-time" or "email".
+	Format string `json:"format,omitempty"`
+	// Pattern is a regular expression a string value must match.
+	Pattern string `json:"pattern,omitempty"`
+	// MinLength is the minimum length of a string value.
+	MinLength *int `json:"minLength,omitempty"`
+	// MaxLength is the maximum length of a string value.
+	MaxLength *int `json:"maxLength,omitempty"`
+	// Minimum is the inclusive lower bound of a numeric value.
+	Minimum *float64 `json:"minimum,omitempty"`
+	// Maximum is the inclusive upper bound of a numeric value.
+	Maximum *float64 `json:"maximum,omitempty"`
+	// MinItems is the minimum number of items of an array value.
+	MinItems *int `json:"minItems,omitempty"`
+	// MaxItems is the maximum number of items of an array value.
+	MaxItems *int `json:"maxItems,omitempty"`
+	// Nullable allows null in addition to values of Type. The type is then marshaled as
+	// [Type, "null"].
+	Nullable bool `json:"-"`
 }
 
 func (d Definition) MarshalJSON() ([]byte, error) {
@@ -41,6 +67,15 @@ func (d Definition) MarshalJSON() ([]byte, error) {
 		d.Properties = make(map[string]Definition)
 	}
 	type Alias Definition
+	if d.Nullable && d.Type != "" && d.Type != Null {
+		return json.Marshal(struct {
+			Alias
+			Type []DataType `json:"type"`
+		}{
+			Alias: (Alias)(d),
+			Type:  []DataType{d.Type, Null},
+		})
+	}
 	return json.Marshal(struct {
 		Alias
 	}{
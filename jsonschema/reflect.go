@@ -0,0 +1,361 @@
+package jsonschema
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrUnsupportedType is returned when a Go type cannot be represented as a JSON schema.
+	ErrUnsupportedType = errors.New("unsupported type")
+	// ErrRecursiveType is returned when a Go type refers to itself, directly or indirectly.
+	ErrRecursiveType = errors.New("recursive type")
+)
+
+const (
+	_tagJSON        = "json"
+	_tagDescription = "description"
+	_tagEnum        = "enum"
+	_tagRequired    = "required"
+	_tagFormat      = "format"
+	_tagPattern     = "pattern"
+	_tagMinimum     = "minimum"
+	_tagMaximum     = "maximum"
+)
+
+// nolint:gochecknoglobals
+var _timeType = reflect.TypeOf(time.Time{})
+
+// Reflect builds a Definition from the type of v. Struct fields are named by their
+// `json` tag and can be annotated with the following tags:
+//
+//   - description: the description of the property.
+//   - enum: a comma separated list of allowed values.
+//   - required: "true" or "false". If not set, a field is required unless its
+//     `json` tag has the omitempty option.
+//   - format: the format of a string property, e.g. "email".
+//   - pattern: a regular expression a string property must match.
+//   - minimum and maximum: the inclusive bounds of a numeric property.
+//
+// Fields tagged with `json:"-"` and unexported fields are ignored. The fields of
+// embedded structs without a json name are promoted to the parent object, following the
+// rules of encoding/json when several fields have the same name. Pointer, slice and map
+// fields, which encoding/json encodes as null when nil, are nullable.
+func Reflect(v any) (*Definition, error) {
+	if v == nil {
+		return nil, fmt.Errorf("%w: nil", ErrUnsupportedType)
+	}
+	return ReflectType(reflect.TypeOf(v))
+}
+
+// ReflectType builds a Definition from a Go type. See Reflect for the supported tags.
+func ReflectType(t reflect.Type) (*Definition, error) {
+	r := reflector{visiting: make(map[reflect.Type]bool)}
+	d, err := r.reflect(t)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+type reflector struct {
+	visiting map[reflect.Type]bool
+}
+
+func (r reflector) reflect(t reflect.Type) (Definition, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == _timeType {
+		return Definition{Type: String, Format: "date-time"}, nil
+	}
+
+	switch t.Kind() { //nolint:exhaustive
+	case reflect.Bool:
+		return Definition{Type: Boolean}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Definition{Type: Integer}, nil
+	case reflect.Float32, reflect.Float64:
+		return Definition{Type: Number}, nil
+	case reflect.String:
+		return Definition{Type: String}, nil
+	case reflect.Slice, reflect.Array:
+		// []byte is encoded as a base64 string by encoding/json.
+		if t.Elem().Kind() == reflect.Uint8 && t.Kind() == reflect.Slice {
+			return Definition{Type: String}, nil
+		}
+		items, err := r.reflectNullable(t.Elem())
+		if err != nil {
+			return Definition{}, err
+		}
+		return Definition{Type: Array, Items: &items}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return Definition{}, fmt.Errorf("%w: map with %s keys", ErrUnsupportedType, t.Key())
+		}
+		values, err := r.reflectNullable(t.Elem())
+		if err != nil {
+			return Definition{}, err
+		}
+		if values.Type == "" {
+			// Values of any type are allowed.
+			return Definition{Type: Object}, nil
+		}
+		return Definition{Type: Object, AdditionalProperties: &values}, nil
+	case reflect.Interface:
+		return Definition{}, nil
+	case reflect.Struct:
+		return r.reflectStruct(t)
+	default:
+		return Definition{}, fmt.Errorf("%w: %s", ErrUnsupportedType, t)
+	}
+}
+
+func (r reflector) reflectStruct(t reflect.Type) (Definition, error) {
+	if r.visiting[t] {
+		return Definition{}, fmt.Errorf("%w: %s", ErrRecursiveType, t)
+	}
+	r.visiting[t] = true
+	defer delete(r.visiting, t)
+
+	d := Definition{
+		Type:       Object,
+		Properties: make(map[string]Definition),
+	}
+	if err := r.addFields(&d, t); err != nil {
+		return Definition{}, err
+	}
+	return d, nil
+}
+
+// reflectNullable builds the Definition of a type whose nil values encoding/json encodes as
+// null: a struct field, an array item or a map value.
+func (r reflector) reflectNullable(t reflect.Type) (Definition, error) {
+	d, err := r.reflect(t)
+	if err != nil {
+		return Definition{}, err
+	}
+	switch t.Kind() { //nolint:exhaustive
+	case reflect.Pointer, reflect.Slice, reflect.Map:
+		d.Nullable = d.Type != ""
+	}
+	return d, nil
+}
+
+// structField is a field of a struct, or of a struct embedded in it, encoded by encoding/json.
+type structField struct {
+	reflect.StructField
+	name      string
+	tagged    bool
+	omitEmpty bool
+	depth     int
+}
+
+// addFields adds the properties of the struct type t to the definition d.
+func (r reflector) addFields(d *Definition, t reflect.Type) error {
+	var fields []structField
+	if err := r.collectFields(t, 0, &fields); err != nil {
+		return err
+	}
+
+	for _, field := range dominantFields(fields) {
+		property, err := r.reflectNullable(field.Type)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		if err := applyTags(&property, field.Tag); err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+
+		d.Properties[field.name] = property
+		if isRequired(field.StructField, field.omitEmpty) {
+			d.Required = append(d.Required, field.name)
+		}
+	}
+
+	return nil
+}
+
+// collectFields appends the fields of the struct type t and of the structs embedded in it,
+// in the order of encoding/json.
+func (r reflector) collectFields(t reflect.Type, depth int, fields *[]structField) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name, omitEmpty, skip := parseJSONTag(field)
+		if skip {
+			continue
+		}
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if err := r.collectEmbeddedFields(embedded, depth+1, fields); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+		structField := structField{
+			StructField: field,
+			name:        name,
+			tagged:      name != "",
+			omitEmpty:   omitEmpty,
+			depth:       depth,
+		}
+		if name == "" {
+			structField.name = field.Name
+		}
+		*fields = append(*fields, structField)
+	}
+
+	return nil
+}
+
+func (r reflector) collectEmbeddedFields(t reflect.Type, depth int, fields *[]structField) error {
+	if r.visiting[t] {
+		return fmt.Errorf("%w: %s", ErrRecursiveType, t)
+	}
+	r.visiting[t] = true
+	defer delete(r.visiting, t)
+
+	return r.collectFields(t, depth, fields)
+}
+
+// dominantFields returns the fields encoded by encoding/json among fields with the same
+// name: the least nested one, or the only tagged one among the least nested. Names without
+// such a field are not encoded. The fields keep their order.
+func dominantFields(fields []structField) []structField {
+	byName := make(map[string][]int)
+	for i, field := range fields {
+		byName[field.name] = append(byName[field.name], i)
+	}
+
+	dominant := make([]structField, 0, len(byName))
+	for i, field := range fields {
+		if dominantField(fields, byName[field.name]) == i {
+			dominant = append(dominant, field)
+		}
+	}
+	return dominant
+}
+
+// dominantField returns the index of the dominant field among the fields with the given
+// indexes, or -1 if there is none.
+func dominantField(fields []structField, indexes []int) int {
+	depth := fields[indexes[0]].depth
+	for _, i := range indexes[1:] {
+		if fields[i].depth < depth {
+			depth = fields[i].depth
+		}
+	}
+
+	shallowest, tagged := -1, -1
+	numShallowest, numTagged := 0, 0
+	for _, i := range indexes {
+		if fields[i].depth != depth {
+			continue
+		}
+		shallowest = i
+		numShallowest++
+		if fields[i].tagged {
+			tagged = i
+			numTagged++
+		}
+	}
+
+	switch {
+	case numShallowest == 1:
+		return shallowest
+	case numTagged == 1:
+		return tagged
+	default:
+		return -1
+	}
+}
+
+// applyTags sets the annotations found in the struct tag on the property.
+func applyTags(property *Definition, tag reflect.StructTag) error {
+	property.Description = tag.Get(_tagDescription)
+	if enum := tag.Get(_tagEnum); enum != "" {
+		property.Enum = splitAndTrim(enum)
+	}
+	if format := tag.Get(_tagFormat); format != "" {
+		property.Format = format
+	}
+	property.Pattern = tag.Get(_tagPattern)
+
+	var err error
+	if property.Minimum, err = parseBound(tag, _tagMinimum); err != nil {
+		return err
+	}
+	if property.Maximum, err = parseBound(tag, _tagMaximum); err != nil {
+		return err
+	}
+	return nil
+}
+
+func parseBound(tag reflect.StructTag, key string) (*float64, error) {
+	value, ok := tag.Lookup(key)
+	if !ok {
+		return nil, nil
+	}
+	bound, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s tag %q: %w", key, value, err)
+	}
+	return &bound, nil
+}
+
+// parseJSONTag returns the json name of a field, whether the omitempty option
+// is set and whether the field should be skipped.
+func parseJSONTag(field reflect.StructField) (string, bool, bool) {
+	tag, ok := field.Tag.Lookup(_tagJSON)
+	if !ok {
+		return "", false, false
+	}
+	if tag == "-" {
+		return "", false, true
+	}
+
+	parts := strings.Split(tag, ",")
+	omitEmpty := false
+	for _, option := range parts[1:] {
+		if option == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	return parts[0], omitEmpty, false
+}
+
+func isRequired(field reflect.StructField, omitEmpty bool) bool {
+	switch field.Tag.Get(_tagRequired) {
+	case "true":
+		return true
+	case "false":
+		return false
+	default:
+		return !omitEmpty
+	}
+}
+
+func splitAndTrim(s string) []string {
+	parts := strings.Split(s, ",")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	return parts
+}
@@ -0,0 +1,179 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	. "github.com/tmc/langchaingo/jsonschema"
+)
+
+type testAddress struct {
+	City    string `json:"city" description:"The city"`
+	Country string `json:"country,omitempty"`
+}
+
+type testBase struct {
+	ID string `json:"id"`
+}
+
+type testPerson struct {
+	testBase
+	Name     string            `json:"name" description:"The name of the person"`
+	Age      int               `json:"age,omitempty" minimum:"0" maximum:"150"`
+	Height   float64           `json:"height" required:"false"`
+	Unit     string            `json:"unit,omitempty" enum:"metric, imperial" required:"true"`
+	Address  *testAddress      `json:"address"`
+	Tags     []string          `json:"tags"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Active   bool              `json:"active"`
+	Email    string            `json:"email" format:"email" pattern:".+@example.com"`
+	Born     time.Time         `json:"born"`
+	Ignored  string            `json:"-"`
+	NoTag    string
+	internal string
+}
+
+func TestReflect(t *testing.T) {
+	t.Parallel()
+
+	got, err := Reflect(testPerson{})
+	require.NoError(t, err)
+
+	minAge, maxAge := 0.0, 150.0
+
+	want := &Definition{
+		Type: Object,
+		Properties: map[string]Definition{
+			"id":     {Type: String},
+			"name":   {Type: String, Description: "The name of the person"},
+			"age":    {Type: Integer, Minimum: &minAge, Maximum: &maxAge},
+			"height": {Type: Number},
+			"unit":   {Type: String, Enum: []string{"metric", "imperial"}},
+			"address": {
+				Type: Object,
+				Properties: map[string]Definition{
+					"city":    {Type: String, Description: "The city"},
+					"country": {Type: String},
+				},
+				Required: []string{"city"},
+				Nullable: true,
+			},
+			"tags":   {Type: Array, Items: &Definition{Type: String}, Nullable: true},
+			"labels": {Type: Object, AdditionalProperties: &Definition{Type: String}, Nullable: true},
+			"email":  {Type: String, Format: "email", Pattern: ".+@example.com"},
+			"born":   {Type: String, Format: "date-time"},
+			"active": {Type: Boolean},
+			"NoTag":  {Type: String},
+		},
+		Required: []string{"id", "name", "unit", "address", "tags", "active", "email", "born", "NoTag"},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestReflectPointer(t *testing.T) {
+	t.Parallel()
+
+	got, err := Reflect(&testAddress{})
+	require.NoError(t, err)
+	assert.Equal(t, Object, got.Type)
+	assert.Equal(t, []string{"city"}, got.Required)
+
+	got, err = Reflect([]int{})
+	require.NoError(t, err)
+	assert.Equal(t, &Definition{Type: Array, Items: &Definition{Type: Integer}}, got)
+}
+
+type testShadowed struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Other string `json:"other"`
+}
+
+type testTagged struct {
+	Name  string `json:"name"`
+	Other string `json:"other"`
+}
+
+type testUntagged struct {
+	Other string
+}
+
+type testShadowing struct {
+	testShadowed
+	*testTagged
+	testUntagged
+	ID string `json:"id"`
+}
+
+func TestReflectEmbeddedFields(t *testing.T) {
+	t.Parallel()
+
+	got, err := Reflect(testShadowing{})
+	require.NoError(t, err)
+	// The outer id shadows the embedded one, while name and other are ambiguous between two
+	// tagged fields at the same depth, like in encoding/json.
+	assert.Equal(t, &Definition{
+		Type: Object,
+		Properties: map[string]Definition{
+			"id":    {Type: String},
+			"Other": {Type: String},
+		},
+		Required: []string{"Other", "id"},
+	}, got)
+
+	data, err := json.Marshal(testShadowing{testTagged: &testTagged{}})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"Other": "", "id": ""}`, string(data))
+}
+
+func TestReflectNullableFields(t *testing.T) {
+	t.Parallel()
+
+	type value struct {
+		Address *testAddress      `json:"address"`
+		Tags    []*string         `json:"tags"`
+		Labels  map[string]string `json:"labels"`
+	}
+	def, err := Reflect(value{})
+	require.NoError(t, err)
+
+	// The nil fields Go encodes as null are valid.
+	data, err := json.Marshal(value{Tags: []*string{nil}})
+	require.NoError(t, err)
+	require.NoError(t, ValidateJSON(*def, data))
+
+	data, err = json.Marshal(def.Properties["tags"])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type": ["array", "null"], "properties": {},
+		"items": {"type": ["string", "null"], "properties": {}}}`, string(data))
+}
+
+type testNode struct {
+	Value    string      `json:"value"`
+	Children []*testNode `json:"children"`
+}
+
+func TestReflectErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := Reflect(testNode{})
+	assert.True(t, errors.Is(err, ErrRecursiveType))
+
+	_, err = Reflect(map[int]string{})
+	assert.True(t, errors.Is(err, ErrUnsupportedType))
+
+	_, err = Reflect(make(chan int))
+	assert.True(t, errors.Is(err, ErrUnsupportedType))
+
+	_, err = Reflect(nil)
+	assert.True(t, errors.Is(err, ErrUnsupportedType))
+
+	_, err = Reflect(struct {
+		N int `json:"n" minimum:"zero"`
+	}{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,328 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// ValidationError describes a single way in which a value does not match a Definition.
+type ValidationError struct {
+	// Path is the location of the invalid value, e.g. "$.user.tags[1]".
+	Path string
+	// Message describes what is wrong with the value.
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// ValidationErrors is the error returned by Validate. It contains every problem found.
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+// ErrTrailingData is returned by ValidateJSON when data holds more than one JSON value.
+var ErrTrailingData = errors.New("unexpected data after the JSON value")
+
+const _rootPath = "$"
+
+// nolint:gochecknoglobals
+var _uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// _patterns caches the compiled patterns of definitions, by pattern.
+var _patterns sync.Map // nolint:gochecknoglobals
+
+// compiledPattern is a compiled pattern, or the error compiling it.
+type compiledPattern struct {
+	re  *regexp.Regexp
+	err error
+}
+
+// compilePattern compiles a pattern, or returns it from the cache.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if compiled, ok := _patterns.Load(pattern); ok {
+		return compiled.(compiledPattern).re, compiled.(compiledPattern).err //nolint:forcetypeassert
+	}
+	re, err := regexp.Compile(pattern)
+	_patterns.Store(pattern, compiledPattern{re: re, err: err})
+	return re, err
+}
+
+// ValidateJSON decodes data, which must hold a single JSON value, and validates the result
+// against the definition. It can be used to check the arguments of a function call before
+// executing it.
+func ValidateJSON(def Definition, data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return err
+	}
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return ErrTrailingData
+	}
+	return Validate(def, value)
+}
+
+// Validate checks a decoded JSON value against the definition. The value is expected to
+// be made of the types produced by encoding/json when decoding into an any: map[string]any,
+// []any, string, float64 or json.Number, bool and nil. If the value does not match, the
+// returned error is of type ValidationErrors.
+func Validate(def Definition, value any) error {
+	v := validator{}
+	v.validate(def, value, _rootPath)
+	if len(v.errs) > 0 {
+		return v.errs
+	}
+	return nil
+}
+
+type validator struct {
+	errs ValidationErrors
+}
+
+func (v *validator) addError(path, format string, args ...any) {
+	v.errs = append(v.errs, ValidationError{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) validate(def Definition, value any, path string) {
+	if value == nil && def.Nullable {
+		return
+	}
+	if def.Type != "" && !matchesType(def.Type, value) {
+		v.addError(path, "expected %s, got %s", def.Type, typeOf(value))
+		return
+	}
+
+	if len(def.Enum) > 0 {
+		v.validateEnum(def, value, path)
+	}
+
+	switch value := value.(type) {
+	case map[string]any:
+		v.validateObject(def, value, path)
+	case []any:
+		v.validateArray(def, value, path)
+	case string:
+		v.validateString(def, value, path)
+	case float64, json.Number:
+		n, _ := toFloat(value)
+		v.validateNumber(def, n, path)
+	}
+}
+
+func (v *validator) validateEnum(def Definition, value any, path string) {
+	s, ok := enumString(value)
+	if ok {
+		for _, e := range def.Enum {
+			if e == s {
+				return
+			}
+		}
+	}
+	v.addError(path, "value %v is not one of %v", value, def.Enum)
+}
+
+func (v *validator) validateObject(def Definition, value map[string]any, path string) {
+	for _, key := range def.Required {
+		if _, ok := value[key]; !ok {
+			v.addError(path, "missing required property %q", key)
+		}
+	}
+
+	// Iterate in a stable order so errors are reported deterministically.
+	keys := make([]string, 0, len(value))
+	for key := range value {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		propertyPath := path + "." + key
+		if property, ok := def.Properties[key]; ok {
+			v.validate(property, value[key], propertyPath)
+			continue
+		}
+
+		switch additional := def.AdditionalProperties.(type) {
+		case bool:
+			if !additional {
+				v.addError(propertyPath, "additional property is not allowed")
+			}
+		case Definition:
+			v.validate(additional, value[key], propertyPath)
+		case *Definition:
+			if additional != nil {
+				v.validate(*additional, value[key], propertyPath)
+			}
+		}
+	}
+}
+
+func (v *validator) validateArray(def Definition, value []any, path string) {
+	if def.MinItems != nil && len(value) < *def.MinItems {
+		v.addError(path, "expected at least %d items, got %d", *def.MinItems, len(value))
+	}
+	if def.MaxItems != nil && len(value) > *def.MaxItems {
+		v.addError(path, "expected at most %d items, got %d", *def.MaxItems, len(value))
+	}
+
+	if def.Items == nil {
+		return
+	}
+	for i, item := range value {
+		v.validate(*def.Items, item, fmt.Sprintf("%s[%d]", path, i))
+	}
+}
+
+func (v *validator) validateString(def Definition, value string, path string) {
+	length := utf8.RuneCountInString(value)
+	if def.MinLength != nil && length < *def.MinLength {
+		v.addError(path, "expected a length of at least %d, got %d", *def.MinLength, length)
+	}
+	if def.MaxLength != nil && length > *def.MaxLength {
+		v.addError(path, "expected a length of at most %d, got %d", *def.MaxLength, length)
+	}
+
+	if def.Pattern != "" {
+		re, err := compilePattern(def.Pattern)
+		if err != nil {
+			v.addError(path, "invalid pattern %q: %s", def.Pattern, err)
+		} else if !re.MatchString(value) {
+			v.addError(path, "value %q does not match pattern %q", value, def.Pattern)
+		}
+	}
+
+	if def.Format != "" && !matchesFormat(def.Format, value) {
+		v.addError(path, "value %q is not a valid %s", value, def.Format)
+	}
+}
+
+func (v *validator) validateNumber(def Definition, value float64, path string) {
+	if def.Minimum != nil && value < *def.Minimum {
+		v.addError(path, "value %v is less than the minimum %v", value, *def.Minimum)
+	}
+	if def.Maximum != nil && value > *def.Maximum {
+		v.addError(path, "value %v is greater than the maximum %v", value, *def.Maximum)
+	}
+}
+
+func matchesType(t DataType, value any) bool {
+	switch t {
+	case Object:
+		_, ok := value.(map[string]any)
+		return ok
+	case Array:
+		_, ok := value.([]any)
+		return ok
+	case String:
+		_, ok := value.(string)
+		return ok
+	case Boolean:
+		_, ok := value.(bool)
+		return ok
+	case Null:
+		return value == nil
+	case Number:
+		_, ok := toFloat(value)
+		return ok
+	case Integer:
+		n, ok := toFloat(value)
+		return ok && n == math.Trunc(n)
+	default:
+		return true
+	}
+}
+
+func matchesFormat(format, value string) bool {
+	var err error
+	switch format {
+	case "date-time":
+		_, err = time.Parse(time.RFC3339, value)
+	case "date":
+		_, err = time.Parse(time.DateOnly, value)
+	case "time":
+		_, err = time.Parse(time.TimeOnly, value)
+	case "email":
+		_, err = mail.ParseAddress(value)
+	case "uri":
+		var u *url.URL
+		u, err = url.Parse(value)
+		if err == nil && !u.IsAbs() {
+			return false
+		}
+	case "uuid":
+		return _uuidPattern.MatchString(value)
+	}
+	// Unknown formats are treated as annotations and always match.
+	return err == nil
+}
+
+func toFloat(value any) (float64, bool) {
+	switch n := value.(type) {
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// enumString returns the string form of a value that is compared with the
+// values of an enum.
+func enumString(value any) (string, bool) {
+	switch value := value.(type) {
+	case string:
+		return value, true
+	case json.Number:
+		return value.String(), true
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(value), true
+	default:
+		return "", false
+	}
+}
+
+func typeOf(value any) string {
+	switch value.(type) {
+	case nil:
+		return string(Null)
+	case map[string]any:
+		return string(Object)
+	case []any:
+		return string(Array)
+	case string:
+		return string(String)
+	case bool:
+		return string(Boolean)
+	case float64, json.Number:
+		return string(Number)
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
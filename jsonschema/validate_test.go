@@ -0,0 +1,155 @@
+package jsonschema_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	. "github.com/tmc/langchaingo/jsonschema"
+)
+
+func intPtr(i int) *int           { return &i }
+func floatPtr(f float64) *float64 { return &f }
+
+func validationPaths(err error) []string {
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		return nil
+	}
+	paths := make([]string, 0, len(errs))
+	for _, e := range errs {
+		paths = append(paths, e.Path)
+	}
+	return paths
+}
+
+func TestValidateJSON(t *testing.T) { //nolint:funlen
+	t.Parallel()
+
+	def := Definition{
+		Type: Object,
+		Properties: map[string]Definition{
+			"location": {Type: String, MinLength: intPtr(2), MaxLength: intPtr(20)},
+			"unit":     {Type: String, Enum: []string{"celsius", "fahrenheit"}},
+			"days":     {Type: Integer, Minimum: floatPtr(1), Maximum: floatPtr(7)},
+			"email":    {Type: String, Format: "email"},
+			"code":     {Type: String, Pattern: `^[A-Z]{3}$`},
+			"tags": {
+				Type:     Array,
+				Items:    &Definition{Type: String},
+				MinItems: intPtr(1),
+				MaxItems: intPtr(2),
+			},
+			"user": {
+				Type: Object,
+				Properties: map[string]Definition{
+					"name": {Type: String},
+				},
+				Required:             []string{"name"},
+				AdditionalProperties: false,
+			},
+			"scores": {Type: Object, AdditionalProperties: &Definition{Type: Number}},
+		},
+		Required: []string{"location"},
+	}
+
+	tests := []struct {
+		name  string
+		data  string
+		paths []string
+	}{
+		{
+			name: "valid",
+			data: `{
+				"location": "Boston",
+				"unit": "celsius",
+				"days": 3,
+				"email": "jane@example.com",
+				"code": "BOS",
+				"tags": ["a"],
+				"user": {"name": "jane"},
+				"scores": {"a": 1.5}
+			}`,
+		},
+		{
+			name:  "missing required",
+			data:  `{"unit": "celsius"}`,
+			paths: []string{"$"},
+		},
+		{
+			name:  "wrong type",
+			data:  `{"location": 12}`,
+			paths: []string{"$.location"},
+		},
+		{
+			name:  "enum",
+			data:  `{"location": "Boston", "unit": "kelvin"}`,
+			paths: []string{"$.unit"},
+		},
+		{
+			name:  "not an integer",
+			data:  `{"location": "Boston", "days": 2.5}`,
+			paths: []string{"$.days"},
+		},
+		{
+			name:  "out of range",
+			data:  `{"location": "Boston", "days": 8}`,
+			paths: []string{"$.days"},
+		},
+		{
+			name:  "string length",
+			data:  `{"location": "B"}`,
+			paths: []string{"$.location"},
+		},
+		{
+			name:  "format and pattern",
+			data:  `{"location": "Boston", "email": "not an email", "code": "bos"}`,
+			paths: []string{"$.code", "$.email"},
+		},
+		{
+			name:  "array items",
+			data:  `{"location": "Boston", "tags": ["a", 1, "c"]}`,
+			paths: []string{"$.tags", "$.tags[1]"},
+		},
+		{
+			name:  "nested object",
+			data:  `{"location": "Boston", "user": {"age": 3}, "scores": {"a": "high"}}`,
+			paths: []string{"$.scores.a", "$.user", "$.user.age"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := ValidateJSON(def, []byte(tt.data))
+			if len(tt.paths) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Equal(t, tt.paths, validationPaths(err))
+		})
+	}
+}
+
+func TestValidateReflected(t *testing.T) {
+	t.Parallel()
+
+	def, err := Reflect(testAddress{})
+	require.NoError(t, err)
+
+	require.NoError(t, Validate(*def, map[string]any{"city": "Paris"}))
+
+	err = Validate(*def, map[string]any{"country": "France"})
+	require.Error(t, err)
+	assert.Equal(t, `validation failed: $: missing required property "city"`, err.Error())
+
+	assert.Error(t, ValidateJSON(*def, []byte(`{"city": `)))
+
+	// Only a single JSON value is valid.
+	require.NoError(t, ValidateJSON(*def, []byte("{\"city\": \"Paris\"}\n ")))
+	assert.ErrorIs(t, ValidateJSON(*def, []byte(`{"city": "Paris"} junk`)), ErrTrailingData)
+	assert.ErrorIs(t, ValidateJSON(*def, []byte(`{"city": "Paris"} {}`)), ErrTrailingData)
+}
@@ -0,0 +1,82 @@
+package llms
+
+import (
+	"github.com/tmc/langchaingo/schema"
+)
+
+const (
+	// _tokensPerMessage is the number of tokens chat models add to every message to
+	// delimit it and carry its role.
+	_tokensPerMessage = 3
+	// _tokensPerName is the number of extra tokens used when a message has a name.
+	_tokensPerName = 1
+	// _tokensPerReply is the number of tokens priming the reply of the model.
+	_tokensPerReply = 3
+)
+
+// TokenCounter is a function returning the number of tokens of a text, such as
+// LanguageModel.GetNumTokens.
+type TokenCounter func(text string) int
+
+// ModelTokenCounter returns a TokenCounter using the tokenizer of the model, see CountTokens.
+func ModelTokenCounter(model string) TokenCounter {
+	return func(text string) int {
+		return CountTokens(model, text)
+	}
+}
+
+// CountMessageTokens returns the number of tokens a single message uses in a chat model
+// request: its content, role, name and function call plus a fixed per-message overhead.
+func CountMessageTokens(countTokens TokenCounter, message schema.ChatMessage) int {
+	numTokens := _tokensPerMessage + countTokens(message.GetContent()) + countTokens(messageRole(message))
+
+	if named, ok := message.(schema.Named); ok && named.GetName() != "" {
+		numTokens += countTokens(named.GetName()) + _tokensPerName
+	}
+
+	if ai, ok := message.(schema.AIChatMessage); ok && ai.FunctionCall != nil {
+		numTokens += countTokens(ai.FunctionCall.Name) + countTokens(ai.FunctionCall.Arguments)
+	}
+
+	return numTokens
+}
+
+// CountMessagesTokens returns the number of tokens a list of messages uses in a chat model
+// request, including the tokens priming the reply.
+func CountMessagesTokens(countTokens TokenCounter, messages []schema.ChatMessage) int {
+	numTokens := _tokensPerReply
+	for _, message := range messages {
+		numTokens += CountMessageTokens(countTokens, message)
+	}
+	return numTokens
+}
+
+// TrimMessages returns the most recent messages whose total number of tokens, as counted
+// by CountMessagesTokens, does not exceed maxTokens. The oldest messages are dropped
+// first. Every message is counted once.
+func TrimMessages(countTokens TokenCounter, messages []schema.ChatMessage, maxTokens int) []schema.ChatMessage {
+	numTokens := _tokensPerReply
+	for i := len(messages) - 1; i >= 0; i-- {
+		numTokens += CountMessageTokens(countTokens, messages[i])
+		if numTokens > maxTokens {
+			return messages[i+1:]
+		}
+	}
+	return messages
+}
+
+// messageRole returns the role a chat model API uses for the message.
+func messageRole(message schema.ChatMessage) string {
+	if generic, ok := message.(schema.GenericChatMessage); ok {
+		return generic.Role
+	}
+
+	switch message.GetType() { //nolint:exhaustive
+	case schema.ChatMessageTypeAI:
+		return "assistant"
+	case schema.ChatMessageTypeHuman:
+		return "user"
+	default:
+		return string(message.GetType())
+	}
+}
@@ -0,0 +1,63 @@
+package llms
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func countWords(text string) int {
+	return len(strings.Fields(text))
+}
+
+func TestCountMessageTokens(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		message  schema.ChatMessage
+		expected int
+	}{
+		{schema.HumanChatMessage{Content: "hello there"}, 3 + 2 + 1},
+		{schema.AIChatMessage{Content: "hi"}, 3 + 1 + 1},
+		{schema.SystemChatMessage{Content: "be nice"}, 3 + 2 + 1},
+		{schema.GenericChatMessage{Role: "critic", Content: "too long"}, 3 + 2 + 1},
+		{schema.FunctionChatMessage{Name: "weather", Content: "sunny"}, 3 + 1 + 1 + 1 + 1},
+		{
+			schema.AIChatMessage{FunctionCall: &schema.FunctionCall{Name: "weather", Arguments: "{} now"}},
+			3 + 0 + 1 + 1 + 2,
+		},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, CountMessageTokens(countWords, c.message), c.message)
+	}
+}
+
+func TestCountMessagesTokens(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 3, CountMessagesTokens(countWords, nil))
+	assert.Equal(t, 3+6+5, CountMessagesTokens(countWords, []schema.ChatMessage{
+		schema.HumanChatMessage{Content: "hello there"},
+		schema.AIChatMessage{Content: "hi"},
+	}))
+}
+
+func TestTrimMessages(t *testing.T) {
+	t.Parallel()
+
+	messages := []schema.ChatMessage{
+		schema.HumanChatMessage{Content: "one"},
+		schema.AIChatMessage{Content: "two"},
+		schema.HumanChatMessage{Content: "three"},
+		schema.AIChatMessage{Content: "four"},
+	}
+
+	// Every message uses 5 tokens and the reply priming 3.
+	assert.Equal(t, messages, TrimMessages(countWords, messages, 23))
+	assert.Equal(t, messages[1:], TrimMessages(countWords, messages, 22))
+	assert.Equal(t, messages[2:], TrimMessages(countWords, messages, 13))
+	assert.Empty(t, TrimMessages(countWords, messages, 7))
+	assert.Empty(t, TrimMessages(countWords, nil, 0))
+}
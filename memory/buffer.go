@@ -12,6 +12,9 @@ import (
 var ErrInvalidInputValues = errors.New("invalid input values")
 
 // ConversationBuffer is a simple form of memory that remembers previous conversational back and forths directly.
+// It is safe for concurrent use if its chat history is, which is the case for the histories of this package.
+// The user and AI messages of an exchange are added at once if the chat history has an AddMessages method,
+// as the histories of this package do, so the exchanges saved concurrently are not interleaved.
 type ConversationBuffer struct {
 	ChatHistory schema.ChatMessageHistory
 
@@ -26,6 +29,19 @@ type ConversationBuffer struct {
 // Statically assert that ConversationBuffer implement the memory interface.
 var _ schema.Memory = &ConversationBuffer{}
 
+// messagesAdder is implemented by chat message histories that can add several messages
+// atomically.
+type messagesAdder interface {
+	AddMessages(ctx context.Context, messages []schema.ChatMessage) error
+}
+
+// Statically assert that the chat message histories of this package add messages atomically.
+var (
+	_ messagesAdder = &ChatMessageHistory{}
+	_ messagesAdder = &FileChatMessageHistory{}
+	_ messagesAdder = &SQLChatMessageHistory{}
+)
+
 // NewConversationBuffer is a function for crating a new buffer memory.
 func NewConversationBuffer(options ...ConversationBufferOption) *ConversationBuffer {
 	return applyBufferOptions(options...)
@@ -80,21 +96,23 @@ func (m *ConversationBuffer) SaveContext(
 	if err != nil {
 		return err
 	}
-	err = m.ChatHistory.AddUserMessage(ctx, userInputValue)
+	aiOutputValue, err := getInputValue(outputValues, m.OutputKey)
 	if err != nil {
 		return err
 	}
 
-	aiOutputValue, err := getInputValue(outputValues, m.OutputKey)
-	if err != nil {
-		return err
+	if adder, ok := m.ChatHistory.(messagesAdder); ok {
+		return adder.AddMessages(ctx, []schema.ChatMessage{
+			schema.HumanChatMessage{Content: userInputValue},
+			schema.AIChatMessage{Content: aiOutputValue},
+		})
 	}
-	err = m.ChatHistory.AddAIMessage(ctx, aiOutputValue)
+
+	err = m.ChatHistory.AddUserMessage(ctx, userInputValue)
 	if err != nil {
 		return err
 	}
-
-	return nil
+	return m.ChatHistory.AddAIMessage(ctx, aiOutputValue)
 }
 
 // Clear sets the chat messages to a new and empty chat message history.
@@ -2,6 +2,7 @@ package memory
 
 import (
 	"context"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -97,6 +98,12 @@ func (t testChatMessageHistory) SetMessages(context.Context, []schema.ChatMessag
 	return nil
 }
 
+func (t testChatMessageHistory) TrimMessages(
+	context.Context, func([]schema.ChatMessage) []schema.ChatMessage,
+) error {
+	return nil
+}
+
 func (t testChatMessageHistory) Messages(context.Context) ([]schema.ChatMessage, error) {
 	return []schema.ChatMessage{
 		schema.HumanChatMessage{Content: "user message test"},
@@ -115,3 +122,37 @@ func TestBufferMemoryWithChatHistoryOption(t *testing.T) {
 	expected := map[string]any{"history": "Human: user message test\nAI: ai message test"}
 	assert.Equal(t, expected, result)
 }
+
+func TestBufferMemoryConcurrent(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	m := NewConversationBuffer()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				assert.NoError(t, m.SaveContext(ctx, map[string]any{"input": "hi"}, map[string]any{"output": "hello"}))
+				_, err := m.LoadMemoryVariables(ctx, map[string]any{})
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	messages, err := m.ChatHistory.Messages(ctx)
+	require.NoError(t, err)
+	require.Len(t, messages, 8*50*2)
+
+	// The messages of an exchange are not interleaved with those of other exchanges.
+	for i, message := range messages {
+		expectedType := schema.ChatMessageTypeHuman
+		if i%2 == 1 {
+			expectedType = schema.ChatMessageTypeAI
+		}
+		assert.Equal(t, expectedType, message.GetType())
+	}
+}
@@ -2,13 +2,15 @@ package memory
 
 import (
 	"context"
+	"sync"
 
 	"github.com/tmc/langchaingo/schema"
 )
 
-// ChatMessageHistory is a struct that stores chat messages.
+// ChatMessageHistory is a struct that stores chat messages. It is safe for concurrent use.
 type ChatMessageHistory struct {
 	messages []schema.ChatMessage
+	mu       sync.RWMutex
 }
 
 // Statically assert that ChatMessageHistory implement the chat message history interface.
@@ -19,34 +21,68 @@ func NewChatMessageHistory(options ...ChatMessageHistoryOption) *ChatMessageHist
 	return applyChatOptions(options...)
 }
 
-// Messages returns all messages stored.
+// Messages returns a copy of all messages stored.
 func (h *ChatMessageHistory) Messages(_ context.Context) ([]schema.ChatMessage, error) {
-	return h.messages, nil
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return append(make([]schema.ChatMessage, 0, len(h.messages)), h.messages...), nil
 }
 
 // AddAIMessage adds an AIMessage to the chat message history.
-func (h *ChatMessageHistory) AddAIMessage(_ context.Context, text string) error {
-	h.messages = append(h.messages, schema.AIChatMessage{Content: text})
-	return nil
+func (h *ChatMessageHistory) AddAIMessage(ctx context.Context, text string) error {
+	return h.AddMessage(ctx, schema.AIChatMessage{Content: text})
 }
 
 // AddUserMessage adds an user to the chat message history.
-func (h *ChatMessageHistory) AddUserMessage(_ context.Context, text string) error {
-	h.messages = append(h.messages, schema.HumanChatMessage{Content: text})
-	return nil
+func (h *ChatMessageHistory) AddUserMessage(ctx context.Context, text string) error {
+	return h.AddMessage(ctx, schema.HumanChatMessage{Content: text})
 }
 
 func (h *ChatMessageHistory) Clear(_ context.Context) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
 	h.messages = make([]schema.ChatMessage, 0)
 	return nil
 }
 
 func (h *ChatMessageHistory) AddMessage(_ context.Context, message schema.ChatMessage) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
 	h.messages = append(h.messages, message)
 	return nil
 }
 
+// AddMessages adds the messages to the chat message history at once.
+func (h *ChatMessageHistory) AddMessages(_ context.Context, messages []schema.ChatMessage) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.messages = append(h.messages, messages...)
+	return nil
+}
+
 func (h *ChatMessageHistory) SetMessages(_ context.Context, messages []schema.ChatMessage) error {
-	h.messages = messages
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.messages = append(make([]schema.ChatMessage, 0, len(messages)), messages...)
+	return nil
+}
+
+// TrimMessages removes the oldest messages, keeping the ones returned by trim.
+func (h *ChatMessageHistory) TrimMessages(
+	_ context.Context, trim func(messages []schema.ChatMessage) []schema.ChatMessage,
+) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	messages := append(make([]schema.ChatMessage, 0, len(h.messages)), h.messages...)
+	numRemoved := len(messages) - len(trim(messages))
+	if numRemoved > 0 {
+		h.messages = messages[numRemoved:]
+	}
 	return nil
 }
@@ -2,6 +2,7 @@ package memory
 
 import (
 	"context"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -43,3 +44,54 @@ func TestChatMessageHistory(t *testing.T) {
 		schema.HumanChatMessage{Content: "zoo"},
 	}, messages)
 }
+
+func TestChatMessageHistoryTrimMessages(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	h := NewChatMessageHistory()
+	for _, text := range []string{"one", "two", "three"} {
+		assert.NoError(t, h.AddUserMessage(ctx, text))
+	}
+
+	err := h.TrimMessages(ctx, func(messages []schema.ChatMessage) []schema.ChatMessage {
+		return messages[2:]
+	})
+	assert.NoError(t, err)
+
+	messages, err := h.Messages(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []schema.ChatMessage{schema.HumanChatMessage{Content: "three"}}, messages)
+}
+
+func TestChatMessageHistoryConcurrent(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	h := NewChatMessageHistory()
+	keepLast := func(messages []schema.ChatMessage) []schema.ChatMessage {
+		if len(messages) > 10 {
+			return messages[len(messages)-10:]
+		}
+		return messages
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				assert.NoError(t, h.AddUserMessage(ctx, "hi"))
+				_, err := h.Messages(ctx)
+				assert.NoError(t, err)
+				assert.NoError(t, h.TrimMessages(ctx, keepLast))
+			}
+		}()
+	}
+	wg.Wait()
+
+	messages, err := h.Messages(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, messages, 10)
+}
@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ErrMemoryKeyCollision is returned when two combined memories use the same memory variable.
+var ErrMemoryKeyCollision = errors.New("memory variable used by several memories")
+
+// Combined is a memory that merges several memories into one, so that a single chain
+// can use, for example, a shared read-only knowledge memory and its own conversation
+// buffer. The memory variables of the combined memories must be distinct.
+type Combined struct {
+	Memories []schema.Memory
+}
+
+// Statically assert that Combined implement the memory interface.
+var _ schema.Memory = &Combined{}
+
+// NewCombined is a function for creating a memory combining the given memories. It
+// returns an error wrapping ErrMemoryKeyCollision if two memories share a memory
+// variable.
+func NewCombined(memories ...schema.Memory) (*Combined, error) {
+	seen := make(map[string]bool)
+	for _, memory := range memories {
+		for _, key := range memory.MemoryVariables(context.Background()) {
+			if seen[key] {
+				return nil, fmt.Errorf("%w: %s", ErrMemoryKeyCollision, key)
+			}
+			seen[key] = true
+		}
+	}
+
+	return &Combined{Memories: memories}, nil
+}
+
+// GetMemoryKey returns the memory key of the first memory, or an empty string if
+// there are no memories.
+func (m *Combined) GetMemoryKey(ctx context.Context) string {
+	if len(m.Memories) == 0 {
+		return ""
+	}
+	return m.Memories[0].GetMemoryKey(ctx)
+}
+
+// MemoryVariables returns the union of the memory variables of all memories.
+func (m *Combined) MemoryVariables(ctx context.Context) []string {
+	variables := make([]string, 0, len(m.Memories))
+	for _, memory := range m.Memories {
+		variables = append(variables, memory.MemoryVariables(ctx)...)
+	}
+	return variables
+}
+
+// LoadMemoryVariables loads and merges the memory variables of all memories. It
+// returns an error wrapping ErrMemoryKeyCollision if two memories return the same
+// variable.
+func (m *Combined) LoadMemoryVariables(ctx context.Context, inputs map[string]any) (map[string]any, error) {
+	values := make(map[string]any)
+	for _, memory := range m.Memories {
+		memoryValues, err := memory.LoadMemoryVariables(ctx, inputs)
+		if err != nil {
+			return nil, err
+		}
+
+		for key, value := range memoryValues {
+			if _, ok := values[key]; ok {
+				return nil, fmt.Errorf("%w: %s", ErrMemoryKeyCollision, key)
+			}
+			values[key] = value
+		}
+	}
+
+	return values, nil
+}
+
+// SaveContext saves the context in every memory. Wrap a memory with NewReadOnly to
+// keep it unchanged.
+func (m *Combined) SaveContext(ctx context.Context, inputs map[string]any, outputs map[string]any) error {
+	for _, memory := range m.Memories {
+		if err := memory.SaveContext(ctx, inputs, outputs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Clear clears every memory.
+func (m *Combined) Clear(ctx context.Context) error {
+	for _, memory := range m.Memories {
+		if err := memory.Clear(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
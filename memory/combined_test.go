@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestCombined(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	knowledge := NewConversationBuffer(
+		WithMemoryKey("knowledge"),
+		WithChatHistory(NewChatMessageHistory(WithPreviousMessages([]schema.ChatMessage{
+			schema.SystemChatMessage{Content: "The office opens at 9."},
+		}))),
+	)
+	conversation := NewConversationBuffer()
+
+	m, err := NewCombined(NewReadOnly(knowledge), conversation)
+	require.NoError(t, err)
+
+	assert.Equal(t, "knowledge", m.GetMemoryKey(ctx))
+	assert.Equal(t, []string{"knowledge", "history"}, m.MemoryVariables(ctx))
+
+	err = m.SaveContext(ctx, map[string]any{"input": "hi"}, map[string]any{"output": "hello"})
+	require.NoError(t, err)
+
+	result, err := m.LoadMemoryVariables(ctx, map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"knowledge": "System: The office opens at 9.",
+		"history":   "Human: hi\nAI: hello",
+	}, result)
+
+	// Only the conversation is cleared.
+	require.NoError(t, m.Clear(ctx))
+	result, err = m.LoadMemoryVariables(ctx, map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"knowledge": "System: The office opens at 9.",
+		"history":   "",
+	}, result)
+}
+
+func TestCombinedKeyCollision(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewCombined(NewConversationBuffer(), NewReadOnly(NewConversationWindowBuffer(2)))
+	require.ErrorIs(t, err, ErrMemoryKeyCollision)
+
+	m := &Combined{Memories: []schema.Memory{NewConversationBuffer(), NewConversationBuffer()}}
+	_, err = m.LoadMemoryVariables(context.Background(), map[string]any{})
+	require.ErrorIs(t, err, ErrMemoryKeyCollision)
+}
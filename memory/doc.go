@@ -5,5 +5,19 @@ a variety of implementations for storing and retrieving that data.
 The main components of this package are:
 - ChatMessageHistory: a struct that stores chat messages.
 - ConversationBuffer: a simple form of memory that remembers previous conversational back and forths directly.
+- ConversationWindowBuffer: a buffer memory that only remembers the last K exchanges.
+- ConversationSummary: a memory that keeps a running summary of the conversation generated by an LLM.
+- ConversationSummaryBuffer: a memory that keeps the most recent messages verbatim, up to a token limit,
+and a running summary of the older ones.
+- VectorStoreRetriever: a memory that stores exchanges in a vector store and retrieves the ones
+most relevant to the current input.
+- ConversationEntity: a memory that keeps LLM-generated summaries of the entities mentioned in the
+conversation in an EntityStore.
+- SQLChatMessageHistory and FileChatMessageHistory: chat message histories persisted, per session,
+in a SQL database or in JSON files.
+- Combined and ReadOnly: a memory merging several memories into one, and a view of a memory that
+is never saved to or cleared.
+- SessionMemory: a memory keeping a separate memory per session, identified by a session ID set on
+the context or in the input values.
 */
 package memory
@@ -0,0 +1,387 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+//nolint:lll
+const _entityExtractionTemplate = `You are an AI assistant reading the transcript of a conversation between an AI and a human. Extract all of the proper nouns from the last line of conversation. As a guideline, a proper noun is generally capitalized. You should definitely extract all names and places.
+
+The conversation history is provided just in case of a coreference (e.g. "What do you know about him" where "him" is defined in a previous line) -- ignore items mentioned there that are not in the last line.
+
+Return the output as a single comma-separated list, or NONE if there is nothing of note to return (e.g. the user is just issuing a greeting or having a simple conversation).
+
+EXAMPLE
+Conversation history:
+Person #1: how's it going today?
+AI: "It's going great! How about you?"
+Person #1: good! busy working on Langchain. lots to do.
+AI: "That sounds like a lot of work! What kind of things are you doing to make Langchain better?"
+Last line:
+Person #1: i'm trying to improve Langchain's interfaces, the UX, its integrations with various products the user might want ... a lot of stuff. I'm working with Person #2.
+Output: Langchain, Person #2
+END OF EXAMPLE
+
+Conversation history (for reference only):
+{{.history}}
+Last line of conversation (for extraction):
+Human: {{.input}}
+
+Output:`
+
+//nolint:lll
+const _entitySummarizationTemplate = `You are an AI assistant helping a human keep track of facts about relevant people, places, and concepts in their life. Update the summary of the provided entity in the "Entity" section based on the last line of your conversation with the human. If you are writing the summary for the first time, return a single sentence.
+The update should only include facts that are relayed in the last line of conversation about the provided entity, and should only contain facts about the provided entity.
+
+If there is no new information about the provided entity or the information is not worth noting (not an important or relevant fact to remember long-term), return the existing summary unchanged.
+
+Full conversation history (for context):
+{{.history}}
+
+Entity to summarize:
+{{.entity}}
+
+Existing summary of {{.entity}}:
+{{.summary}}
+
+Last line of conversation:
+Human: {{.input}}
+Updated summary:`
+
+const (
+	_defaultEntitiesKey           = "entities"
+	_defaultEntityContextMessages = 6
+	_noEntities                   = "NONE"
+)
+
+// NewEntityExtractionPrompt returns the default prompt used to extract entities from
+// the input. It expects the variables "history" and "input".
+func NewEntityExtractionPrompt() prompts.PromptTemplate {
+	return prompts.NewPromptTemplate(_entityExtractionTemplate, []string{"history", "input"})
+}
+
+// NewEntitySummarizationPrompt returns the default prompt used to update the summary of
+// an entity. It expects the variables "history", "entity", "summary" and "input".
+func NewEntitySummarizationPrompt() prompts.PromptTemplate {
+	return prompts.NewPromptTemplate(
+		_entitySummarizationTemplate,
+		[]string{"history", "entity", "summary", "input"},
+	)
+}
+
+// EntityStore is the interface for storing the summaries of entities.
+type EntityStore interface {
+	// Get returns the summary of the entity, and whether the entity exists.
+	Get(ctx context.Context, entity string) (string, bool, error)
+	// Set sets the summary of the entity.
+	Set(ctx context.Context, entity string, summary string) error
+	// Delete removes the entity.
+	Delete(ctx context.Context, entity string) error
+	// Clear removes all entities.
+	Clear(ctx context.Context) error
+}
+
+// InMemoryEntityStore is an entity store that keeps the summaries in a map.
+type InMemoryEntityStore struct {
+	mu       sync.RWMutex
+	entities map[string]string
+}
+
+// Statically assert that InMemoryEntityStore implement the entity store interface.
+var _ EntityStore = &InMemoryEntityStore{}
+
+// NewInMemoryEntityStore creates a new empty in-memory entity store.
+func NewInMemoryEntityStore() *InMemoryEntityStore {
+	return &InMemoryEntityStore{
+		entities: make(map[string]string),
+	}
+}
+
+// Get returns the summary of the entity, and whether the entity exists.
+func (s *InMemoryEntityStore) Get(_ context.Context, entity string) (string, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	summary, ok := s.entities[entity]
+	return summary, ok, nil
+}
+
+// Set sets the summary of the entity.
+func (s *InMemoryEntityStore) Set(_ context.Context, entity string, summary string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entities[entity] = summary
+	return nil
+}
+
+// Delete removes the entity.
+func (s *InMemoryEntityStore) Delete(_ context.Context, entity string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entities, entity)
+	return nil
+}
+
+// Clear removes all entities.
+func (s *InMemoryEntityStore) Clear(_ context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entities = make(map[string]string)
+	return nil
+}
+
+// ConversationEntity is a memory that remembers facts about the entities, such as
+// people, places and products, mentioned in the conversation. The entities of every
+// input are extracted by an llm, and an llm-generated summary of each entity is kept
+// in an entity store. Besides the recent messages of the conversation, the summaries
+// of the entities mentioned in the current input are loaded. Entity names are
+// case-insensitive: they are stored in the entity store in lower case, with their
+// whitespace collapsed.
+type ConversationEntity struct {
+	ConversationBuffer
+	LLM         llms.LanguageModel
+	EntityStore EntityStore
+	// EntitiesKey is the key under which the entity summaries are loaded, as a
+	// string with a "name: summary" line per entity.
+	EntitiesKey string
+	// NumContextMessages is the number of recent messages loaded as history and
+	// used as context when extracting and summarizing entities.
+	NumContextMessages int
+
+	EntityExtractionPrompt    prompts.FormatPrompter
+	EntitySummarizationPrompt prompts.FormatPrompter
+
+	// The entities extracted from the last loaded input, reused when saving the
+	// context of the same input.
+	mu           sync.Mutex
+	lastInput    string
+	lastEntities []string
+}
+
+// Statically assert that ConversationEntity implement the memory interface.
+var _ schema.Memory = &ConversationEntity{}
+
+// NewConversationEntity is a function for creating a new entity memory. If the entity
+// store is nil, an in-memory entity store is used.
+func NewConversationEntity(
+	llm llms.LanguageModel,
+	entityStore EntityStore,
+	options ...ConversationBufferOption,
+) *ConversationEntity {
+	if entityStore == nil {
+		entityStore = NewInMemoryEntityStore()
+	}
+
+	return &ConversationEntity{
+		ConversationBuffer:        *applyBufferOptions(options...),
+		LLM:                       llm,
+		EntityStore:               entityStore,
+		EntitiesKey:               _defaultEntitiesKey,
+		NumContextMessages:        _defaultEntityContextMessages,
+		EntityExtractionPrompt:    NewEntityExtractionPrompt(),
+		EntitySummarizationPrompt: NewEntitySummarizationPrompt(),
+	}
+}
+
+// MemoryVariables returns the memory key and the entities key.
+func (m *ConversationEntity) MemoryVariables(context.Context) []string {
+	return []string{m.MemoryKey, m.EntitiesKey}
+}
+
+// LoadMemoryVariables extracts the entities of the input and returns their summaries,
+// together with the recent messages of the conversation.
+func (m *ConversationEntity) LoadMemoryVariables(
+	ctx context.Context, inputs map[string]any,
+) (map[string]any, error) {
+	input, err := getInputValue(inputs, m.InputKey)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := m.recentMessages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	entities, err := m.extractEntities(ctx, messages, input)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	m.lastInput, m.lastEntities = input, entities
+	m.mu.Unlock()
+
+	summaries, err := m.entitySummaries(ctx, entities)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.ReturnMessages {
+		return map[string]any{
+			m.MemoryKey:   messages,
+			m.EntitiesKey: summaries,
+		}, nil
+	}
+
+	bufferString, err := schema.GetBufferString(messages, m.HumanPrefix, m.AIPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		m.MemoryKey:   bufferString,
+		m.EntitiesKey: summaries,
+	}, nil
+}
+
+// SaveContext saves the messages in the chat history and updates the summaries of the
+// entities mentioned in the input.
+func (m *ConversationEntity) SaveContext(
+	ctx context.Context, inputValues map[string]any, outputValues map[string]any,
+) error {
+	input, err := getInputValue(inputValues, m.InputKey)
+	if err != nil {
+		return err
+	}
+
+	messages, err := m.recentMessages(ctx)
+	if err != nil {
+		return err
+	}
+	history, err := schema.GetBufferString(messages, m.HumanPrefix, m.AIPrefix)
+	if err != nil {
+		return err
+	}
+
+	entities, err := m.entitiesOf(ctx, messages, input)
+	if err != nil {
+		return err
+	}
+
+	for _, entity := range entities {
+		summary, _, err := m.EntityStore.Get(ctx, normalizeEntity(entity))
+		if err != nil {
+			return err
+		}
+
+		newSummary, err := generate(ctx, m.LLM, m.EntitySummarizationPrompt, map[string]any{
+			"history": history,
+			"entity":  entity,
+			"summary": summary,
+			"input":   input,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := m.EntityStore.Set(ctx, normalizeEntity(entity), newSummary); err != nil {
+			return err
+		}
+	}
+
+	return m.ConversationBuffer.SaveContext(ctx, inputValues, outputValues)
+}
+
+// Clear clears the chat history and the entity store.
+func (m *ConversationEntity) Clear(ctx context.Context) error {
+	m.mu.Lock()
+	m.lastInput, m.lastEntities = "", nil
+	m.mu.Unlock()
+
+	if err := m.EntityStore.Clear(ctx); err != nil {
+		return err
+	}
+	return m.ConversationBuffer.Clear(ctx)
+}
+
+// entitiesOf returns the entities of the input, reusing the entities extracted in
+// LoadMemoryVariables if the input is the same.
+func (m *ConversationEntity) entitiesOf(
+	ctx context.Context, messages []schema.ChatMessage, input string,
+) ([]string, error) {
+	m.mu.Lock()
+	if m.lastInput == input && m.lastEntities != nil {
+		entities := m.lastEntities
+		m.mu.Unlock()
+		return entities, nil
+	}
+	m.mu.Unlock()
+
+	return m.extractEntities(ctx, messages, input)
+}
+
+func (m *ConversationEntity) extractEntities(
+	ctx context.Context, messages []schema.ChatMessage, input string,
+) ([]string, error) {
+	history, err := schema.GetBufferString(messages, m.HumanPrefix, m.AIPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := generate(ctx, m.LLM, m.EntityExtractionPrompt, map[string]any{
+		"history": history,
+		"input":   input,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entities := make([]string, 0)
+	if output == _noEntities {
+		return entities, nil
+	}
+	seen := make(map[string]bool)
+	for _, entity := range strings.Split(output, ",") {
+		entity = strings.Join(strings.Fields(entity), " ")
+		if entity == "" || entity == _noEntities || seen[normalizeEntity(entity)] {
+			continue
+		}
+		seen[normalizeEntity(entity)] = true
+		entities = append(entities, entity)
+	}
+
+	return entities, nil
+}
+
+// entitySummaries returns the summaries of the entities found in the entity store,
+// formatted as a "name: summary" line per entity.
+func (m *ConversationEntity) entitySummaries(ctx context.Context, entities []string) (string, error) {
+	lines := make([]string, 0, len(entities))
+	for _, entity := range entities {
+		summary, ok, err := m.EntityStore.Get(ctx, normalizeEntity(entity))
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			lines = append(lines, entity+": "+summary)
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// normalizeEntity returns the name under which an entity is stored.
+func normalizeEntity(entity string) string {
+	return strings.ToLower(strings.Join(strings.Fields(entity), " "))
+}
+
+func (m *ConversationEntity) recentMessages(ctx context.Context) ([]schema.ChatMessage, error) {
+	messages, err := m.ChatHistory.Messages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.NumContextMessages > 0 && len(messages) > m.NumContextMessages {
+		messages = messages[len(messages)-m.NumContextMessages:]
+	}
+	return messages, nil
+}
@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConversationEntity(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	llm := &testLanguageModel{completions: []string{
+		// Load: entities of the first input.
+		"Acme, Bob",
+		// Save: summaries of the entities.
+		"Acme is a customer buying 10 widgets.",
+		"Bob works at Acme.",
+		// Load: entities of the second input.
+		"acme, ACME",
+	}}
+	store := NewInMemoryEntityStore()
+	m := NewConversationEntity(llm, store, WithInputKey("input"))
+
+	assert.Equal(t, []string{"history", "entities"}, m.MemoryVariables(ctx))
+
+	inputs := map[string]any{"input": "Bob from Acme wants 10 widgets"}
+	result, err := m.LoadMemoryVariables(ctx, inputs)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": "", "entities": ""}, result)
+
+	err = m.SaveContext(ctx, inputs, map[string]any{"output": "Noted"})
+	require.NoError(t, err)
+
+	// The entities extracted when loading are reused when saving.
+	require.Len(t, llm.prompts, 3)
+	assert.True(t, strings.Contains(llm.prompts[1], "Entity to summarize:\nAcme\n"))
+	assert.True(t, strings.Contains(llm.prompts[2], "Entity to summarize:\nBob\n"))
+
+	// Entities are stored under their normalized name.
+	summary, ok, err := store.Get(ctx, "bob")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "Bob works at Acme.", summary)
+
+	result, err = m.LoadMemoryVariables(ctx, map[string]any{"input": "What does Acme want?"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"history":  "Human: Bob from Acme wants 10 widgets\nAI: Noted",
+		"entities": "acme: Acme is a customer buying 10 widgets.",
+	}, result)
+	assert.True(t, strings.Contains(llm.prompts[3], "Conversation history (for reference only):\nHuman: Bob from Acme"))
+
+	require.NoError(t, m.Clear(ctx))
+	_, ok, err = store.Get(ctx, "acme")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestConversationEntityNoEntities(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	llm := &testLanguageModel{completions: []string{"NONE"}}
+	m := NewConversationEntity(llm, nil)
+
+	err := m.SaveContext(ctx, map[string]any{"input": "hi"}, map[string]any{"output": "hello"})
+	require.NoError(t, err)
+	assert.Len(t, llm.prompts, 1)
+
+	messages, err := m.ChatHistory.Messages(ctx)
+	require.NoError(t, err)
+	assert.Len(t, messages, 2)
+}
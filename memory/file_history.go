@@ -0,0 +1,178 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ErrInvalidSessionID is returned when a session ID can not be used to name a file.
+var ErrInvalidSessionID = errors.New("invalid session id")
+
+const _fileHistoryPermissions = 0o600
+
+// _fileHistoryLocks holds a *sync.Mutex per absolute file path, shared by all the
+// histories of the same session.
+var _fileHistoryLocks sync.Map //nolint:gochecknoglobals
+
+// FileChatMessageHistory is a chat message history stored as a JSON file. Every session
+// is stored in its own file, named after the session ID, in a directory. Histories of
+// the same session in a process are safe for concurrent use.
+type FileChatMessageHistory struct {
+	path string
+	mu   *sync.Mutex
+}
+
+// Statically assert that FileChatMessageHistory implement the chat message history interface.
+var _ schema.ChatMessageHistory = &FileChatMessageHistory{}
+
+// NewFileChatMessageHistory creates a chat message history for the session stored in the
+// file "<sessionID>.json" in the directory. The directory is created if it does not exist.
+func NewFileChatMessageHistory(dir string, sessionID string) (*FileChatMessageHistory, error) {
+	if sessionID == "" || sessionID != filepath.Base(sessionID) || sessionID == "." || sessionID == ".." {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidSessionID, sessionID)
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil { //nolint:gomnd
+		return nil, err
+	}
+
+	path, err := filepath.Abs(filepath.Join(dir, sessionID+".json"))
+	if err != nil {
+		return nil, err
+	}
+	mu, _ := _fileHistoryLocks.LoadOrStore(path, &sync.Mutex{})
+
+	return &FileChatMessageHistory{
+		path: path,
+		mu:   mu.(*sync.Mutex), //nolint:forcetypeassert
+	}, nil
+}
+
+// Messages returns all messages of the session.
+func (h *FileChatMessageHistory) Messages(_ context.Context) ([]schema.ChatMessage, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.read()
+}
+
+// AddAIMessage adds an AIMessage to the chat message history.
+func (h *FileChatMessageHistory) AddAIMessage(ctx context.Context, text string) error {
+	return h.AddMessage(ctx, schema.AIChatMessage{Content: text})
+}
+
+// AddUserMessage adds an user to the chat message history.
+func (h *FileChatMessageHistory) AddUserMessage(ctx context.Context, text string) error {
+	return h.AddMessage(ctx, schema.HumanChatMessage{Content: text})
+}
+
+// AddMessage adds a message to the chat message history.
+func (h *FileChatMessageHistory) AddMessage(_ context.Context, message schema.ChatMessage) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	messages, err := h.read()
+	if err != nil {
+		return err
+	}
+
+	return h.write(append(messages, message))
+}
+
+// AddMessages adds the messages to the chat message history at once.
+func (h *FileChatMessageHistory) AddMessages(_ context.Context, messages []schema.ChatMessage) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	stored, err := h.read()
+	if err != nil {
+		return err
+	}
+
+	return h.write(append(stored, messages...))
+}
+
+// Clear removes all messages of the session.
+func (h *FileChatMessageHistory) Clear(_ context.Context) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.write(nil)
+}
+
+// SetMessages replaces all messages of the session.
+func (h *FileChatMessageHistory) SetMessages(_ context.Context, messages []schema.ChatMessage) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.write(messages)
+}
+
+// TrimMessages removes the oldest messages of the session, keeping the ones returned by trim.
+func (h *FileChatMessageHistory) TrimMessages(
+	_ context.Context, trim func(messages []schema.ChatMessage) []schema.ChatMessage,
+) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	messages, err := h.read()
+	if err != nil {
+		return err
+	}
+
+	numRemoved := len(messages) - len(trim(messages))
+	if numRemoved <= 0 {
+		return nil
+	}
+	return h.write(messages[numRemoved:])
+}
+
+func (h *FileChatMessageHistory) read() ([]schema.ChatMessage, error) {
+	data, err := os.ReadFile(h.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return make([]schema.ChatMessage, 0), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return schema.UnmarshalChatMessages(data)
+}
+
+// write replaces the file atomically, so a crash never leaves a partially written history.
+func (h *FileChatMessageHistory) write(messages []schema.ChatMessage) error {
+	data, err := schema.MarshalChatMessages(messages)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(h.path), filepath.Base(h.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(_fileHistoryPermissions); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), h.path)
+}
@@ -0,0 +1,47 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ReadOnly is a memory that exposes the variables of another memory without ever
+// changing it. SaveContext and Clear are no-ops, so the wrapped memory can be
+// shared, for example as a knowledge memory seen by several chains.
+type ReadOnly struct {
+	Memory schema.Memory
+}
+
+// Statically assert that ReadOnly implement the memory interface.
+var _ schema.Memory = ReadOnly{}
+
+// NewReadOnly is a function for creating a read-only view of a memory.
+func NewReadOnly(memory schema.Memory) ReadOnly {
+	return ReadOnly{Memory: memory}
+}
+
+// GetMemoryKey returns the memory key of the wrapped memory.
+func (m ReadOnly) GetMemoryKey(ctx context.Context) string {
+	return m.Memory.GetMemoryKey(ctx)
+}
+
+// MemoryVariables returns the memory variables of the wrapped memory.
+func (m ReadOnly) MemoryVariables(ctx context.Context) []string {
+	return m.Memory.MemoryVariables(ctx)
+}
+
+// LoadMemoryVariables loads the memory variables of the wrapped memory.
+func (m ReadOnly) LoadMemoryVariables(ctx context.Context, inputs map[string]any) (map[string]any, error) {
+	return m.Memory.LoadMemoryVariables(ctx, inputs)
+}
+
+// SaveContext does nothing.
+func (m ReadOnly) SaveContext(context.Context, map[string]any, map[string]any) error {
+	return nil
+}
+
+// Clear does nothing.
+func (m ReadOnly) Clear(context.Context) error {
+	return nil
+}
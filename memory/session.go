@@ -0,0 +1,250 @@
+package memory
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ErrNoSessionID is returned when the session ID is neither set on the context nor in
+// the input values.
+var ErrNoSessionID = errors.New("no session id")
+
+type sessionIDContextKey struct{}
+
+// WithSessionID returns a copy of the context carrying the session ID used by
+// SessionMemory.
+func WithSessionID(ctx context.Context, sessionID string) context.Context {
+	return context.WithValue(ctx, sessionIDContextKey{}, sessionID)
+}
+
+// SessionIDFromContext returns the session ID set on the context with WithSessionID.
+func SessionIDFromContext(ctx context.Context) (string, bool) {
+	sessionID, ok := ctx.Value(sessionIDContextKey{}).(string)
+	return sessionID, ok && sessionID != ""
+}
+
+// SessionHistoryFactory returns the chat message history of a session.
+type SessionHistoryFactory func(ctx context.Context, sessionID string) (schema.ChatMessageHistory, error)
+
+// SessionMemory is a memory that keeps a separate memory per session, so a single
+// chain can serve many conversations. The session ID is read from the context, see
+// WithSessionID, or from the input values, see WithSessionIDKey. The chat message
+// history of a session is created by a factory, and the memories of the most recently
+// used sessions are cached. Evicted sessions are recreated with the factory, so the
+// factory should return persistent histories, such as SQLChatMessageHistory, for
+// conversations to survive eviction.
+type SessionMemory struct {
+	Factory SessionHistoryFactory
+	// NewMemory creates the memory of a session from its chat message history.
+	NewMemory func(history schema.ChatMessageHistory) schema.Memory
+	// SessionIDKey is the input key of the session ID, used when the context has none.
+	SessionIDKey string
+	// MaxSessions is the maximum number of cached sessions.
+	MaxSessions int
+	// TTL is the duration after which an unused session is evicted. Zero means never.
+	TTL time.Duration
+
+	mu       sync.Mutex
+	sessions map[string]*list.Element
+	// lru holds the cached sessions, the most recently used first.
+	lru *list.List
+	now func() time.Time
+}
+
+type session struct {
+	id       string
+	memory   schema.Memory
+	lastUsed time.Time
+}
+
+// Statically assert that SessionMemory implement the memory interface.
+var _ schema.Memory = &SessionMemory{}
+
+// NewSessionMemory is a function for creating a new session memory using the factory
+// for the chat message histories of the sessions.
+func NewSessionMemory(factory SessionHistoryFactory, options ...SessionMemoryOption) *SessionMemory {
+	m := applySessionMemoryOptions(options...)
+	m.Factory = factory
+	m.sessions = make(map[string]*list.Element)
+	m.lru = list.New()
+	return m
+}
+
+// GetMemoryKey returns the memory key of the session memories.
+func (m *SessionMemory) GetMemoryKey(ctx context.Context) string {
+	return m.NewMemory(NewChatMessageHistory()).GetMemoryKey(ctx)
+}
+
+// MemoryVariables returns the memory variables of the session memories.
+func (m *SessionMemory) MemoryVariables(ctx context.Context) []string {
+	return m.NewMemory(NewChatMessageHistory()).MemoryVariables(ctx)
+}
+
+// LoadMemoryVariables loads the memory variables of the session.
+func (m *SessionMemory) LoadMemoryVariables(ctx context.Context, inputs map[string]any) (map[string]any, error) {
+	memory, inputs, err := m.sessionMemory(ctx, inputs)
+	if err != nil {
+		return nil, err
+	}
+	return memory.LoadMemoryVariables(ctx, inputs)
+}
+
+// SaveContext saves the context in the memory of the session.
+func (m *SessionMemory) SaveContext(ctx context.Context, inputs map[string]any, outputs map[string]any) error {
+	memory, inputs, err := m.sessionMemory(ctx, inputs)
+	if err != nil {
+		return err
+	}
+	return memory.SaveContext(ctx, inputs, outputs)
+}
+
+// Clear clears the memory of the session set on the context and evicts the session.
+func (m *SessionMemory) Clear(ctx context.Context) error {
+	return m.ClearSession(ctx, nil)
+}
+
+// ClearSession clears the memory of the session and evicts the session. The session ID
+// is read from the context or the input values, as in SaveContext.
+func (m *SessionMemory) ClearSession(ctx context.Context, inputs map[string]any) error {
+	sessionID, _, err := m.sessionID(ctx, inputs)
+	if err != nil {
+		return err
+	}
+
+	memory, err := m.memoryOf(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if err := memory.Clear(ctx); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if element, ok := m.sessions[sessionID]; ok {
+		m.remove(element)
+	}
+	return nil
+}
+
+// sessionMemory returns the memory of the session and the input values without the
+// session ID.
+func (m *SessionMemory) sessionMemory(
+	ctx context.Context, inputs map[string]any,
+) (schema.Memory, map[string]any, error) {
+	sessionID, inputs, err := m.sessionID(ctx, inputs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	memory, err := m.memoryOf(ctx, sessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return memory, inputs, nil
+}
+
+// memoryOf returns the cached memory of the session, or creates it. The factory is
+// called without holding the lock, so a slow factory does not block other sessions.
+func (m *SessionMemory) memoryOf(ctx context.Context, sessionID string) (schema.Memory, error) { //nolint:ireturn
+	if memory, ok := m.cachedMemory(sessionID); ok {
+		return memory, nil
+	}
+
+	history, err := m.Factory(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	memory := m.NewMemory(history)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Another call may have created the memory of the session in the meantime.
+	now := m.now()
+	if element, ok := m.sessions[sessionID]; ok {
+		return m.touch(element, now), nil
+	}
+
+	s := &session{id: sessionID, memory: memory, lastUsed: now}
+	m.sessions[sessionID] = m.lru.PushFront(s)
+	for m.MaxSessions > 0 && m.lru.Len() > m.MaxSessions {
+		m.remove(m.lru.Back())
+	}
+
+	return memory, nil
+}
+
+// cachedMemory returns the memory of the session if it is cached.
+func (m *SessionMemory) cachedMemory(sessionID string) (schema.Memory, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.evictExpired(now)
+
+	element, ok := m.sessions[sessionID]
+	if !ok {
+		return nil, false
+	}
+	return m.touch(element, now), true
+}
+
+// touch marks a session as used and returns its memory. The caller must hold the lock.
+func (m *SessionMemory) touch(element *list.Element, now time.Time) schema.Memory { //nolint:ireturn
+	s, _ := element.Value.(*session)
+	s.lastUsed = now
+	m.lru.MoveToFront(element)
+	return s.memory
+}
+
+func (m *SessionMemory) sessionID(ctx context.Context, inputs map[string]any) (string, map[string]any, error) {
+	if sessionID, ok := SessionIDFromContext(ctx); ok {
+		return sessionID, inputs, nil
+	}
+
+	value, ok := inputs[m.SessionIDKey]
+	if m.SessionIDKey == "" || !ok {
+		return "", nil, ErrNoSessionID
+	}
+	sessionID, ok := value.(string)
+	if !ok || sessionID == "" {
+		return "", nil, fmt.Errorf("%w: %v", ErrInvalidSessionID, value)
+	}
+
+	sessionInputs := make(map[string]any, len(inputs)-1)
+	for key, value := range inputs {
+		if key != m.SessionIDKey {
+			sessionInputs[key] = value
+		}
+	}
+
+	return sessionID, sessionInputs, nil
+}
+
+// evictExpired removes the sessions not used since the TTL. The caller must hold the lock.
+func (m *SessionMemory) evictExpired(now time.Time) {
+	if m.TTL <= 0 {
+		return
+	}
+
+	for element := m.lru.Back(); element != nil; element = m.lru.Back() {
+		s, _ := element.Value.(*session)
+		if now.Sub(s.lastUsed) < m.TTL {
+			return
+		}
+		m.remove(element)
+	}
+}
+
+// remove removes a session from the cache. The caller must hold the lock.
+func (m *SessionMemory) remove(element *list.Element) {
+	s, _ := m.lru.Remove(element).(*session)
+	delete(m.sessions, s.id)
+}
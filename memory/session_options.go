@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+const _defaultMaxSessions = 1000
+
+// SessionMemoryOption is a function for creating a new session memory with other
+// than the default values.
+type SessionMemoryOption func(m *SessionMemory)
+
+// WithSessionIDKey is an option for reading the session ID from the input values with
+// the given key when it is not set on the context. The key is removed from the input
+// values passed to the memory of the session.
+func WithSessionIDKey(key string) SessionMemoryOption {
+	return func(m *SessionMemory) {
+		m.SessionIDKey = key
+	}
+}
+
+// WithMaxSessions is an option for setting the number of sessions kept in the cache.
+// When the cache is full, the least recently used session is evicted.
+func WithMaxSessions(maxSessions int) SessionMemoryOption {
+	return func(m *SessionMemory) {
+		m.MaxSessions = maxSessions
+	}
+}
+
+// WithSessionTTL is an option for evicting the sessions not used for the given duration.
+// Sessions never expire by default.
+func WithSessionTTL(ttl time.Duration) SessionMemoryOption {
+	return func(m *SessionMemory) {
+		m.TTL = ttl
+	}
+}
+
+// WithSessionMemory is an option for setting the function creating the memory of a
+// session from its chat message history. By default, a conversation buffer is used.
+func WithSessionMemory(newMemory func(history schema.ChatMessageHistory) schema.Memory) SessionMemoryOption {
+	return func(m *SessionMemory) {
+		m.NewMemory = newMemory
+	}
+}
+
+func applySessionMemoryOptions(options ...SessionMemoryOption) *SessionMemory {
+	m := &SessionMemory{
+		MaxSessions: _defaultMaxSessions,
+		NewMemory: func(history schema.ChatMessageHistory) schema.Memory {
+			return NewConversationBuffer(WithChatHistory(history))
+		},
+		now: time.Now,
+	}
+
+	for _, option := range options {
+		option(m)
+	}
+
+	return m
+}
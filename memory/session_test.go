@@ -0,0 +1,131 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// testHistoryFactory creates in-memory chat message histories and records the sessions
+// it was called for.
+type testHistoryFactory struct {
+	sessionIDs []string
+}
+
+func (f *testHistoryFactory) new(_ context.Context, sessionID string) (schema.ChatMessageHistory, error) {
+	f.sessionIDs = append(f.sessionIDs, sessionID)
+	return NewChatMessageHistory(), nil
+}
+
+func TestSessionMemory(t *testing.T) {
+	t.Parallel()
+
+	factory := &testHistoryFactory{}
+	m := NewSessionMemory(factory.new)
+	alice := WithSessionID(context.Background(), "alice")
+	bob := WithSessionID(context.Background(), "bob")
+
+	assert.Equal(t, []string{"history"}, m.MemoryVariables(context.Background()))
+
+	err := m.SaveContext(alice, map[string]any{"input": "hi, I am Alice"}, map[string]any{"output": "hello Alice"})
+	require.NoError(t, err)
+	err = m.SaveContext(bob, map[string]any{"input": "hi, I am Bob"}, map[string]any{"output": "hello Bob"})
+	require.NoError(t, err)
+
+	result, err := m.LoadMemoryVariables(alice, map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": "Human: hi, I am Alice\nAI: hello Alice"}, result)
+
+	result, err = m.LoadMemoryVariables(bob, map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": "Human: hi, I am Bob\nAI: hello Bob"}, result)
+	assert.Equal(t, []string{"alice", "bob"}, factory.sessionIDs)
+
+	require.NoError(t, m.Clear(alice))
+	result, err = m.LoadMemoryVariables(alice, map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": ""}, result)
+
+	_, err = m.LoadMemoryVariables(context.Background(), map[string]any{})
+	require.ErrorIs(t, err, ErrNoSessionID)
+}
+
+func TestSessionMemoryInputKey(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	m := NewSessionMemory((&testHistoryFactory{}).new, WithSessionIDKey("user"))
+
+	err := m.SaveContext(ctx, map[string]any{"user": "alice", "input": "hi"}, map[string]any{"output": "hello"})
+	require.NoError(t, err)
+
+	result, err := m.LoadMemoryVariables(ctx, map[string]any{"user": "alice", "input": "how are you?"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": "Human: hi\nAI: hello"}, result)
+
+	_, err = m.LoadMemoryVariables(ctx, map[string]any{"user": 1})
+	require.ErrorIs(t, err, ErrInvalidSessionID)
+
+	require.ErrorIs(t, m.Clear(ctx), ErrNoSessionID)
+	require.NoError(t, m.ClearSession(ctx, map[string]any{"user": "alice"}))
+	result, err = m.LoadMemoryVariables(ctx, map[string]any{"user": "alice", "input": "how are you?"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": ""}, result)
+}
+
+func TestSessionMemorySlowFactory(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	// The factory of alice blocks until the memory of bob is created.
+	bobCreated := make(chan struct{})
+	m := NewSessionMemory(func(_ context.Context, sessionID string) (schema.ChatMessageHistory, error) {
+		if sessionID == "alice" {
+			<-bobCreated
+		}
+		return NewChatMessageHistory(), nil
+	}, WithSessionIDKey("user"))
+
+	done := make(chan error)
+	go func() {
+		_, err := m.LoadMemoryVariables(ctx, map[string]any{"user": "alice"})
+		done <- err
+	}()
+
+	_, err := m.LoadMemoryVariables(ctx, map[string]any{"user": "bob"})
+	require.NoError(t, err)
+	close(bobCreated)
+	require.NoError(t, <-done)
+}
+
+func TestSessionMemoryEviction(t *testing.T) {
+	t.Parallel()
+
+	factory := &testHistoryFactory{}
+	m := NewSessionMemory(factory.new, WithMaxSessions(2), WithSessionTTL(time.Minute))
+	now := time.Now()
+	m.now = func() time.Time { return now }
+
+	load := func(sessionID string) {
+		_, err := m.LoadMemoryVariables(WithSessionID(context.Background(), sessionID), map[string]any{})
+		require.NoError(t, err)
+	}
+
+	load("a")
+	load("b")
+	load("a")
+	// "b" is the least recently used session.
+	load("c")
+	load("a")
+	load("b")
+	assert.Equal(t, []string{"a", "b", "c", "b"}, factory.sessionIDs)
+
+	now = now.Add(time.Minute)
+	load("b")
+	assert.Equal(t, []string{"a", "b", "c", "b", "b"}, factory.sessionIDs)
+	assert.Equal(t, 1, m.lru.Len())
+}
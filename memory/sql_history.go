@@ -0,0 +1,311 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// SQLDialect is the SQL dialect used by a SQLChatMessageHistory.
+type SQLDialect string
+
+const (
+	// SQLDialectSQLite3 is the dialect of SQLite, e.g. with the github.com/mattn/go-sqlite3 driver.
+	SQLDialectSQLite3 SQLDialect = "sqlite3"
+	// SQLDialectPostgreSQL is the dialect of PostgreSQL, e.g. with the github.com/jackc/pgx/v5/stdlib driver.
+	SQLDialectPostgreSQL SQLDialect = "postgresql"
+	// SQLDialectMySQL is the dialect of MySQL, e.g. with the github.com/go-sql-driver/mysql driver.
+	SQLDialectMySQL SQLDialect = "mysql"
+)
+
+const _defaultSQLTableName = "langchaingo_chat_messages"
+
+var (
+	// ErrUnknownSQLDialect is returned when a SQLChatMessageHistory is created with an unknown dialect.
+	ErrUnknownSQLDialect = errors.New("unknown sql dialect")
+	// ErrInvalidTableName is returned when the table name of a SQLChatMessageHistory is not a
+	// valid identifier.
+	ErrInvalidTableName = errors.New("invalid table name")
+)
+
+// nolint:gochecknoglobals
+var _sqlIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// SQLChatMessageHistory is a chat message history stored in a SQL database. The messages
+// of every session are stored in the same table, keyed by session ID.
+type SQLChatMessageHistory struct {
+	db        *sql.DB
+	dialect   SQLDialect
+	sessionID string
+	tableName string
+}
+
+// Statically assert that SQLChatMessageHistory implement the chat message history interface.
+var _ schema.ChatMessageHistory = &SQLChatMessageHistory{}
+
+// SQLChatMessageHistoryOption is a function for creating a new SQL chat message history
+// with other than the default values.
+type SQLChatMessageHistoryOption func(h *SQLChatMessageHistory)
+
+// WithTableName is an option for NewSQLChatMessageHistory for setting the name of the
+// table the messages are stored in. It defaults to "langchaingo_chat_messages".
+func WithTableName(tableName string) SQLChatMessageHistoryOption {
+	return func(h *SQLChatMessageHistory) {
+		h.tableName = tableName
+	}
+}
+
+// NewSQLChatMessageHistory creates a chat message history for the session stored in the
+// database. The database must have been opened with a driver matching the dialect. The
+// table is created if it does not exist.
+func NewSQLChatMessageHistory(
+	ctx context.Context,
+	db *sql.DB,
+	dialect SQLDialect,
+	sessionID string,
+	options ...SQLChatMessageHistoryOption,
+) (*SQLChatMessageHistory, error) {
+	h := &SQLChatMessageHistory{
+		db:        db,
+		dialect:   dialect,
+		sessionID: sessionID,
+		tableName: _defaultSQLTableName,
+	}
+
+	for _, option := range options {
+		option(h)
+	}
+
+	if !_sqlIdentifier.MatchString(h.tableName) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidTableName, h.tableName)
+	}
+
+	if err := h.createTable(ctx); err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+func (h *SQLChatMessageHistory) createTable(ctx context.Context) error {
+	var statements []string
+	switch h.dialect {
+	case SQLDialectSQLite3:
+		statements = []string{
+			fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	session_id TEXT NOT NULL,
+	message TEXT NOT NULL
+)`, h.tableName),
+			fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %[1]s_session_id ON %[1]s (session_id)`, h.tableName),
+		}
+	case SQLDialectPostgreSQL:
+		statements = []string{
+			fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	id BIGSERIAL PRIMARY KEY,
+	session_id TEXT NOT NULL,
+	message TEXT NOT NULL
+)`, h.tableName),
+			fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %[1]s_session_id ON %[1]s (session_id)`, h.tableName),
+		}
+	case SQLDialectMySQL:
+		statements = []string{
+			fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	id BIGINT AUTO_INCREMENT PRIMARY KEY,
+	session_id VARCHAR(255) NOT NULL,
+	message LONGTEXT NOT NULL,
+	INDEX (session_id)
+)`, h.tableName),
+		}
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownSQLDialect, h.dialect)
+	}
+
+	for _, statement := range statements {
+		if _, err := h.db.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// placeholder returns the placeholder of the n-th (1-based) query argument.
+func (h *SQLChatMessageHistory) placeholder(n int) string {
+	if h.dialect == SQLDialectPostgreSQL {
+		return fmt.Sprintf("$%d", n)
+	}
+	return "?"
+}
+
+// Messages returns all messages of the session, in the order they were added.
+func (h *SQLChatMessageHistory) Messages(ctx context.Context) ([]schema.ChatMessage, error) {
+	_, messages, err := h.query(ctx, h.db)
+	return messages, err
+}
+
+// AddAIMessage adds an AIMessage to the chat message history.
+func (h *SQLChatMessageHistory) AddAIMessage(ctx context.Context, text string) error {
+	return h.AddMessage(ctx, schema.AIChatMessage{Content: text})
+}
+
+// AddUserMessage adds an user to the chat message history.
+func (h *SQLChatMessageHistory) AddUserMessage(ctx context.Context, text string) error {
+	return h.AddMessage(ctx, schema.HumanChatMessage{Content: text})
+}
+
+// AddMessage adds a message to the chat message history.
+func (h *SQLChatMessageHistory) AddMessage(ctx context.Context, message schema.ChatMessage) error {
+	return h.insert(ctx, h.db, message)
+}
+
+// AddMessages adds the messages to the chat message history in a single transaction.
+func (h *SQLChatMessageHistory) AddMessages(ctx context.Context, messages []schema.ChatMessage) error {
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, message := range messages {
+		if err := h.insert(ctx, tx, message); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Clear removes all messages of the session.
+func (h *SQLChatMessageHistory) Clear(ctx context.Context) error {
+	return h.clear(ctx, h.db)
+}
+
+// SetMessages replaces all messages of the session in a single transaction.
+func (h *SQLChatMessageHistory) SetMessages(ctx context.Context, messages []schema.ChatMessage) error {
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := h.clear(ctx, tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	for _, message := range messages {
+		if err := h.insert(ctx, tx, message); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// TrimMessages removes the oldest messages of the session, keeping the ones returned by
+// trim, in a single transaction.
+func (h *SQLChatMessageHistory) TrimMessages(
+	ctx context.Context, trim func(messages []schema.ChatMessage) []schema.ChatMessage,
+) error {
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	ids, messages, err := h.query(ctx, tx)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	numRemoved := len(messages) - len(trim(messages))
+	if numRemoved <= 0 {
+		return tx.Rollback()
+	}
+
+	// Messages added concurrently have greater ids and are kept.
+	_, err = tx.ExecContext(ctx, fmt.Sprintf(
+		"DELETE FROM %s WHERE session_id = %s AND id <= %s",
+		h.tableName, h.placeholder(1), h.placeholder(2),
+	), h.sessionID, ids[numRemoved-1])
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// execer is implemented by both *sql.DB and *sql.Tx.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// queryer is implemented by both *sql.DB and *sql.Tx.
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// query returns the ids and the messages of the session, in the order they were added.
+func (h *SQLChatMessageHistory) query(ctx context.Context, db queryer) ([]int64, []schema.ChatMessage, error) {
+	rows, err := db.QueryContext(ctx, fmt.Sprintf(
+		"SELECT id, message FROM %s WHERE session_id = %s ORDER BY id",
+		h.tableName, h.placeholder(1),
+	), h.sessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]int64, 0)
+	messages := make([]schema.ChatMessage, 0)
+	for rows.Next() {
+		var id int64
+		var data string
+		if err := rows.Scan(&id, &data); err != nil {
+			return nil, nil, err
+		}
+
+		var model schema.ChatMessageModel
+		if err := json.Unmarshal([]byte(data), &model); err != nil {
+			return nil, nil, err
+		}
+		message, err := model.ToChatMessage()
+		if err != nil {
+			return nil, nil, err
+		}
+		ids = append(ids, id)
+		messages = append(messages, message)
+	}
+
+	return ids, messages, rows.Err()
+}
+
+func (h *SQLChatMessageHistory) insert(ctx context.Context, db execer, message schema.ChatMessage) error {
+	model, err := schema.ConvertChatMessageToModel(message)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(model)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, fmt.Sprintf(
+		"INSERT INTO %s (session_id, message) VALUES (%s, %s)",
+		h.tableName, h.placeholder(1), h.placeholder(2),
+	), h.sessionID, string(data))
+	return err
+}
+
+func (h *SQLChatMessageHistory) clear(ctx context.Context, db execer) error {
+	_, err := db.ExecContext(ctx, fmt.Sprintf(
+		"DELETE FROM %s WHERE session_id = %s",
+		h.tableName, h.placeholder(1),
+	), h.sessionID)
+	return err
+}
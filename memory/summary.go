@@ -0,0 +1,177 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ErrNoGeneration is returned when the llm used by a memory returns no generation.
+var ErrNoGeneration = errors.New("no generation returned by the llm")
+
+//nolint:lll
+const _summaryTemplate = `Progressively summarize the lines of conversation provided, adding onto the previous summary returning a new summary.
+
+EXAMPLE
+Current summary:
+The human asks what the AI thinks of artificial intelligence. The AI thinks artificial intelligence is a force for good.
+
+New lines of conversation:
+Human: Why do you think artificial intelligence is a force for good?
+AI: Because artificial intelligence will help humans reach their full potential.
+
+New summary:
+The human asks what the AI thinks of artificial intelligence. The AI thinks artificial intelligence is a force for good because it will help humans reach their full potential.
+END OF EXAMPLE
+
+Current summary:
+{{.summary}}
+
+New lines of conversation:
+{{.new_lines}}
+
+New summary:`
+
+// NewSummaryPrompt returns the default prompt used to update the summary of a
+// conversation. It expects the variables "summary" and "new_lines".
+func NewSummaryPrompt() prompts.PromptTemplate {
+	return prompts.NewPromptTemplate(_summaryTemplate, []string{"summary", "new_lines"})
+}
+
+// ConversationSummary is a memory that keeps a running summary of the conversation,
+// generated by an llm. Only the summary is kept and loaded as memory, the messages
+// are not saved in the chat history.
+type ConversationSummary struct {
+	ConversationBuffer
+	LLM llms.LanguageModel
+	// SummaryPrompt is the prompt used to update the summary with new messages.
+	SummaryPrompt prompts.FormatPrompter
+	// Summary is the current summary of the conversation.
+	Summary string
+
+	// mu guards the summary, and serializes its updates.
+	mu sync.Mutex
+}
+
+// Statically assert that ConversationSummary implement the memory interface.
+var _ schema.Memory = &ConversationSummary{}
+
+// NewConversationSummary is a function for creating a new summary memory.
+func NewConversationSummary(llm llms.LanguageModel, options ...ConversationBufferOption) *ConversationSummary {
+	return &ConversationSummary{
+		ConversationBuffer: *applyBufferOptions(options...),
+		LLM:                llm,
+		SummaryPrompt:      NewSummaryPrompt(),
+	}
+}
+
+// LoadMemoryVariables returns the summary of the conversation. If ReturnMessages is
+// set to true the summary is returned as a system message in a slice of schema.ChatMessage.
+func (m *ConversationSummary) LoadMemoryVariables(context.Context, map[string]any) (map[string]any, error) {
+	summary := m.getSummary()
+	if m.ReturnMessages {
+		return map[string]any{
+			m.MemoryKey: []schema.ChatMessage{schema.SystemChatMessage{Content: summary}},
+		}, nil
+	}
+
+	return map[string]any{
+		m.MemoryKey: summary,
+	}, nil
+}
+
+// SaveContext updates the summary with the new messages.
+func (m *ConversationSummary) SaveContext(
+	ctx context.Context, inputValues map[string]any, outputValues map[string]any,
+) error {
+	userInputValue, err := getInputValue(inputValues, m.InputKey)
+	if err != nil {
+		return err
+	}
+	aiOutputValue, err := getInputValue(outputValues, m.OutputKey)
+	if err != nil {
+		return err
+	}
+	messages := []schema.ChatMessage{
+		schema.HumanChatMessage{Content: userInputValue},
+		schema.AIChatMessage{Content: aiOutputValue},
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	summary, err := predictNewSummary(ctx, m.LLM, m.SummaryPrompt, messages, m.Summary,
+		m.HumanPrefix, m.AIPrefix)
+	if err != nil {
+		return err
+	}
+	m.Summary = summary
+
+	return nil
+}
+
+// Clear clears the chat history and the summary.
+func (m *ConversationSummary) Clear(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Summary = ""
+	return m.ConversationBuffer.Clear(ctx)
+}
+
+func (m *ConversationSummary) getSummary() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.Summary
+}
+
+// predictNewSummary asks the llm to add the messages to the current summary.
+func predictNewSummary(
+	ctx context.Context,
+	llm llms.LanguageModel,
+	summaryPrompt prompts.FormatPrompter,
+	messages []schema.ChatMessage,
+	summary string,
+	humanPrefix string,
+	aiPrefix string,
+) (string, error) {
+	newLines, err := schema.GetBufferString(messages, humanPrefix, aiPrefix)
+	if err != nil {
+		return "", err
+	}
+
+	return generate(ctx, llm, summaryPrompt, map[string]any{
+		"summary":   summary,
+		"new_lines": newLines,
+	})
+}
+
+// generate formats the prompt with the values and returns the trimmed text of the
+// first generation of the llm.
+func generate(
+	ctx context.Context,
+	llm llms.LanguageModel,
+	prompt prompts.FormatPrompter,
+	values map[string]any,
+) (string, error) {
+	promptValue, err := prompt.FormatPrompt(values)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := llm.GeneratePrompt(ctx, []schema.PromptValue{promptValue})
+	if err != nil {
+		return "", err
+	}
+	if len(result.Generations) == 0 || len(result.Generations[0]) == 0 {
+		return "", ErrNoGeneration
+	}
+
+	return strings.TrimSpace(result.Generations[0][0].Text), nil
+}
@@ -0,0 +1,135 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ConversationSummaryBuffer is a memory that keeps the most recent messages of the
+// conversation verbatim, up to a token limit, and a running summary of the older
+// messages generated by an llm.
+type ConversationSummaryBuffer struct {
+	ConversationBuffer
+	LLM           llms.LanguageModel
+	MaxTokenLimit int
+	// SummaryPrompt is the prompt used to update the summary with pruned messages.
+	SummaryPrompt prompts.FormatPrompter
+	// Summary is the current summary of the messages pruned from the chat history.
+	Summary string
+
+	// mu guards the summary, and serializes its updates with the pruning of the chat
+	// history.
+	mu sync.Mutex
+}
+
+// Statically assert that ConversationSummaryBuffer implement the memory interface.
+var _ schema.Memory = &ConversationSummaryBuffer{}
+
+// NewConversationSummaryBuffer is a function for creating a new summary buffer memory.
+func NewConversationSummaryBuffer(
+	llm llms.LanguageModel,
+	maxTokenLimit int,
+	options ...ConversationBufferOption,
+) *ConversationSummaryBuffer {
+	return &ConversationSummaryBuffer{
+		ConversationBuffer: *applyBufferOptions(options...),
+		LLM:                llm,
+		MaxTokenLimit:      maxTokenLimit,
+		SummaryPrompt:      NewSummaryPrompt(),
+	}
+}
+
+// LoadMemoryVariables returns the summary of the older messages followed by the
+// recent messages. The summary is added as a system message, if there is one.
+func (m *ConversationSummaryBuffer) LoadMemoryVariables(
+	ctx context.Context, _ map[string]any,
+) (map[string]any, error) {
+	messages, err := m.ChatHistory.Messages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if summary := m.getSummary(); summary != "" {
+		messages = append([]schema.ChatMessage{schema.SystemChatMessage{Content: summary}}, messages...)
+	}
+
+	if m.ReturnMessages {
+		return map[string]any{
+			m.MemoryKey: messages,
+		}, nil
+	}
+
+	bufferString, err := schema.GetBufferString(messages, m.HumanPrefix, m.AIPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		m.MemoryKey: bufferString,
+	}, nil
+}
+
+// SaveContext saves the new messages in the chat history. If the chat history
+// exceeds the token limit, as counted by llms.CountMessagesTokens, the oldest
+// messages are added to the summary and then removed from the chat history
+// atomically, see schema.ChatMessageHistory.TrimMessages.
+func (m *ConversationSummaryBuffer) SaveContext(
+	ctx context.Context, inputValues map[string]any, outputValues map[string]any,
+) error {
+	if err := m.ConversationBuffer.SaveContext(ctx, inputValues, outputValues); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	messages, err := m.ChatHistory.Messages(ctx)
+	if err != nil {
+		return err
+	}
+
+	numPruned := len(messages) - len(llms.TrimMessages(m.LLM.GetNumTokens, messages, m.MaxTokenLimit))
+	if numPruned == 0 {
+		return nil
+	}
+
+	summary, err := predictNewSummary(ctx, m.LLM, m.SummaryPrompt, messages[:numPruned], m.Summary,
+		m.HumanPrefix, m.AIPrefix)
+	if err != nil {
+		return err
+	}
+
+	// Messages are only added concurrently, so the pruned messages are still the oldest.
+	err = m.ChatHistory.TrimMessages(ctx, func(messages []schema.ChatMessage) []schema.ChatMessage {
+		if len(messages) < numPruned {
+			return nil
+		}
+		return messages[numPruned:]
+	})
+	if err != nil {
+		return err
+	}
+	m.Summary = summary
+
+	return nil
+}
+
+// Clear clears the chat history and the summary.
+func (m *ConversationSummaryBuffer) Clear(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Summary = ""
+	return m.ConversationBuffer.Clear(ctx)
+}
+
+func (m *ConversationSummaryBuffer) getSummary() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.Summary
+}
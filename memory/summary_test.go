@@ -0,0 +1,141 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// testLanguageModel returns the given completions in order, records the prompts and
+// counts words as tokens.
+type testLanguageModel struct {
+	completions []string
+	prompts     []string
+}
+
+func (l *testLanguageModel) GeneratePrompt(_ context.Context, promptValues []schema.PromptValue, _ ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	l.prompts = append(l.prompts, promptValues[0].String())
+	completion := l.completions[0]
+	l.completions = l.completions[1:]
+	return llms.LLMResult{
+		Generations: [][]*llms.Generation{{&llms.Generation{Text: completion}}},
+	}, nil
+}
+
+func (l *testLanguageModel) GetNumTokens(text string) int {
+	return len(strings.Fields(text))
+}
+
+func TestConversationSummary(t *testing.T) {
+	t.Parallel()
+
+	llm := &testLanguageModel{completions: []string{
+		" The human greets the AI. ",
+		"The human greets the AI and asks for help.",
+	}}
+	m := NewConversationSummary(llm)
+
+	result, err := m.LoadMemoryVariables(context.Background(), map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": ""}, result)
+
+	err = m.SaveContext(context.Background(), map[string]any{"input": "hi"}, map[string]any{"output": "hello"})
+	require.NoError(t, err)
+	err = m.SaveContext(context.Background(), map[string]any{"input": "help"}, map[string]any{"output": "sure"})
+	require.NoError(t, err)
+
+	require.Len(t, llm.prompts, 2)
+	assert.True(t, strings.Contains(llm.prompts[1], "Current summary:\nThe human greets the AI.\n"))
+	messages, err := m.ChatHistory.Messages(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+	assert.True(t, strings.Contains(llm.prompts[1], "New lines of conversation:\nHuman: help\nAI: sure\n"))
+
+	result, err = m.LoadMemoryVariables(context.Background(), map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": "The human greets the AI and asks for help."}, result)
+
+	m.ReturnMessages = true
+	result, err = m.LoadMemoryVariables(context.Background(), map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": []schema.ChatMessage{
+		schema.SystemChatMessage{Content: "The human greets the AI and asks for help."},
+	}}, result)
+
+	require.NoError(t, m.Clear(context.Background()))
+	assert.Equal(t, "", m.Summary)
+}
+
+func TestConversationSummaryBuffer(t *testing.T) {
+	t.Parallel()
+
+	llm := &testLanguageModel{completions: []string{"The human says one."}}
+	m := NewConversationSummaryBuffer(llm, 23)
+
+	err := m.SaveContext(context.Background(), map[string]any{"input": "one"}, map[string]any{"output": "two"})
+	require.NoError(t, err)
+	assert.Empty(t, llm.prompts)
+
+	// Four one-word messages use 3 + 4*(3+1+1) = 23 tokens with the word counting llm.
+	err = m.SaveContext(context.Background(), map[string]any{"input": "three"}, map[string]any{"output": "four"})
+	require.NoError(t, err)
+	assert.Empty(t, llm.prompts)
+
+	err = m.SaveContext(context.Background(), map[string]any{"input": "five"}, map[string]any{"output": "six"})
+	require.NoError(t, err)
+	require.Len(t, llm.prompts, 1)
+	assert.True(t, strings.Contains(llm.prompts[0], "New lines of conversation:\nHuman: one\nAI: two\n"))
+
+	result, err := m.LoadMemoryVariables(context.Background(), map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"history": "System: The human says one.\nHuman: three\nAI: four\nHuman: five\nAI: six",
+	}, result)
+}
+
+// constantLanguageModel always returns the same completion and counts words as tokens.
+type constantLanguageModel struct {
+	completion string
+}
+
+func (l constantLanguageModel) GeneratePrompt(context.Context, []schema.PromptValue, ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	return llms.LLMResult{
+		Generations: [][]*llms.Generation{{&llms.Generation{Text: l.completion}}},
+	}, nil
+}
+
+func (l constantLanguageModel) GetNumTokens(text string) int {
+	return len(strings.Fields(text))
+}
+
+func TestConversationSummaryBufferConcurrent(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	m := NewConversationSummaryBuffer(constantLanguageModel{completion: "The human greets the AI."}, 4)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				assert.NoError(t, m.SaveContext(ctx, map[string]any{"input": "hi"}, map[string]any{"output": "hello"}))
+				_, err := m.LoadMemoryVariables(ctx, map[string]any{})
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	messages, err := m.ChatHistory.Messages(ctx)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(messages), 4)
+	assert.Equal(t, "The human greets the AI.", m.Summary)
+}
@@ -45,6 +45,8 @@ func (tb *ConversationTokenBuffer) LoadMemoryVariables(
 }
 
 // SaveContext uses ConversationBuffer method for saving context and prunes memory buffer if needed.
+// Messages are counted the way chat models count them, see llms.CountMessagesTokens, and the
+// oldest messages are removed atomically, see schema.ChatMessageHistory.TrimMessages.
 func (tb *ConversationTokenBuffer) SaveContext(
 	ctx context.Context, inputValues map[string]any, outputValues map[string]any,
 ) error {
@@ -52,58 +54,13 @@ func (tb *ConversationTokenBuffer) SaveContext(
 	if err != nil {
 		return err
 	}
-	currBufferLength, err := tb.getNumTokensFromMessages(ctx)
-	if err != nil {
-		return err
-	}
-
-	if currBufferLength > tb.MaxTokenLimit {
-		// while currBufferLength is greater than MaxTokenLimit we keep removing messages from the memory
-		// from the oldest
-		for currBufferLength > tb.MaxTokenLimit {
-			messages, err := tb.ChatHistory.Messages(ctx)
-			if err != nil {
-				return err
-			}
-
-			if len(messages) == 0 {
-				break
-			}
-
-			err = tb.ChatHistory.SetMessages(ctx, append(messages[:0], messages[1:]...))
-			if err != nil {
-				return err
-			}
-
-			currBufferLength, err = tb.getNumTokensFromMessages(ctx)
-			if err != nil {
-				return err
-			}
-		}
-	}
 
-	return nil
+	return tb.ChatHistory.TrimMessages(ctx, func(messages []schema.ChatMessage) []schema.ChatMessage {
+		return llms.TrimMessages(tb.LLM.GetNumTokens, messages, tb.MaxTokenLimit)
+	})
 }
 
 // Clear uses ConversationBuffer method for clearing buffer memory.
 func (tb *ConversationTokenBuffer) Clear(ctx context.Context) error {
 	return tb.ConversationBuffer.Clear(ctx)
 }
-
-func (tb *ConversationTokenBuffer) getNumTokensFromMessages(ctx context.Context) (int, error) {
-	messages, err := tb.ChatHistory.Messages(ctx)
-	if err != nil {
-		return 0, err
-	}
-
-	bufferString, err := schema.GetBufferString(
-		messages,
-		tb.ConversationBuffer.HumanPrefix,
-		tb.ConversationBuffer.AIPrefix,
-	)
-	if err != nil {
-		return 0, err
-	}
-
-	return tb.LLM.GetNumTokens(bufferString), nil
-}
@@ -3,10 +3,12 @@ package memory
 import (
 	"context"
 	"os"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/llms/openai"
 	"github.com/tmc/langchaingo/schema"
 )
@@ -94,3 +96,44 @@ func TestTokenBufferMemoryWithPreLoadedHistory(t *testing.T) {
 	expected := map[string]any{"history": "Human: bar\nAI: foo"}
 	assert.Equal(t, expected, result)
 }
+
+func TestTokenBufferMemoryPrunes(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	// Every one-word message uses 5 tokens with the word counting llm, plus 3 for the reply.
+	m := NewConversationTokenBuffer(&testLanguageModel{}, 13)
+
+	err := m.SaveContext(ctx, map[string]any{"input": "one"}, map[string]any{"output": "two"})
+	require.NoError(t, err)
+	err = m.SaveContext(ctx, map[string]any{"input": "three"}, map[string]any{"output": "four"})
+	require.NoError(t, err)
+
+	result, err := m.LoadMemoryVariables(ctx, map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": "Human: three\nAI: four"}, result)
+}
+
+func TestTokenBufferMemoryConcurrent(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	llm := &testLanguageModel{}
+	m := NewConversationTokenBuffer(llm, 50)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				assert.NoError(t, m.SaveContext(ctx, map[string]any{"input": "hi"}, map[string]any{"output": "hello"}))
+			}
+		}()
+	}
+	wg.Wait()
+
+	messages, err := m.ChatHistory.Messages(ctx)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, llms.CountMessagesTokens(llm.GetNumTokens, messages), 50)
+}
@@ -0,0 +1,169 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// ErrClearUnsupported is returned when clearing a memory whose vector store can not
+// delete documents.
+var ErrClearUnsupported = errors.New("vector store does not support deleting documents")
+
+const (
+	// _vectorStoreInputMetadataKey is the metadata key under which the input of an
+	// exchange is stored in the vector store.
+	_vectorStoreInputMetadataKey = "input"
+	// _vectorStoreOutputMetadataKey is the metadata key under which the output of an
+	// exchange is stored in the vector store.
+	_vectorStoreOutputMetadataKey = "output"
+
+	_defaultVectorStoreNumDocuments = 4
+)
+
+// VectorStoreRetriever is a memory that stores every exchange between the human and
+// the AI as a document in a vector store. When loading memory variables, the exchanges
+// most relevant to the current input are retrieved, regardless of when they happened.
+type VectorStoreRetriever struct {
+	ConversationBuffer
+	VectorStore vectorstores.VectorStore
+	// NumDocuments is the number of past exchanges to retrieve.
+	NumDocuments int
+	// AddOptions are used when adding exchanges to the vector store, e.g. to set a name
+	// space.
+	AddOptions []vectorstores.Option
+	// SearchOptions are used when searching and clearing the vector store, e.g. to set
+	// the same name space as AddOptions, or to filter on Metadata.
+	SearchOptions []vectorstores.Option
+	// Metadata is added to the metadata of every saved exchange, so that the filters of
+	// SearchOptions can select the exchanges of this memory.
+	Metadata map[string]any
+}
+
+// Statically assert that VectorStoreRetriever implement the memory interface.
+var _ schema.Memory = &VectorStoreRetriever{}
+
+// NewVectorStoreRetriever is a function for creating a new vector store retriever memory.
+// If numDocuments is not positive, four exchanges are retrieved. The chat history of the
+// conversation buffer options is not used.
+func NewVectorStoreRetriever(
+	vectorStore vectorstores.VectorStore,
+	numDocuments int,
+	options ...ConversationBufferOption,
+) *VectorStoreRetriever {
+	if numDocuments <= 0 {
+		numDocuments = _defaultVectorStoreNumDocuments
+	}
+
+	return &VectorStoreRetriever{
+		ConversationBuffer: *applyBufferOptions(options...),
+		VectorStore:        vectorStore,
+		NumDocuments:       numDocuments,
+	}
+}
+
+// LoadMemoryVariables searches the vector store for the exchanges most relevant to the
+// input. If ReturnMessages is set to true, the exchanges are returned as a slice of
+// schema.ChatMessage. Otherwise, they are returned as a single string with one exchange
+// per paragraph.
+func (m *VectorStoreRetriever) LoadMemoryVariables(
+	ctx context.Context, inputs map[string]any,
+) (map[string]any, error) {
+	query, err := getInputValue(inputs, m.InputKey)
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := m.VectorStore.SimilaritySearch(ctx, query, m.NumDocuments, m.SearchOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.ReturnMessages {
+		messages := make([]schema.ChatMessage, 0, 2*len(docs))
+		for _, doc := range docs {
+			input, okInput := doc.Metadata[_vectorStoreInputMetadataKey].(string)
+			output, okOutput := doc.Metadata[_vectorStoreOutputMetadataKey].(string)
+			if !okInput || !okOutput {
+				return nil, fmt.Errorf("%w: document %q has no exchange metadata",
+					ErrInvalidInputValues, doc.PageContent)
+			}
+			messages = append(messages,
+				schema.HumanChatMessage{Content: input},
+				schema.AIChatMessage{Content: output},
+			)
+		}
+
+		return map[string]any{
+			m.MemoryKey: messages,
+		}, nil
+	}
+
+	contents := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		contents = append(contents, doc.PageContent)
+	}
+
+	return map[string]any{
+		m.MemoryKey: strings.Join(contents, "\n\n"),
+	}, nil
+}
+
+// SaveContext adds the exchange to the vector store as a single document, with the
+// add options. The input and output values are chosen the same way as in
+// ConversationBuffer.SaveContext.
+func (m *VectorStoreRetriever) SaveContext(
+	ctx context.Context,
+	inputValues map[string]any,
+	outputValues map[string]any,
+) error {
+	input, err := getInputValue(inputValues, m.InputKey)
+	if err != nil {
+		return err
+	}
+	output, err := getInputValue(outputValues, m.OutputKey)
+	if err != nil {
+		return err
+	}
+
+	metadata := make(map[string]any, len(m.Metadata)+2) //nolint:gomnd
+	for key, value := range m.Metadata {
+		metadata[key] = value
+	}
+	metadata[_vectorStoreInputMetadataKey] = input
+	metadata[_vectorStoreOutputMetadataKey] = output
+
+	doc := schema.Document{
+		PageContent: fmt.Sprintf("%s: %s\n%s: %s", m.HumanPrefix, input, m.AIPrefix, output),
+		Metadata:    metadata,
+	}
+
+	return m.VectorStore.AddDocuments(ctx, []schema.Document{doc}, m.AddOptions...)
+}
+
+// Clear deletes the documents matching the filters of the search options, or all the
+// documents of their name space if they set no filters, if the vector store implements
+// vectorstores.FilterDeleter. Otherwise ErrClearUnsupported is returned. Search options
+// setting neither filters nor a name space would clear the whole store, so
+// vectorstores.ErrDeleteWithoutFilters is returned instead.
+func (m *VectorStoreRetriever) Clear(ctx context.Context) error {
+	deleter, ok := m.VectorStore.(vectorstores.FilterDeleter)
+	if !ok {
+		return ErrClearUnsupported
+	}
+
+	opts := vectorstores.Options{}
+	for _, opt := range m.SearchOptions {
+		opt(&opts)
+	}
+	options := m.SearchOptions
+	if opts.Filters == nil && opts.NameSpace != "" {
+		options = append(append([]vectorstores.Option{}, options...), vectorstores.WithDeleteAll())
+	}
+
+	return deleter.DeleteDocuments(ctx, options...)
+}
@@ -0,0 +1,122 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// testVectorStore returns the stored documents sharing a word with the query, most
+// recently added first.
+type testVectorStore struct {
+	docs       []schema.Document
+	addOptions [][]vectorstores.Option
+}
+
+var _ vectorstores.VectorStore = &testVectorStore{}
+
+func (s *testVectorStore) AddDocuments(_ context.Context, docs []schema.Document, options ...vectorstores.Option) error { //nolint:lll
+	s.docs = append(s.docs, docs...)
+	s.addOptions = append(s.addOptions, options)
+	return nil
+}
+
+func (s *testVectorStore) SimilaritySearch(_ context.Context, query string, numDocuments int, _ ...vectorstores.Option) ([]schema.Document, error) { //nolint:lll
+	var docs []schema.Document
+	for i := len(s.docs) - 1; i >= 0 && len(docs) < numDocuments; i-- {
+		for _, word := range strings.Fields(query) {
+			if strings.Contains(s.docs[i].PageContent, word) {
+				docs = append(docs, s.docs[i])
+				break
+			}
+		}
+	}
+	return docs, nil
+}
+
+func TestVectorStoreRetriever(t *testing.T) {
+	t.Parallel()
+
+	store := &testVectorStore{}
+	m := NewVectorStoreRetriever(store, 2, WithInputKey("input"))
+	ctx := context.Background()
+
+	exchanges := [][2]string{
+		{"my favorite food is pizza", "noted"},
+		{"my favorite sport is soccer", "great"},
+		{"what time is it", "noon"},
+	}
+	for _, e := range exchanges {
+		err := m.SaveContext(ctx, map[string]any{"input": e[0]}, map[string]any{"output": e[1]})
+		require.NoError(t, err)
+	}
+	require.Len(t, store.docs, 3)
+	assert.Equal(t, "Human: what time is it\nAI: noon", store.docs[2].PageContent)
+
+	result, err := m.LoadMemoryVariables(ctx, map[string]any{"input": "favorite"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"history": "Human: my favorite sport is soccer\nAI: great\n\nHuman: my favorite food is pizza\nAI: noted",
+	}, result)
+
+	m.ReturnMessages = true
+	result, err = m.LoadMemoryVariables(ctx, map[string]any{"input": "time"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": []schema.ChatMessage{
+		schema.HumanChatMessage{Content: "what time is it"},
+		schema.AIChatMessage{Content: "noon"},
+	}}, result)
+
+	_, err = m.LoadMemoryVariables(ctx, map[string]any{"question": "time"})
+	assert.ErrorIs(t, err, ErrInvalidInputValues)
+}
+
+// testDeleterVectorStore is a testVectorStore that can delete its documents.
+type testDeleterVectorStore struct {
+	testVectorStore
+	deleteOptions [][]vectorstores.Option
+}
+
+func (s *testDeleterVectorStore) DeleteDocuments(_ context.Context, options ...vectorstores.Option) error {
+	opts := vectorstores.Options{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	if err := vectorstores.CheckDeleteFilters(opts); err != nil {
+		return err
+	}
+
+	s.deleteOptions = append(s.deleteOptions, options)
+	s.docs = nil
+	return nil
+}
+
+func TestVectorStoreRetrieverClear(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	err := NewVectorStoreRetriever(&testVectorStore{}, 2).Clear(ctx)
+	require.ErrorIs(t, err, ErrClearUnsupported)
+
+	store := &testDeleterVectorStore{}
+	m := NewVectorStoreRetriever(store, 2)
+	require.ErrorIs(t, m.Clear(ctx), vectorstores.ErrDeleteWithoutFilters)
+
+	m.AddOptions = []vectorstores.Option{vectorstores.WithNameSpace("conversation")}
+	m.SearchOptions = []vectorstores.Option{vectorstores.WithNameSpace("conversation")}
+	m.Metadata = map[string]any{"user": "jane"}
+	err = m.SaveContext(ctx, map[string]any{"input": "hello"}, map[string]any{"output": "hi"})
+	require.NoError(t, err)
+	assert.Len(t, store.addOptions[0], 1)
+	assert.Equal(t, map[string]any{"user": "jane", "input": "hello", "output": "hi"}, store.docs[0].Metadata)
+
+	require.NoError(t, m.Clear(ctx))
+	assert.Empty(t, store.docs)
+	require.Len(t, store.deleteOptions, 1)
+	assert.Len(t, store.deleteOptions[0], 2)
+}
@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+const _defaultConversationWindowSize = 5
+
+// ConversationWindowBuffer is a memory that remembers the last K exchanges between the
+// human and the AI. An exchange starts with a human message and contains every message
+// up to the next human message, so AI messages are never separated from the human
+// message they answer.
+type ConversationWindowBuffer struct {
+	ConversationBuffer
+	ConversationWindowSize int
+}
+
+// Statically assert that ConversationWindowBuffer implement the memory interface.
+var _ schema.Memory = &ConversationWindowBuffer{}
+
+// NewConversationWindowBuffer is a function for creating a new window buffer memory
+// keeping the last windowSize exchanges. If windowSize is not positive, five exchanges
+// are kept.
+func NewConversationWindowBuffer(
+	windowSize int,
+	options ...ConversationBufferOption,
+) *ConversationWindowBuffer {
+	if windowSize <= 0 {
+		windowSize = _defaultConversationWindowSize
+	}
+
+	return &ConversationWindowBuffer{
+		ConversationBuffer:     *applyBufferOptions(options...),
+		ConversationWindowSize: windowSize,
+	}
+}
+
+// LoadMemoryVariables returns the messages of the last exchanges. If ReturnMessages is
+// set to true the output is a slice of schema.ChatMessage. Otherwise, the output is a
+// buffer string of the chat messages.
+func (wb *ConversationWindowBuffer) LoadMemoryVariables(
+	ctx context.Context, _ map[string]any,
+) (map[string]any, error) {
+	messages, err := wb.ChatHistory.Messages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	messages = lastExchanges(messages, wb.ConversationWindowSize)
+
+	if wb.ReturnMessages {
+		return map[string]any{
+			wb.MemoryKey: messages,
+		}, nil
+	}
+
+	bufferString, err := schema.GetBufferString(messages, wb.HumanPrefix, wb.AIPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		wb.MemoryKey: bufferString,
+	}, nil
+}
+
+// lastExchanges returns the messages starting at the k-th human message from the end.
+// If there are at most k human messages, all messages are returned.
+func lastExchanges(messages []schema.ChatMessage, k int) []schema.ChatMessage {
+	numExchanges := 0
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].GetType() != schema.ChatMessageTypeHuman {
+			continue
+		}
+		numExchanges++
+		if numExchanges == k {
+			return messages[i:]
+		}
+	}
+
+	return messages
+}
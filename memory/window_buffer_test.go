@@ -0,0 +1,65 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestWindowBufferMemory(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	m := NewConversationWindowBuffer(2)
+
+	result, err := m.LoadMemoryVariables(ctx, map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": ""}, result)
+
+	for _, exchange := range [][2]string{{"1", "one"}, {"2", "two"}, {"3", "three"}} {
+		err = m.SaveContext(ctx, map[string]any{"input": exchange[0]}, map[string]any{"output": exchange[1]})
+		require.NoError(t, err)
+	}
+
+	result, err = m.LoadMemoryVariables(ctx, map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": "Human: 2\nAI: two\nHuman: 3\nAI: three"}, result)
+
+	// The full history is kept.
+	messages, err := m.ChatHistory.Messages(ctx)
+	require.NoError(t, err)
+	assert.Len(t, messages, 6)
+}
+
+func TestWindowBufferMemoryKeepsExchangesWhole(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	m := NewConversationWindowBuffer(1,
+		WithReturnMessages(true),
+		WithChatHistory(NewChatMessageHistory(WithPreviousMessages([]schema.ChatMessage{
+			schema.SystemChatMessage{Content: "be nice"},
+			schema.HumanChatMessage{Content: "weather?"},
+			schema.AIChatMessage{FunctionCall: &schema.FunctionCall{Name: "weather"}},
+			schema.FunctionChatMessage{Name: "weather", Content: "sunny"},
+			schema.AIChatMessage{Content: "It is sunny."},
+		}))),
+	)
+
+	result, err := m.LoadMemoryVariables(ctx, map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": []schema.ChatMessage{
+		schema.HumanChatMessage{Content: "weather?"},
+		schema.AIChatMessage{FunctionCall: &schema.FunctionCall{Name: "weather"}},
+		schema.FunctionChatMessage{Name: "weather", Content: "sunny"},
+		schema.AIChatMessage{Content: "It is sunny."},
+	}}, result)
+
+	m.ConversationWindowSize = 3
+	result, err = m.LoadMemoryVariables(ctx, map[string]any{})
+	require.NoError(t, err)
+	assert.Len(t, result["history"], 5)
+}
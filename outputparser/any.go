@@ -0,0 +1,55 @@
+package outputparser
+
+import (
+	"context"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// Any is an output parser that wraps a parser of a specific type, such as JSON,
+// so it can be used where a schema.OutputParser[any] is expected, e.g. in an
+// LLMChain. The parsed values are returned unchanged as any.
+type Any[T any] struct {
+	Parser schema.OutputParser[T]
+}
+
+// NewAny creates a new Any output parser wrapping the parser.
+func NewAny[T any](parser schema.OutputParser[T]) Any[T] {
+	return Any[T]{
+		Parser: parser,
+	}
+}
+
+// Statically assert that Any implements the OutputParser interface.
+var _ schema.OutputParser[any] = Any[string]{}
+
+// GetFormatInstructions returns the format instructions of the wrapped parser.
+func (p Any[T]) GetFormatInstructions() string {
+	return p.Parser.GetFormatInstructions()
+}
+
+// Parse parses the text with the wrapped parser.
+func (p Any[T]) Parse(text string) (any, error) {
+	return p.Parser.Parse(text)
+}
+
+// ParseWithPrompt parses the text with the wrapped parser.
+func (p Any[T]) ParseWithPrompt(text string, prompt schema.PromptValue) (any, error) {
+	return p.Parser.ParseWithPrompt(text, prompt)
+}
+
+// ParseWithPromptContext parses the text with the wrapped parser, passing the
+// context along if the wrapped parser accepts one.
+func (p Any[T]) ParseWithPromptContext(ctx context.Context, text string, prompt schema.PromptValue) (any, error) {
+	if parser, ok := p.Parser.(interface {
+		ParseWithPromptContext(ctx context.Context, text string, prompt schema.PromptValue) (T, error)
+	}); ok {
+		return parser.ParseWithPromptContext(ctx, text, prompt)
+	}
+	return p.Parser.ParseWithPrompt(text, prompt)
+}
+
+// Type returns the type of the wrapped parser.
+func (p Any[T]) Type() string {
+	return p.Parser.Type()
+}
@@ -15,5 +15,13 @@ The outputparser package includes the following parsers:
     and returns map[string]string of the regex groups.
   - RegexDict: a parser that searches a string for values in a dictionary format,
     and returns a map[string]string of the keys and their associated value.
+  - JSON: a generic parser that unmarshals JSON output into a value of a given type,
+    with format instructions generated from the schema of that type.
+  - Retry: a parser that wraps another parser and asks the LLM for a new completion,
+    given the original prompt and the error, when parsing fails.
+  - Fixing: a parser that wraps another parser and asks the LLM to fix the output,
+    given the format instructions and the error, when parsing fails.
+  - Any: a parser that wraps a typed parser, such as JSON, so it can be used where a
+    parser returning any is expected, e.g. in chains.
 */
 package outputparser
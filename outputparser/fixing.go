@@ -0,0 +1,129 @@
+package outputparser
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// _fixingPromptTemplate is the prompt used to ask the llm to fix a completion
+// that failed to parse. The prompt section is left out when the prompt is unknown.
+const _fixingPromptTemplate = `{{if .prompt}}Prompt:
+--------------
+{{.prompt}}
+--------------
+{{end}}Instructions:
+--------------
+{{.instructions}}
+--------------
+Completion:
+--------------
+{{.completion}}
+--------------
+
+Above, the Completion did not satisfy the constraints given in the Instructions.
+Error:
+--------------
+{{.error}}
+--------------
+
+Please try again. Please only respond with an answer that satisfies the constraints laid out in the Instructions:`
+
+// Fixing is an output parser that wraps another parser. If the wrapped parser
+// returns a ParseError, the llm is given the prompt if known, the format
+// instructions of the wrapped parser, the completion and the error, and asked to
+// fix the completion. The fixed
+// completion is parsed again, up to MaxRetries times.
+type Fixing[T any] struct {
+	Parser     schema.OutputParser[T]
+	LLM        llms.LanguageModel
+	MaxRetries int
+}
+
+// NewFixing creates a new output fixing parser. If maxRetries is not positive,
+// the llm is asked to fix the completion once.
+func NewFixing[T any](parser schema.OutputParser[T], llm llms.LanguageModel, maxRetries int) Fixing[T] {
+	if maxRetries <= 0 {
+		maxRetries = _defaultMaxRetries
+	}
+
+	return Fixing[T]{
+		Parser:     parser,
+		LLM:        llm,
+		MaxRetries: maxRetries,
+	}
+}
+
+// Statically assert that Fixing implements the OutputParser interface.
+var _ schema.OutputParser[any] = Fixing[any]{}
+
+// GetFormatInstructions returns the format instructions of the wrapped parser.
+func (p Fixing[T]) GetFormatInstructions() string {
+	return p.Parser.GetFormatInstructions()
+}
+
+// Parse parses the text with the wrapped parser and asks the llm to fix the
+// text if parsing fails.
+func (p Fixing[T]) Parse(text string) (T, error) {
+	return p.ParseContext(context.Background(), text)
+}
+
+// ParseContext is like Parse but uses the context for the llm calls.
+func (p Fixing[T]) ParseContext(ctx context.Context, text string) (T, error) {
+	return p.ParseWithPromptContext(ctx, text, nil)
+}
+
+// ParseWithPrompt parses the text with the wrapped parser and asks the llm to
+// fix the text, given the prompt, if parsing fails.
+func (p Fixing[T]) ParseWithPrompt(text string, prompt schema.PromptValue) (T, error) {
+	return p.ParseWithPromptContext(context.Background(), text, prompt)
+}
+
+// ParseWithPromptContext is like ParseWithPrompt but uses the context for the llm calls.
+func (p Fixing[T]) ParseWithPromptContext(ctx context.Context, text string, prompt schema.PromptValue) (T, error) {
+	result, err := p.Parser.ParseWithPrompt(text, prompt)
+	for retries := 0; err != nil && retries < p.MaxRetries; retries++ {
+		if !errors.As(err, &ParseError{}) {
+			return result, err
+		}
+
+		text, err = p.fix(ctx, text, prompt, err)
+		if err != nil {
+			return result, err
+		}
+
+		result, err = p.Parser.ParseWithPrompt(text, prompt)
+	}
+
+	return result, err
+}
+
+func (p Fixing[T]) fix(ctx context.Context, completion string, prompt schema.PromptValue, parseErr error) (string, error) { //nolint:lll
+	promptText := ""
+	if prompt != nil {
+		promptText = prompt.String()
+	}
+
+	fixPrompt, err := prompts.NewPromptTemplate(
+		_fixingPromptTemplate,
+		[]string{"prompt", "instructions", "completion", "error"},
+	).FormatPrompt(map[string]any{
+		"prompt":       promptText,
+		"instructions": p.Parser.GetFormatInstructions(),
+		"completion":   completion,
+		"error":        parseErr.Error(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return generateText(ctx, p.LLM, fixPrompt)
+}
+
+// Type returns the type of the parser.
+func (p Fixing[T]) Type() string {
+	return "output_fixing_parser"
+}
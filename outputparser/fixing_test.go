@@ -0,0 +1,108 @@
+package outputparser_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/outputparser"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestFixing(t *testing.T) {
+	t.Parallel()
+
+	regexDict := outputparser.NewRegexDict(map[string]string{"action": "Action"}, "")
+	llm := &testLanguageModel{completions: []string{"Action: Search"}}
+	parser := outputparser.NewFixing[any](regexDict, llm, 1)
+
+	actual, err := parser.Parse("action = Search")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"action": "Search"}, actual)
+
+	require.Len(t, llm.prompts, 1)
+	assert.True(t, strings.Contains(llm.prompts[0], regexDict.GetFormatInstructions()))
+	assert.True(t, strings.Contains(llm.prompts[0], "action = Search"))
+	assert.True(t, strings.Contains(llm.prompts[0], "No match found for expression"))
+}
+
+func TestFixingMaxRetries(t *testing.T) {
+	t.Parallel()
+
+	structured := outputparser.NewStructured([]outputparser.ResponseSchema{
+		{Name: "answer", Description: "The answer to the question"},
+	})
+	llm := &testLanguageModel{completions: []string{
+		"{\"answer\": \"Paris\"}",
+		"```json\n{\"source\": \"wiki\"}\n```",
+	}}
+	parser := outputparser.NewFixing[any](structured, llm, 2)
+
+	_, err := parser.Parse("Paris")
+	require.Error(t, err)
+	assert.Len(t, llm.prompts, 2)
+
+	actual, err := parser.Parse("```json\n{\"answer\": \"Paris\"}\n```")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"answer": "Paris"}, actual)
+	assert.Len(t, llm.prompts, 2)
+}
+
+func TestFixingStructured(t *testing.T) {
+	t.Parallel()
+
+	structured := outputparser.NewStructured([]outputparser.ResponseSchema{
+		{Name: "answer", Description: "The answer to the question"},
+	})
+	llm := &testLanguageModel{completions: []string{"```json\n{\"answer\": \"Paris\"}\n```"}}
+	parser := outputparser.NewFixing[any](structured, llm, 2)
+
+	// Invalid JSON is fixed like other formatting errors.
+	actual, err := parser.Parse("```json\n{\"answer\": \"Paris\",}\n```")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"answer": "Paris"}, actual)
+	require.Len(t, llm.prompts, 1)
+	assert.True(t, strings.Contains(llm.prompts[0], "invalid character"))
+}
+
+func TestFixingWithPrompt(t *testing.T) {
+	t.Parallel()
+
+	regexDict := outputparser.NewRegexDict(map[string]string{"action": "Action"}, "")
+	llm := &testLanguageModel{completions: []string{"Action: Search"}}
+	parser := outputparser.NewFixing[any](regexDict, llm, 1)
+
+	_, err := parser.ParseWithPrompt("action = Search", prompts.StringPromptValue("Which action?"))
+	require.NoError(t, err)
+	require.Len(t, llm.prompts, 1)
+	assert.True(t, strings.Contains(llm.prompts[0], "Which action?"))
+}
+
+// failingParser fails to parse with an error that is not a ParseError.
+type failingParser struct {
+	outputparser.Simple
+}
+
+var errFailingParser = errors.New("failing parser")
+
+func (failingParser) Parse(string) (any, error) {
+	return nil, errFailingParser
+}
+
+func (p failingParser) ParseWithPrompt(text string, _ schema.PromptValue) (any, error) {
+	return p.Parse(text)
+}
+
+func TestFixingOnlyFixesParseErrors(t *testing.T) {
+	t.Parallel()
+
+	llm := &testLanguageModel{}
+	parser := outputparser.NewFixing[any](failingParser{}, llm, 1)
+
+	_, err := parser.Parse("text")
+	require.ErrorIs(t, err, errFailingParser)
+	assert.Empty(t, llm.prompts)
+}
@@ -0,0 +1,165 @@
+package outputparser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/tmc/langchaingo/jsonschema"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// _jsonFormatInstructionTemplate is a template for the format instructions of
+// the json output parser. The verb is the json schema of the expected output.
+const _jsonFormatInstructionTemplate = "The output should be formatted as a JSON instance that conforms to the JSON schema below.\n\nAs an example, for the schema {\"type\":\"object\",\"properties\":{\"foo\":{\"type\":\"array\",\"items\":{\"type\":\"string\"}}},\"required\":[\"foo\"]}\nthe object {\"foo\": [\"bar\", \"baz\"]} is a well-formatted instance of the schema. The object {\"properties\": {\"foo\": [\"bar\", \"baz\"]}} is not well-formatted.\n\nHere is the output schema:\n```json\n%s\n```" //nolint:lll
+
+// nolint:gochecknoglobals
+var _jsonCodeFence = regexp.MustCompile("(?s)```(?:json|JSON)?\\s*(.*?)```")
+
+// JSON is an output parser that unmarshals the JSON output of an llm into a
+// value of type T. The output may be wrapped in a markdown code snippet, be
+// preceded or followed by prose and contain trailing commas. If a schema is
+// set, the output is validated against it before being unmarshaled.
+type JSON[T any] struct {
+	Schema *jsonschema.Definition
+}
+
+// NewJSON creates a new JSON output parser for the type T. The schema used in
+// the format instructions and for validation is reflected from T, see
+// jsonschema.Reflect for the supported struct tags.
+func NewJSON[T any]() (JSON[T], error) {
+	var zero T
+	def, err := jsonschema.ReflectType(reflect.TypeOf(&zero).Elem())
+	if err != nil {
+		return JSON[T]{}, err
+	}
+
+	return JSON[T]{
+		Schema: def,
+	}, nil
+}
+
+// Statically assert that JSON implements the OutputParser interface.
+var _ schema.OutputParser[any] = JSON[any]{}
+
+// GetFormatInstructions returns instructions on the expected output format.
+func (p JSON[T]) GetFormatInstructions() string {
+	if p.Schema == nil {
+		return "The output should be formatted as a JSON instance."
+	}
+
+	schemaJSON, err := json.Marshal(p.Schema)
+	if err != nil {
+		return "The output should be formatted as a JSON instance."
+	}
+
+	return fmt.Sprintf(_jsonFormatInstructionTemplate, string(schemaJSON))
+}
+
+// Parse parses the output of an llm into a value of type T.
+func (p JSON[T]) Parse(text string) (T, error) {
+	var result T
+
+	jsonText, ok := extractJSON(text, p.openers())
+	if !ok {
+		return result, ParseError{Text: text, Reason: "no JSON found in output"}
+	}
+
+	if p.Schema != nil {
+		if err := jsonschema.ValidateJSON(*p.Schema, []byte(jsonText)); err != nil {
+			return result, ParseError{Text: text, Reason: err.Error()}
+		}
+	}
+
+	if err := json.Unmarshal([]byte(jsonText), &result); err != nil {
+		return result, ParseError{Text: text, Reason: err.Error()}
+	}
+
+	return result, nil
+}
+
+// ParseWithPrompt does the same as Parse.
+func (p JSON[T]) ParseWithPrompt(text string, _ schema.PromptValue) (T, error) {
+	return p.Parse(text)
+}
+
+// Type returns the type of the parser.
+func (p JSON[T]) Type() string {
+	return "json_parser"
+}
+
+// openers returns the brackets the JSON value of the output can start with.
+func (p JSON[T]) openers() string {
+	if p.Schema != nil {
+		switch p.Schema.Type {
+		case jsonschema.Object:
+			return "{"
+		case jsonschema.Array:
+			return "["
+		}
+	}
+	return "{["
+}
+
+// extractJSON finds the JSON value in the output of an llm. A markdown code
+// snippet is preferred. The first of the opening brackets from which a
+// complete JSON value can be decoded is used, so brackets in leading prose are
+// skipped. Trailing commas are removed.
+func extractJSON(text, openers string) (string, bool) {
+	if match := _jsonCodeFence.FindStringSubmatch(text); match != nil {
+		text = match[1]
+	}
+
+	for start := strings.IndexAny(text, openers); start >= 0; {
+		candidate := removeTrailingCommas(text[start:])
+		decoder := json.NewDecoder(strings.NewReader(candidate))
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err == nil {
+			return string(value), true
+		}
+
+		next := strings.IndexAny(text[start+1:], openers)
+		if next < 0 {
+			break
+		}
+		start += next + 1
+	}
+
+	return "", false
+}
+
+// removeTrailingCommas removes commas directly followed by a closing bracket,
+// ignoring the content of strings.
+func removeTrailingCommas(text string) string {
+	var b bytes.Buffer
+	inString := false
+	escaped := false
+
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case inString:
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == ',':
+			next := strings.TrimLeft(text[i+1:], " \t\r\n")
+			if strings.HasPrefix(next, "}") || strings.HasPrefix(next, "]") {
+				continue
+			}
+		}
+		b.WriteByte(c)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,109 @@
+package outputparser_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/outputparser"
+)
+
+type testAnswer struct {
+	Answer  string   `json:"answer" description:"The answer to the question"`
+	Sources []string `json:"sources,omitempty"`
+}
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+
+	parser, err := outputparser.NewJSON[testAnswer]()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		input    string
+		expected testAnswer
+		err      bool
+	}{
+		{
+			name:     "Plain",
+			input:    `{"answer": "Paris"}`,
+			expected: testAnswer{Answer: "Paris"},
+		},
+		{
+			name:     "CodeFence",
+			input:    "Here you go:\n```json\n{\"answer\": \"Paris\", \"sources\": [\"wiki\"]}\n```\nAnything else?",
+			expected: testAnswer{Answer: "Paris", Sources: []string{"wiki"}},
+		},
+		{
+			name:     "LeadingProseAndTrailingCommas",
+			input:    "Sure! The answer is {\"answer\": \"Paris, France\", \"sources\": [\"a\", \"b\",],}",
+			expected: testAnswer{Answer: "Paris, France", Sources: []string{"a", "b"}},
+		},
+		{
+			name:     "BracketsInLeadingProse",
+			input:    "See [1]: {\"answer\": \"Paris\", \"sources\": [\"[1]\"]} and {maybe}.",
+			expected: testAnswer{Answer: "Paris", Sources: []string{"[1]"}},
+		},
+		{
+			name:     "NullSlice",
+			input:    `{"answer": "Paris", "sources": null}`,
+			expected: testAnswer{Answer: "Paris"},
+		},
+		{
+			name:  "NoJSON",
+			input: "Paris",
+			err:   true,
+		},
+		{
+			name:  "MissingRequired",
+			input: `{"sources": []}`,
+			err:   true,
+		},
+		{
+			name:  "WrongType",
+			input: `{"answer": 1}`,
+			err:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := parser.Parse(tc.input)
+			if tc.err {
+				require.Error(t, err)
+				assert.True(t, errors.As(err, &outputparser.ParseError{}))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestJSONFormatInstructions(t *testing.T) {
+	t.Parallel()
+
+	parser, err := outputparser.NewJSON[testAnswer]()
+	require.NoError(t, err)
+
+	instructions := parser.GetFormatInstructions()
+	assert.True(t, strings.Contains(instructions, `"answer":{"type":"string","description":"The answer to the question"`))
+	assert.True(t, strings.Contains(instructions, `"required":["answer"]`))
+}
+
+func TestJSONSlice(t *testing.T) {
+	t.Parallel()
+
+	parser, err := outputparser.NewJSON[[]int]()
+	require.NoError(t, err)
+
+	actual, err := parser.Parse("The numbers are [1, 2, 3,].")
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, actual)
+}
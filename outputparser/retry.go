@@ -0,0 +1,141 @@
+package outputparser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ErrNoRetryGeneration is returned when the llm returns no generation when
+// asked to retry.
+var ErrNoRetryGeneration = errors.New("no generation returned when retrying")
+
+const (
+	_defaultMaxRetries = 1
+
+	// _retryPromptTemplate is the prompt used to ask the llm for a new completion
+	// after the previous one failed to parse.
+	_retryPromptTemplate = `Prompt:
+{{.prompt}}
+Completion:
+{{.completion}}
+
+Above, the Completion did not satisfy the constraints given in the Prompt.
+Details: {{.error}}
+Please try again:`
+
+	// _retryCompletionTemplate is used instead of _retryPromptTemplate when the
+	// prompt is unknown.
+	_retryCompletionTemplate = `Completion:
+{{.completion}}
+
+Above, the Completion did not satisfy the constraints.
+Details: {{.error}}
+Please try again:`
+)
+
+// Retry is an output parser that wraps another parser. If the wrapped parser
+// returns a ParseError in ParseWithPrompt, the llm is given the original prompt,
+// the completion and the error, and asked for a new completion. This is repeated
+// up to MaxRetries times.
+type Retry[T any] struct {
+	Parser     schema.OutputParser[T]
+	LLM        llms.LanguageModel
+	MaxRetries int
+}
+
+// NewRetry creates a new retry output parser. If maxRetries is not positive, the
+// llm is asked for a new completion once.
+func NewRetry[T any](parser schema.OutputParser[T], llm llms.LanguageModel, maxRetries int) Retry[T] {
+	if maxRetries <= 0 {
+		maxRetries = _defaultMaxRetries
+	}
+
+	return Retry[T]{
+		Parser:     parser,
+		LLM:        llm,
+		MaxRetries: maxRetries,
+	}
+}
+
+// Statically assert that Retry implements the OutputParser interface.
+var _ schema.OutputParser[any] = Retry[any]{}
+
+// GetFormatInstructions returns the format instructions of the wrapped parser.
+func (p Retry[T]) GetFormatInstructions() string {
+	return p.Parser.GetFormatInstructions()
+}
+
+// Parse parses the text with the wrapped parser. No retry is done since the
+// prompt is unknown.
+func (p Retry[T]) Parse(text string) (T, error) {
+	return p.Parser.Parse(text)
+}
+
+// ParseWithPrompt parses the text with the wrapped parser and asks the llm for a
+// new completion if parsing fails. If the prompt is nil, the llm is only given
+// the completion and the error.
+func (p Retry[T]) ParseWithPrompt(text string, prompt schema.PromptValue) (T, error) {
+	return p.ParseWithPromptContext(context.Background(), text, prompt)
+}
+
+// ParseWithPromptContext is like ParseWithPrompt but uses the context for the llm calls.
+func (p Retry[T]) ParseWithPromptContext(ctx context.Context, text string, prompt schema.PromptValue) (T, error) {
+	result, err := p.Parser.ParseWithPrompt(text, prompt)
+	for retries := 0; err != nil && retries < p.MaxRetries; retries++ {
+		if !errors.As(err, &ParseError{}) {
+			return result, err
+		}
+
+		text, err = p.retry(ctx, text, prompt, err)
+		if err != nil {
+			return result, err
+		}
+
+		result, err = p.Parser.ParseWithPrompt(text, prompt)
+	}
+
+	return result, err
+}
+
+func (p Retry[T]) retry(ctx context.Context, completion string, prompt schema.PromptValue, parseErr error) (string, error) { //nolint:lll
+	template := prompts.NewPromptTemplate(_retryCompletionTemplate, []string{"completion", "error"})
+	values := map[string]any{
+		"completion": completion,
+		"error":      parseErr.Error(),
+	}
+	if prompt != nil {
+		template = prompts.NewPromptTemplate(_retryPromptTemplate, []string{"prompt", "completion", "error"})
+		values["prompt"] = prompt.String()
+	}
+
+	retryPrompt, err := template.FormatPrompt(values)
+	if err != nil {
+		return "", err
+	}
+
+	return generateText(ctx, p.LLM, retryPrompt)
+}
+
+// Type returns the type of the parser.
+func (p Retry[T]) Type() string {
+	return "retry_parser"
+}
+
+// generateText returns the text of the first generation of the llm for the prompt.
+func generateText(ctx context.Context, llm llms.LanguageModel, prompt schema.PromptValue) (string, error) {
+	result, err := llm.GeneratePrompt(ctx, []schema.PromptValue{prompt})
+	if err != nil {
+		return "", fmt.Errorf("generating new completion: %w", err)
+	}
+
+	if len(result.Generations) == 0 || len(result.Generations[0]) == 0 {
+		return "", ErrNoRetryGeneration
+	}
+
+	return result.Generations[0][0].Text, nil
+}
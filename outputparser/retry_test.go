@@ -0,0 +1,91 @@
+package outputparser_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/outputparser"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// testLanguageModel returns the given completions in order and records the prompts.
+type testLanguageModel struct {
+	completions []string
+	prompts     []string
+}
+
+func (l *testLanguageModel) GeneratePrompt(_ context.Context, promptValues []schema.PromptValue, _ ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	l.prompts = append(l.prompts, promptValues[0].String())
+	completion := l.completions[0]
+	l.completions = l.completions[1:]
+	return llms.LLMResult{
+		Generations: [][]*llms.Generation{{&llms.Generation{Text: completion}}},
+	}, nil
+}
+
+func (l *testLanguageModel) GetNumTokens(text string) int {
+	return len(text)
+}
+
+func TestRetry(t *testing.T) {
+	t.Parallel()
+
+	jsonParser, err := outputparser.NewJSON[testAnswer]()
+	require.NoError(t, err)
+
+	llm := &testLanguageModel{completions: []string{`{"answer": "Paris"}`}}
+	parser := outputparser.NewRetry[testAnswer](jsonParser, llm, 2)
+	prompt := prompts.StringPromptValue("What is the capital of France?")
+
+	actual, err := parser.ParseWithPrompt(`{"response": "Paris"}`, prompt)
+	require.NoError(t, err)
+	assert.Equal(t, testAnswer{Answer: "Paris"}, actual)
+
+	require.Len(t, llm.prompts, 1)
+	assert.True(t, strings.Contains(llm.prompts[0], "What is the capital of France?"))
+	assert.True(t, strings.Contains(llm.prompts[0], `{"response": "Paris"}`))
+	assert.True(t, strings.Contains(llm.prompts[0], `missing required property "answer"`))
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	t.Parallel()
+
+	jsonParser, err := outputparser.NewJSON[testAnswer]()
+	require.NoError(t, err)
+
+	llm := &testLanguageModel{completions: []string{"Paris", "Still Paris"}}
+	parser := outputparser.NewRetry[testAnswer](jsonParser, llm, 2)
+
+	_, err = parser.ParseWithPrompt("Paris", prompts.StringPromptValue("Capital of France?"))
+	require.Error(t, err)
+	assert.ErrorAs(t, err, &outputparser.ParseError{})
+	assert.Len(t, llm.prompts, 2)
+
+	// Parse has no prompt to retry with.
+	_, err = parser.Parse("Paris")
+	require.Error(t, err)
+	assert.Len(t, llm.prompts, 2)
+}
+
+func TestRetryWithoutPrompt(t *testing.T) {
+	t.Parallel()
+
+	jsonParser, err := outputparser.NewJSON[testAnswer]()
+	require.NoError(t, err)
+
+	llm := &testLanguageModel{completions: []string{`{"answer": "Paris"}`}}
+	parser := outputparser.NewRetry[testAnswer](jsonParser, llm, 1)
+
+	actual, err := parser.ParseWithPrompt("Paris", nil)
+	require.NoError(t, err)
+	assert.Equal(t, testAnswer{Answer: "Paris"}, actual)
+
+	require.Len(t, llm.prompts, 1)
+	assert.False(t, strings.Contains(llm.prompts[0], "Prompt:"))
+	assert.True(t, strings.Contains(llm.prompts[0], "Paris"))
+}
@@ -77,7 +77,7 @@ func (p Structured) parse(text string) (map[string]string, error) {
 	var parsed map[string]string
 	err := json.Unmarshal([]byte(jsonString), &parsed)
 	if err != nil {
-		return nil, err
+		return nil, ParseError{Text: text, Reason: err.Error()}
 	}
 
 	// Validate that the parsed map contains all fields specified in the response
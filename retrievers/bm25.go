@@ -0,0 +1,138 @@
+package retrievers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// BM25 is a retriever ranking documents held in memory by their Okapi BM25 score for the
+// terms of the query. The Score of the documents returned is their BM25 score divided by
+// the score of the best document, so the best document has a score of 1.
+type BM25 struct {
+	CallbacksHandler callbacks.Handler
+
+	docs                []schema.Document
+	termFrequencies     []map[string]int
+	lengths             []int
+	averageLength       float64
+	documentFrequencies map[string]int
+	opts                Options
+}
+
+var _ schema.Retriever = BM25{}
+
+// NewBM25 returns a BM25 retriever searching the documents. The documents are tokenized
+// once, so the retriever must be created again when they change.
+func NewBM25(docs []schema.Document, options ...Option) (BM25, error) {
+	opts, err := applyOptions(_bm25, options...)
+	if err != nil {
+		return BM25{}, err
+	}
+	switch {
+	case opts.NumDocuments < 0:
+		return BM25{}, fmt.Errorf("%w: negative number of documents", ErrInvalidOptions)
+	case opts.K1 < 0:
+		return BM25{}, fmt.Errorf("%w: negative k1", ErrInvalidOptions)
+	case opts.B < 0 || opts.B > 1:
+		return BM25{}, fmt.Errorf("%w: b must be between 0 and 1", ErrInvalidOptions)
+	case opts.Tokenizer == nil:
+		return BM25{}, fmt.Errorf("%w: no tokenizer", ErrInvalidOptions)
+	}
+	if opts.NumDocuments == 0 {
+		opts.NumDocuments = _defaultNumDocuments
+	}
+
+	r := BM25{
+		docs:                docs,
+		termFrequencies:     make([]map[string]int, 0, len(docs)),
+		lengths:             make([]int, 0, len(docs)),
+		documentFrequencies: map[string]int{},
+		opts:                opts,
+	}
+
+	totalLength := 0
+	for _, doc := range docs {
+		terms := opts.Tokenizer(doc.PageContent)
+		frequencies := make(map[string]int, len(terms))
+		for _, term := range terms {
+			frequencies[term]++
+		}
+		for term := range frequencies {
+			r.documentFrequencies[term]++
+		}
+
+		r.termFrequencies = append(r.termFrequencies, frequencies)
+		r.lengths = append(r.lengths, len(terms))
+		totalLength += len(terms)
+	}
+	if totalLength > 0 {
+		r.averageLength = float64(totalLength) / float64(len(docs))
+	}
+
+	return r, nil
+}
+
+// GetRelevantDocuments returns the documents with the highest BM25 scores for the query.
+// Documents containing none of the terms of the query are not returned.
+func (r BM25) GetRelevantDocuments(ctx context.Context, query string) ([]schema.Document, error) {
+	if r.CallbacksHandler != nil {
+		r.CallbacksHandler.HandleRetrieverStart(ctx, query)
+	}
+
+	scores := r.scores(r.opts.Tokenizer(query))
+
+	ranked := make([]int, 0, len(scores))
+	for i, score := range scores {
+		if score > 0 {
+			ranked = append(ranked, i)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool { return scores[ranked[i]] > scores[ranked[j]] })
+	if len(ranked) > r.opts.NumDocuments {
+		ranked = ranked[:r.opts.NumDocuments]
+	}
+
+	docs := make([]schema.Document, 0, len(ranked))
+	for _, i := range ranked {
+		doc := r.docs[i]
+		doc.Score = float32(scores[i] / scores[ranked[0]])
+		docs = append(docs, doc)
+	}
+
+	if r.CallbacksHandler != nil {
+		r.CallbacksHandler.HandleRetrieverEnd(ctx, query, docs)
+	}
+
+	return docs, nil
+}
+
+// scores returns the BM25 score of each document for the terms.
+func (r BM25) scores(terms []string) []float64 {
+	scores := make([]float64, len(r.docs))
+	numDocs := float64(len(r.docs))
+
+	for _, term := range terms {
+		documentFrequency := float64(r.documentFrequencies[term])
+		if documentFrequency == 0 {
+			continue
+		}
+		// The inverse document frequency of Lucene, which is never negative.
+		idf := math.Log(1 + (numDocs-documentFrequency+0.5)/(documentFrequency+0.5))
+
+		for i, frequencies := range r.termFrequencies {
+			tf := float64(frequencies[term])
+			if tf == 0 {
+				continue
+			}
+			norm := 1 - r.opts.B + r.opts.B*float64(r.lengths[i])/r.averageLength
+			scores[i] += idf * tf * (r.opts.K1 + 1) / (tf + r.opts.K1*norm)
+		}
+	}
+
+	return scores
+}
@@ -0,0 +1,93 @@
+package retrievers_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/retrievers"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestBM25(t *testing.T) {
+	t.Parallel()
+
+	r, err := retrievers.NewBM25([]schema.Document{
+		{PageContent: "The printer shows error E-4012 when the tray is empty."},
+		{PageContent: "Replacement toner for the SKU AB-1234 printer."},
+		{PageContent: "The printer printer printer is out of paper."},
+		{PageContent: "Bananas are yellow."},
+	}, retrievers.WithNumDocuments(2))
+	require.NoError(t, err)
+
+	docs, err := r.GetRelevantDocuments(context.Background(), "ab-1234")
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "Replacement toner for the SKU AB-1234 printer.", docs[0].PageContent)
+	assert.InDelta(t, 1, docs[0].Score, 1e-6)
+
+	// Repeated terms rank higher, rare terms count more than common ones.
+	docs, err = r.GetRelevantDocuments(context.Background(), "printer error")
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	assert.Equal(t, "The printer shows error E-4012 when the tray is empty.", docs[0].PageContent)
+	assert.Equal(t, "The printer printer printer is out of paper.", docs[1].PageContent)
+	assert.Less(t, docs[1].Score, docs[0].Score)
+
+	docs, err = r.GetRelevantDocuments(context.Background(), "kiwi")
+	require.NoError(t, err)
+	assert.Empty(t, docs)
+}
+
+func TestBM25Tokenizer(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"error", "e-4012", "für", "sku", "version", "v1.2", "snake_case"},
+		retrievers.DefaultTokenizer("Error E-4012 für SKU! Version v1.2. -snake_case_-"))
+	assert.Equal(t, []string{"e-4012", "e", "4012", "printer"}, retrievers.PartsTokenizer("E-4012 printer"))
+
+	// References are kept whole, so they do not match their parts or other references.
+	docs := []schema.Document{
+		{PageContent: "Error E-4012 on AB-1234."},
+		{PageContent: "AB-5678 has a letter e."},
+	}
+	r, err := retrievers.NewBM25(docs)
+	require.NoError(t, err)
+	found, err := r.GetRelevantDocuments(context.Background(), "AB-1234")
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, docs[0].PageContent, found[0].PageContent)
+	found, err = r.GetRelevantDocuments(context.Background(), "e-4012")
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, docs[0].PageContent, found[0].PageContent)
+
+	// Parts of references match them with PartsTokenizer.
+	r, err = retrievers.NewBM25(docs, retrievers.WithTokenizer(retrievers.PartsTokenizer))
+	require.NoError(t, err)
+	found, err = r.GetRelevantDocuments(context.Background(), "1234")
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, docs[0].PageContent, found[0].PageContent)
+	found, err = r.GetRelevantDocuments(context.Background(), "AB-1234")
+	require.NoError(t, err)
+	require.Len(t, found, 2)
+	assert.Equal(t, docs[0].PageContent, found[0].PageContent)
+}
+
+func TestBM25InvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	for _, option := range []retrievers.Option{
+		retrievers.WithNumDocuments(-1),
+		retrievers.WithK1(-1),
+		retrievers.WithB(1.5),
+		retrievers.WithTokenizer(nil),
+		retrievers.WithRankConstant(1),
+		retrievers.WithNumQueries(1),
+	} {
+		_, err := retrievers.NewBM25(nil, option)
+		require.ErrorIs(t, err, retrievers.ErrInvalidOptions)
+	}
+}
@@ -0,0 +1,10 @@
+// Package retrievers contains implementations of the schema.Retriever interface that do not
+// depend on a vector store, and retrievers combining other retrievers.
+//
+// BM25 ranks documents held in memory by keyword relevance, which finds exact matches, such
+// as product references and error codes, that vector search can miss. Ensemble combines the
+// results of several retrievers, e.g. a BM25 retriever and a vector store retriever, with
+// weighted reciprocal rank fusion. MultiQuery asks an llm to rephrase the query in several
+// ways and combines the documents retrieved for each phrasing, which helps short or
+// ambiguous queries.
+package retrievers
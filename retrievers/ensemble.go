@@ -0,0 +1,150 @@
+package retrievers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/schema"
+)
+
+var (
+	// ErrNoRetrievers is returned when an Ensemble is created without retrievers.
+	ErrNoRetrievers = errors.New("no retrievers")
+	// ErrInvalidWeights is returned when the weights of an Ensemble are not one non negative
+	// weight per retriever, with a positive sum.
+	ErrInvalidWeights = errors.New("there must be one non negative weight per retriever, with a positive sum")
+)
+
+// Ensemble is a retriever combining the documents of several retrievers with weighted
+// reciprocal rank fusion: each retriever adds weight / (rank constant + rank) to the score
+// of the documents it returns, ranks starting at 1, and the documents are returned by
+// decreasing score. Documents returned by several retrievers are returned once.
+//
+// The Score of the documents returned is their fused score divided by the highest possible
+// score, obtained by a document ranked first by every retriever.
+type Ensemble struct {
+	CallbacksHandler callbacks.Handler
+
+	retrievers []schema.Retriever
+	opts       Options
+}
+
+var _ schema.Retriever = Ensemble{}
+
+// NewEnsemble returns a retriever combining the retrievers.
+func NewEnsemble(retrievers []schema.Retriever, options ...Option) (Ensemble, error) {
+	if len(retrievers) == 0 {
+		return Ensemble{}, ErrNoRetrievers
+	}
+
+	opts, err := applyOptions(_ensemble, options...)
+	if err != nil {
+		return Ensemble{}, err
+	}
+	switch {
+	case opts.NumDocuments < 0:
+		return Ensemble{}, fmt.Errorf("%w: negative number of documents", ErrInvalidOptions)
+	case opts.RankConstant < 0:
+		return Ensemble{}, fmt.Errorf("%w: negative rank constant", ErrInvalidOptions)
+	case opts.DocumentKey == nil:
+		return Ensemble{}, fmt.Errorf("%w: no document key", ErrInvalidOptions)
+	case opts.MaxConcurrency < 1:
+		return Ensemble{}, fmt.Errorf("%w: max concurrency must be at least 1", ErrInvalidOptions)
+	}
+	if opts.Weights == nil {
+		opts.Weights = make([]float64, len(retrievers))
+		for i := range opts.Weights {
+			opts.Weights[i] = 1
+		}
+	}
+	if len(opts.Weights) != len(retrievers) {
+		return Ensemble{}, ErrInvalidWeights
+	}
+	sum := 0.0
+	for _, weight := range opts.Weights {
+		if weight < 0 {
+			return Ensemble{}, ErrInvalidWeights
+		}
+		sum += weight
+	}
+	if sum == 0 {
+		return Ensemble{}, ErrInvalidWeights
+	}
+
+	return Ensemble{retrievers: retrievers, opts: opts}, nil
+}
+
+// GetRelevantDocuments queries the retrievers concurrently and returns their documents
+// ranked by reciprocal rank fusion. It fails if one of the retrievers fails, canceling the
+// queries of the others.
+func (e Ensemble) GetRelevantDocuments(ctx context.Context, query string) ([]schema.Document, error) {
+	if e.CallbacksHandler != nil {
+		e.CallbacksHandler.HandleRetrieverStart(ctx, query)
+	}
+
+	results, err := e.retrieve(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	docs := e.fuse(results)
+
+	if e.CallbacksHandler != nil {
+		e.CallbacksHandler.HandleRetrieverEnd(ctx, query, docs)
+	}
+
+	return docs, nil
+}
+
+// retrieve returns the documents of each retriever.
+func (e Ensemble) retrieve(ctx context.Context, query string) ([][]schema.Document, error) {
+	return retrieveAll(ctx, len(e.retrievers), e.opts.MaxConcurrency,
+		func(ctx context.Context, i int) ([]schema.Document, error) {
+			return e.retrievers[i].GetRelevantDocuments(ctx, query)
+		})
+}
+
+// fuse ranks the documents of the retrievers by reciprocal rank fusion.
+func (e Ensemble) fuse(results [][]schema.Document) []schema.Document {
+	rankConstant := float64(e.opts.RankConstant)
+
+	var docs []schema.Document
+	var scores []float64
+	indexes := map[string]int{}
+	maxScore := 0.0
+	for i, retrieved := range results {
+		weight := e.opts.Weights[i]
+		maxScore += weight / (rankConstant + 1)
+
+		seen := map[string]bool{}
+		for rank, doc := range retrieved {
+			key := e.opts.DocumentKey(doc)
+			// Only the best rank of a document in the results of a retriever counts.
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			index, ok := indexes[key]
+			if !ok {
+				index = len(docs)
+				indexes[key] = index
+				docs = append(docs, doc)
+				scores = append(scores, 0)
+			}
+			scores[index] += weight / (rankConstant + float64(rank+1))
+		}
+	}
+
+	for i := range docs {
+		docs[i].Score = float32(scores[i] / maxScore)
+	}
+	sort.SliceStable(docs, func(i, j int) bool { return docs[i].Score > docs[j].Score })
+
+	if e.opts.NumDocuments > 0 && len(docs) > e.opts.NumDocuments {
+		docs = docs[:e.opts.NumDocuments]
+	}
+	return docs
+}
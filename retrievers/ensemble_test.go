@@ -0,0 +1,154 @@
+package retrievers_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/retrievers"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// staticRetriever returns the same documents for every query.
+type staticRetriever struct {
+	docs []schema.Document
+	err  error
+}
+
+func (r staticRetriever) GetRelevantDocuments(context.Context, string) ([]schema.Document, error) {
+	return r.docs, r.err
+}
+
+func documents(contents ...string) []schema.Document {
+	docs := make([]schema.Document, 0, len(contents))
+	for _, content := range contents {
+		docs = append(docs, schema.Document{PageContent: content})
+	}
+	return docs
+}
+
+func pageContents(docs []schema.Document) []string {
+	contents := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		contents = append(contents, doc.PageContent)
+	}
+	return contents
+}
+
+func TestEnsemble(t *testing.T) {
+	t.Parallel()
+
+	keyword := staticRetriever{docs: documents("a", "b", "c")}
+	vector := staticRetriever{docs: documents("c", "d", "a")}
+
+	e, err := retrievers.NewEnsemble([]schema.Retriever{keyword, vector})
+	require.NoError(t, err)
+	docs, err := e.GetRelevantDocuments(context.Background(), "query")
+	require.NoError(t, err)
+	// a and c are returned by both retrievers, a ranking before c on ties.
+	assert.Equal(t, []string{"a", "c", "b", "d"}, pageContents(docs))
+	assert.InDelta(t, (1.0/61+1.0/63)/(2.0/61), docs[0].Score, 1e-6)
+
+	// Weights favor the documents of a retriever.
+	e, err = retrievers.NewEnsemble([]schema.Retriever{keyword, vector},
+		retrievers.WithWeights(0.2, 0.8), retrievers.WithNumDocuments(2))
+	require.NoError(t, err)
+	docs, err = e.GetRelevantDocuments(context.Background(), "query")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c", "a"}, pageContents(docs))
+
+	// A document ranked first by every retriever has a score of 1.
+	e, err = retrievers.NewEnsemble([]schema.Retriever{keyword, keyword}, retrievers.WithRankConstant(1))
+	require.NoError(t, err)
+	docs, err = e.GetRelevantDocuments(context.Background(), "query")
+	require.NoError(t, err)
+	assert.InDelta(t, 1, docs[0].Score, 1e-6)
+}
+
+func TestEnsembleErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := retrievers.NewEnsemble(nil)
+	require.ErrorIs(t, err, retrievers.ErrNoRetrievers)
+
+	r := staticRetriever{}
+	_, err = retrievers.NewEnsemble([]schema.Retriever{r, r}, retrievers.WithWeights(1))
+	require.ErrorIs(t, err, retrievers.ErrInvalidWeights)
+	_, err = retrievers.NewEnsemble([]schema.Retriever{r, r}, retrievers.WithWeights(1, -1))
+	require.ErrorIs(t, err, retrievers.ErrInvalidWeights)
+	_, err = retrievers.NewEnsemble([]schema.Retriever{r, r}, retrievers.WithWeights(0, 0))
+	require.ErrorIs(t, err, retrievers.ErrInvalidWeights)
+
+	for _, option := range []retrievers.Option{
+		retrievers.WithNumDocuments(-1),
+		retrievers.WithRankConstant(-1),
+		retrievers.WithDocumentKey(nil),
+		retrievers.WithMaxConcurrency(0),
+		retrievers.WithK1(1),
+		retrievers.WithIncludeOriginal(true),
+	} {
+		_, err = retrievers.NewEnsemble([]schema.Retriever{r}, option)
+		require.ErrorIs(t, err, retrievers.ErrInvalidOptions)
+	}
+
+	errRetriever := errors.New("retriever error")
+	e, err := retrievers.NewEnsemble([]schema.Retriever{r, staticRetriever{err: errRetriever}})
+	require.NoError(t, err)
+	_, err = e.GetRelevantDocuments(context.Background(), "query")
+	require.ErrorIs(t, err, errRetriever)
+}
+
+// blockingRetriever returns the error of its context once it is canceled.
+type blockingRetriever struct{}
+
+func (blockingRetriever) GetRelevantDocuments(ctx context.Context, _ string) ([]schema.Document, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// countingRetriever records the highest number of concurrent retrievals.
+type countingRetriever struct {
+	mu      *sync.Mutex
+	current *int
+	highest *int
+}
+
+func (r countingRetriever) GetRelevantDocuments(context.Context, string) ([]schema.Document, error) {
+	r.mu.Lock()
+	*r.current++
+	if *r.current > *r.highest {
+		*r.highest = *r.current
+	}
+	r.mu.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	r.mu.Lock()
+	*r.current--
+	r.mu.Unlock()
+	return documents("a"), nil
+}
+
+func TestEnsembleConcurrency(t *testing.T) {
+	t.Parallel()
+
+	// The first error cancels the other retrievals.
+	errRetriever := errors.New("retriever error")
+	e, err := retrievers.NewEnsemble([]schema.Retriever{blockingRetriever{}, staticRetriever{err: errRetriever}})
+	require.NoError(t, err)
+	_, err = e.GetRelevantDocuments(context.Background(), "query")
+	require.ErrorIs(t, err, errRetriever)
+
+	var current, highest int
+	r := countingRetriever{mu: &sync.Mutex{}, current: &current, highest: &highest}
+	e, err = retrievers.NewEnsemble([]schema.Retriever{r, r, r, r}, retrievers.WithMaxConcurrency(2))
+	require.NoError(t, err)
+	docs, err := e.GetRelevantDocuments(context.Background(), "query")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, pageContents(docs))
+	assert.LessOrEqual(t, highest, 2)
+}
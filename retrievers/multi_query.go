@@ -0,0 +1,139 @@
+package retrievers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+const _defaultMultiQueryTemplate = `You are an AI language model assistant. Your task is to generate {{.num_queries}} different versions of the given user question to retrieve relevant documents from a vector database. By generating multiple perspectives on the user question, your goal is to help the user overcome some of the limitations of distance-based similarity search. Provide these alternative questions separated by newlines, without numbering.
+Original question: {{.question}}`
+
+// ErrNoGenerations is returned when the llm returns no generation.
+var ErrNoGenerations = errors.New("llm returned no generations")
+
+// _listMarker matches the numbering or bullet llms often put before the lines of a list.
+var _listMarker = regexp.MustCompile(`^\s*(?:\d+[.)]|[-*•])\s+`) //nolint:gochecknoglobals
+
+// MultiQuery is a retriever asking an llm for alternative phrasings of the query and
+// returning the union of the documents retrieved for each of them. Each document is
+// returned once, in the order of the queries and of the documents retrieved for them.
+type MultiQuery struct {
+	CallbacksHandler callbacks.Handler
+
+	retriever schema.Retriever
+	llm       llms.LanguageModel
+	opts      Options
+}
+
+var _ schema.Retriever = MultiQuery{}
+
+// NewMultiQuery returns a retriever querying the retriever with the alternative queries
+// generated by the llm.
+func NewMultiQuery(
+	retriever schema.Retriever, llm llms.LanguageModel, options ...Option,
+) (MultiQuery, error) {
+	opts, err := applyOptions(_multiQuery, options...)
+	if err != nil {
+		return MultiQuery{}, err
+	}
+	switch {
+	case opts.NumQueries < 1:
+		return MultiQuery{}, fmt.Errorf("%w: the number of queries must be at least 1", ErrInvalidOptions)
+	case opts.Prompt == nil:
+		return MultiQuery{}, fmt.Errorf("%w: no prompt", ErrInvalidOptions)
+	case opts.DocumentKey == nil:
+		return MultiQuery{}, fmt.Errorf("%w: no document key", ErrInvalidOptions)
+	case opts.MaxConcurrency < 1:
+		return MultiQuery{}, fmt.Errorf("%w: max concurrency must be at least 1", ErrInvalidOptions)
+	}
+
+	return MultiQuery{
+		retriever: retriever,
+		llm:       llm,
+		opts:      opts,
+	}, nil
+}
+
+// GetRelevantDocuments generates the alternative queries, queries the retriever with them
+// concurrently and returns the documents retrieved. If the llm returns no query, the
+// original query is used. It fails if one of the queries fails, canceling the others.
+func (m MultiQuery) GetRelevantDocuments(ctx context.Context, query string) ([]schema.Document, error) {
+	if m.CallbacksHandler != nil {
+		m.CallbacksHandler.HandleRetrieverStart(ctx, query)
+	}
+
+	queries, err := m.GenerateQueries(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if m.opts.IncludeOriginal || len(queries) == 0 {
+		queries = append([]string{query}, queries...)
+	}
+
+	results, err := m.retrieve(ctx, queries)
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []schema.Document
+	seen := map[string]bool{}
+	for _, retrieved := range results {
+		for _, doc := range retrieved {
+			key := m.opts.DocumentKey(doc)
+			if !seen[key] {
+				seen[key] = true
+				docs = append(docs, doc)
+			}
+		}
+	}
+
+	if m.CallbacksHandler != nil {
+		m.CallbacksHandler.HandleRetrieverEnd(ctx, query, docs)
+	}
+
+	return docs, nil
+}
+
+// GenerateQueries asks the llm for alternative phrasings of the query, and returns at most
+// the number of queries set with WithNumQueries.
+func (m MultiQuery) GenerateQueries(ctx context.Context, query string) ([]string, error) {
+	promptValue, err := m.opts.Prompt.FormatPrompt(map[string]any{
+		"question":    query,
+		"num_queries": m.opts.NumQueries,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := m.llm.GeneratePrompt(ctx, []schema.PromptValue{promptValue})
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Generations) == 0 || len(result.Generations[0]) == 0 {
+		return nil, ErrNoGenerations
+	}
+
+	var queries []string
+	for _, line := range strings.Split(result.Generations[0][0].Text, "\n") {
+		line = strings.TrimSpace(_listMarker.ReplaceAllString(line, ""))
+		if line != "" && len(queries) < m.opts.NumQueries {
+			queries = append(queries, line)
+		}
+	}
+	return queries, nil
+}
+
+// retrieve returns the documents retrieved for each query.
+func (m MultiQuery) retrieve(ctx context.Context, queries []string) ([][]schema.Document, error) {
+	return retrieveAll(ctx, len(queries), m.opts.MaxConcurrency,
+		func(ctx context.Context, i int) ([]schema.Document, error) {
+			return m.retriever.GetRelevantDocuments(ctx, queries[i])
+		})
+}
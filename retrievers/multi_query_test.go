@@ -0,0 +1,107 @@
+package retrievers_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/retrievers"
+	"github.com/tmc/langchaingo/schema"
+)
+
+type testLanguageModel struct {
+	completion string
+	prompts    []string
+}
+
+func (l *testLanguageModel) GeneratePrompt(_ context.Context, promptValues []schema.PromptValue, _ ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	l.prompts = append(l.prompts, promptValues[0].String())
+	return llms.LLMResult{
+		Generations: [][]*llms.Generation{{&llms.Generation{Text: l.completion}}},
+	}, nil
+}
+
+func (l *testLanguageModel) GetNumTokens(text string) int {
+	return len(text)
+}
+
+// queryRetriever returns the documents stored for each query, and records the queries.
+type queryRetriever struct {
+	mu      sync.Mutex
+	docs    map[string][]schema.Document
+	queries []string
+}
+
+func (r *queryRetriever) GetRelevantDocuments(_ context.Context, query string) ([]schema.Document, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queries = append(r.queries, query)
+	return r.docs[query], nil
+}
+
+func TestMultiQuery(t *testing.T) {
+	t.Parallel()
+
+	llm := &testLanguageModel{completion: "1. How do I reset my password?\n" +
+		"2. Steps to recover a forgotten password\n\n" +
+		"- Change account password\n" +
+		"Password reset procedure"}
+	r := &queryRetriever{docs: map[string][]schema.Document{
+		"How do I reset my password?":           documents("reset", "login"),
+		"Steps to recover a forgotten password": documents("recover", "reset"),
+		"Change account password":               documents("settings"),
+		"pw reset":                              documents("original"),
+	}}
+
+	m, err := retrievers.NewMultiQuery(r, llm)
+	require.NoError(t, err)
+	docs, err := m.GetRelevantDocuments(context.Background(), "pw reset")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"reset", "login", "recover", "settings"}, pageContents(docs))
+	assert.ElementsMatch(t, []string{
+		"How do I reset my password?", "Steps to recover a forgotten password", "Change account password",
+	}, r.queries)
+	require.Len(t, llm.prompts, 1)
+	assert.True(t, strings.Contains(llm.prompts[0], "generate 3 different versions"))
+	assert.True(t, strings.HasSuffix(llm.prompts[0], "Original question: pw reset"))
+
+	m, err = retrievers.NewMultiQuery(r, llm, retrievers.WithNumQueries(1), retrievers.WithIncludeOriginal(true))
+	require.NoError(t, err)
+	docs, err = m.GetRelevantDocuments(context.Background(), "pw reset")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"original", "reset", "login"}, pageContents(docs))
+}
+
+func TestMultiQueryWithoutGeneratedQueries(t *testing.T) {
+	t.Parallel()
+
+	r := &queryRetriever{docs: map[string][]schema.Document{"pw reset": documents("original")}}
+	m, err := retrievers.NewMultiQuery(r, &testLanguageModel{completion: "\n  \n"})
+	require.NoError(t, err)
+
+	docs, err := m.GetRelevantDocuments(context.Background(), "pw reset")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"original"}, pageContents(docs))
+}
+
+func TestMultiQueryInvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	llm := &testLanguageModel{}
+	for _, option := range []retrievers.Option{
+		retrievers.WithNumQueries(0),
+		retrievers.WithPrompt(nil),
+		retrievers.WithDocumentKey(nil),
+		retrievers.WithMaxConcurrency(0),
+		retrievers.WithWeights(1),
+		retrievers.WithNumDocuments(2),
+	} {
+		_, err := retrievers.NewMultiQuery(&queryRetriever{}, llm, option)
+		require.ErrorIs(t, err, retrievers.ErrInvalidOptions)
+	}
+	assert.Empty(t, llm.prompts)
+}
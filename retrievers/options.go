@@ -0,0 +1,236 @@
+package retrievers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+const (
+	_defaultNumDocuments = 4
+	_defaultK1           = 1.5
+	_defaultB            = 0.75
+	_defaultRankConstant = 60
+	_defaultNumQueries   = 3
+
+	_defaultMaxConcurrency = 8
+)
+
+// ErrInvalidOptions is returned when an option is invalid or does not apply to the
+// retriever being created.
+var ErrInvalidOptions = errors.New("invalid options")
+
+// Options is a set of options for the retrievers of the package. The constructor of a
+// retriever returns ErrInvalidOptions if given an option that does not apply to it.
+type Options struct {
+	NumDocuments int
+	K1           float64
+	B            float64
+	Tokenizer    func(text string) []string
+	Weights      []float64
+	RankConstant int
+	DocumentKey  func(doc schema.Document) string
+
+	MaxConcurrency int
+
+	NumQueries      int
+	Prompt          prompts.FormatPrompter
+	IncludeOriginal bool
+
+	kind retrieverKind
+	err  error
+}
+
+// retrieverKind is a set of retrievers of the package, to which an option applies.
+type retrieverKind int
+
+const (
+	_bm25 retrieverKind = 1 << iota
+	_ensemble
+	_multiQuery
+)
+
+func (k retrieverKind) String() string {
+	switch k {
+	case _bm25:
+		return "BM25"
+	case _ensemble:
+		return "Ensemble"
+	case _multiQuery:
+		return "MultiQuery"
+	default:
+		return "retriever"
+	}
+}
+
+// appliesTo records an error if the option does not apply to the retriever the options are
+// applied for.
+func (o *Options) appliesTo(option string, kinds retrieverKind) {
+	if o.kind&kinds == 0 && o.err == nil {
+		o.err = fmt.Errorf("%w: %s does not apply to %s", ErrInvalidOptions, option, o.kind)
+	}
+}
+
+// Option is a function that configures an Options.
+type Option func(*Options)
+
+// WithNumDocuments sets the maximum number of documents returned. It defaults to 4 for BM25
+// and to all the documents found for Ensemble.
+func WithNumDocuments(numDocuments int) Option {
+	return func(o *Options) {
+		o.appliesTo("WithNumDocuments", _bm25|_ensemble)
+		o.NumDocuments = numDocuments
+	}
+}
+
+// WithK1 sets the term frequency saturation of BM25: the higher, the more repeated terms
+// count. It must not be negative and defaults to 1.5.
+func WithK1(k1 float64) Option {
+	return func(o *Options) {
+		o.appliesTo("WithK1", _bm25)
+		o.K1 = k1
+	}
+}
+
+// WithB sets the document length normalization of BM25, between 0, for none, and 1, for
+// full normalization. It defaults to 0.75.
+func WithB(b float64) Option {
+	return func(o *Options) {
+		o.appliesTo("WithB", _bm25)
+		o.B = b
+	}
+}
+
+// WithTokenizer sets the function splitting texts into terms for BM25. It defaults to
+// DefaultTokenizer.
+func WithTokenizer(tokenizer func(text string) []string) Option {
+	return func(o *Options) {
+		o.appliesTo("WithTokenizer", _bm25)
+		o.Tokenizer = tokenizer
+	}
+}
+
+// WithWeights sets the weights of the retrievers of an Ensemble, in the order of the
+// retrievers. They default to equal weights.
+func WithWeights(weights ...float64) Option {
+	return func(o *Options) {
+		o.appliesTo("WithWeights", _ensemble)
+		o.Weights = weights
+	}
+}
+
+// WithRankConstant sets the constant added to the ranks in reciprocal rank fusion: the
+// higher, the less the top ranks dominate. It must not be negative and defaults to 60.
+func WithRankConstant(rankConstant int) Option {
+	return func(o *Options) {
+		o.appliesTo("WithRankConstant", _ensemble)
+		o.RankConstant = rankConstant
+	}
+}
+
+// WithDocumentKey sets the function identifying the documents returned by several
+// retrievers of an Ensemble, or for several queries of a MultiQuery. It defaults to the
+// page content of the documents.
+func WithDocumentKey(documentKey func(doc schema.Document) string) Option {
+	return func(o *Options) {
+		o.appliesTo("WithDocumentKey", _ensemble|_multiQuery)
+		o.DocumentKey = documentKey
+	}
+}
+
+// WithMaxConcurrency sets the maximum number of retrievals run at the same time by an
+// Ensemble, one per retriever, or by a MultiQuery, one per query. It defaults to 8.
+func WithMaxConcurrency(maxConcurrency int) Option {
+	return func(o *Options) {
+		o.appliesTo("WithMaxConcurrency", _ensemble|_multiQuery)
+		o.MaxConcurrency = maxConcurrency
+	}
+}
+
+// WithNumQueries sets the number of alternative queries a MultiQuery asks the llm for. It
+// must be at least 1 and defaults to 3.
+func WithNumQueries(numQueries int) Option {
+	return func(o *Options) {
+		o.appliesTo("WithNumQueries", _multiQuery)
+		o.NumQueries = numQueries
+	}
+}
+
+// WithPrompt sets the prompt a MultiQuery uses to ask the llm for alternative queries, one
+// per line. Its input variables are "question" and "num_queries".
+func WithPrompt(prompt prompts.FormatPrompter) Option {
+	return func(o *Options) {
+		o.appliesTo("WithPrompt", _multiQuery)
+		o.Prompt = prompt
+	}
+}
+
+// WithIncludeOriginal sets whether a MultiQuery also retrieves the documents of the
+// original query. It defaults to false.
+func WithIncludeOriginal(includeOriginal bool) Option {
+	return func(o *Options) {
+		o.appliesTo("WithIncludeOriginal", _multiQuery)
+		o.IncludeOriginal = includeOriginal
+	}
+}
+
+// _termJoiners are the characters kept inside a term when they join letters or digits, as
+// in references such as AB-1234, snake_case names or version numbers.
+const _termJoiners = "-_."
+
+// DefaultTokenizer splits a text into lowercase terms made of letters and digits, possibly
+// joined by hyphens, underscores or dots: "Error E-4012!" gives "error" and "e-4012".
+func DefaultTokenizer(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune(_termJoiners, r)
+	})
+
+	terms := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if term := strings.Trim(field, _termJoiners); term != "" {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// PartsTokenizer splits a text like DefaultTokenizer, and also returns the parts of the
+// joined terms, so a query for a part matches the whole term: "E-4012" gives "e-4012", "e"
+// and "4012".
+func PartsTokenizer(text string) []string {
+	var terms []string
+	for _, term := range DefaultTokenizer(text) {
+		terms = append(terms, term)
+		parts := strings.FieldsFunc(term, func(r rune) bool { return strings.ContainsRune(_termJoiners, r) })
+		if len(parts) > 1 {
+			terms = append(terms, parts...)
+		}
+	}
+	return terms
+}
+
+// applyOptions applies the options for a retriever, failing if one does not apply to it.
+func applyOptions(kind retrieverKind, options ...Option) (Options, error) {
+	opts := Options{
+		K1:           _defaultK1,
+		B:            _defaultB,
+		Tokenizer:    DefaultTokenizer,
+		RankConstant: _defaultRankConstant,
+		DocumentKey:  func(doc schema.Document) string { return doc.PageContent },
+
+		MaxConcurrency: _defaultMaxConcurrency,
+
+		NumQueries: _defaultNumQueries,
+		Prompt:     prompts.NewPromptTemplate(_defaultMultiQueryTemplate, []string{"question", "num_queries"}),
+
+		kind: kind,
+	}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	return opts, opts.err
+}
@@ -0,0 +1,35 @@
+package retrievers
+
+import (
+	"context"
+
+	"github.com/tmc/langchaingo/schema"
+	"golang.org/x/sync/errgroup"
+)
+
+// retrieveAll calls retrieve for each index from 0 to n-1, with at most maxConcurrency
+// calls at a time, and returns the documents of each call. The first error cancels the
+// context of the other calls and is returned.
+func retrieveAll(
+	ctx context.Context,
+	n int,
+	maxConcurrency int,
+	retrieve func(ctx context.Context, i int) ([]schema.Document, error),
+) ([][]schema.Document, error) {
+	results := make([][]schema.Document, n)
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(maxConcurrency)
+	for i := 0; i < n; i++ {
+		i := i
+		g.Go(func() error {
+			docs, err := retrieve(ctx, i)
+			results[i] = docs
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
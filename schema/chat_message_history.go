@@ -21,4 +21,9 @@ type ChatMessageHistory interface {
 
 	// SetMessages replaces existing messages in the store
 	SetMessages(ctx context.Context, messages []ChatMessage) error
+
+	// TrimMessages atomically removes the oldest messages from the store. The trim
+	// function is given all messages and returns the most recent ones to keep, which
+	// must be a suffix of the messages.
+	TrimMessages(ctx context.Context, trim func(messages []ChatMessage) []ChatMessage) error
 }
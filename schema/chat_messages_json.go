@@ -0,0 +1,92 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ChatMessageModel is the serializable representation of a ChatMessage. The type
+// field records the concrete message type, so a ChatMessageModel can be converted
+// back to the same ChatMessage it was created from.
+type ChatMessageModel struct {
+	Type         ChatMessageType `json:"type"`
+	Content      string          `json:"content"`
+	Role         string          `json:"role,omitempty"`
+	Name         string          `json:"name,omitempty"`
+	FunctionCall *FunctionCall   `json:"function_call,omitempty"`
+}
+
+// ConvertChatMessageToModel converts a chat message to its serializable representation.
+// Only the message types of this package are supported.
+func ConvertChatMessageToModel(message ChatMessage) (ChatMessageModel, error) {
+	model := ChatMessageModel{
+		Type:    message.GetType(),
+		Content: message.GetContent(),
+	}
+
+	switch m := message.(type) {
+	case AIChatMessage:
+		model.FunctionCall = m.FunctionCall
+	case HumanChatMessage, SystemChatMessage:
+	case GenericChatMessage:
+		model.Role = m.Role
+		model.Name = m.Name
+	case FunctionChatMessage:
+		model.Name = m.Name
+	default:
+		return ChatMessageModel{}, fmt.Errorf("%w: %T", ErrUnexpectedChatMessageType, message)
+	}
+
+	return model, nil
+}
+
+// ToChatMessage converts the model back to a chat message of the recorded type.
+func (m ChatMessageModel) ToChatMessage() (ChatMessage, error) { //nolint:ireturn
+	switch m.Type {
+	case ChatMessageTypeAI:
+		return AIChatMessage{Content: m.Content, FunctionCall: m.FunctionCall}, nil
+	case ChatMessageTypeHuman:
+		return HumanChatMessage{Content: m.Content}, nil
+	case ChatMessageTypeSystem:
+		return SystemChatMessage{Content: m.Content}, nil
+	case ChatMessageTypeGeneric:
+		return GenericChatMessage{Content: m.Content, Role: m.Role, Name: m.Name}, nil
+	case ChatMessageTypeFunction:
+		return FunctionChatMessage{Content: m.Content, Name: m.Name}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnexpectedChatMessageType, m.Type)
+	}
+}
+
+// MarshalChatMessages encodes chat messages as a JSON array of type-tagged objects.
+func MarshalChatMessages(messages []ChatMessage) ([]byte, error) {
+	models := make([]ChatMessageModel, 0, len(messages))
+	for _, message := range messages {
+		model, err := ConvertChatMessageToModel(message)
+		if err != nil {
+			return nil, err
+		}
+		models = append(models, model)
+	}
+
+	return json.Marshal(models)
+}
+
+// UnmarshalChatMessages decodes chat messages encoded with MarshalChatMessages.
+func UnmarshalChatMessages(data []byte) ([]ChatMessage, error) {
+	var models []ChatMessageModel
+	if err := json.Unmarshal(data, &models); err != nil {
+		return nil, err
+	}
+
+	messages := make([]ChatMessage, 0, len(models))
+	for _, model := range models {
+		message, err := model.ToChatMessage()
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+
+	return messages, nil
+}
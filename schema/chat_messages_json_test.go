@@ -0,0 +1,52 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestMarshalChatMessages(t *testing.T) {
+	t.Parallel()
+
+	messages := []schema.ChatMessage{
+		schema.SystemChatMessage{Content: "be nice"},
+		schema.HumanChatMessage{Content: "what is the weather?"},
+		schema.AIChatMessage{
+			FunctionCall: &schema.FunctionCall{Name: "weather", Arguments: `{"location":"Boston"}`},
+		},
+		schema.FunctionChatMessage{Name: "weather", Content: "sunny"},
+		schema.GenericChatMessage{Role: "moderator", Name: "mod", Content: "on topic"},
+		schema.AIChatMessage{Content: "It is sunny."},
+	}
+
+	data, err := schema.MarshalChatMessages(messages)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"type": "system", "content": "be nice"},
+		{"type": "human", "content": "what is the weather?"},
+		{"type": "ai", "content": "", "function_call": {"name": "weather", "arguments": "{\"location\":\"Boston\"}"}},
+		{"type": "function", "content": "sunny", "name": "weather"},
+		{"type": "generic", "content": "on topic", "role": "moderator", "name": "mod"},
+		{"type": "ai", "content": "It is sunny."}
+	]`, string(data))
+
+	got, err := schema.UnmarshalChatMessages(data)
+	require.NoError(t, err)
+	assert.Equal(t, messages, got)
+}
+
+func TestMarshalChatMessagesErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := schema.MarshalChatMessages([]schema.ChatMessage{unsupportedChatMessage{}})
+	assert.ErrorIs(t, err, schema.ErrUnexpectedChatMessageType)
+
+	_, err = schema.UnmarshalChatMessages([]byte(`[{"type": "unknown", "content": "?"}]`))
+	assert.ErrorIs(t, err, schema.ErrUnexpectedChatMessageType)
+
+	_, err = schema.UnmarshalChatMessages([]byte(`{`))
+	assert.Error(t, err)
+}
@@ -4,5 +4,7 @@ package schema
 type Document struct {
 	PageContent string
 	Metadata    map[string]any
-	Score       float32
+	// Score is the relevance of the document to the query of the search returning it, between
+	// 0 and 1, higher being more relevant. Vector stores set it in SimilaritySearch.
+	Score float32
 }
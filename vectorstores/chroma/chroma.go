@@ -7,10 +7,12 @@ import (
 
 	chromago "github.com/amikos-tech/chroma-go"
 	"github.com/amikos-tech/chroma-go/openai"
+	chromaopenapi "github.com/amikos-tech/chroma-go/swagger"
 	"github.com/google/uuid"
 	"github.com/tmc/langchaingo/embeddings"
 	"github.com/tmc/langchaingo/schema"
 	"github.com/tmc/langchaingo/vectorstores"
+	"github.com/tmc/langchaingo/vectorstores/filter"
 	"golang.org/x/exp/maps"
 )
 
@@ -21,6 +23,10 @@ var (
 	ErrAddDocument              = errors.New("error adding document")
 	ErrRemoveCollection         = errors.New("error resetting collection")
 	ErrUnsupportedOptions       = errors.New("unsupported options")
+	ErrDeleteDocuments          = errors.New("error deleting documents")
+	// ErrInvalidFilter is returned when the filters are neither a filter.Filter nor a Chroma
+	// where clause.
+	ErrInvalidFilter = errors.New("invalid filter")
 )
 
 // Store is a wrapper around the chromaGo API and client.
@@ -37,7 +43,13 @@ type Store struct {
 	includes         []chromago.QueryEnum
 }
 
-var _ vectorstores.VectorStore = Store{}
+var (
+	_ vectorstores.VectorStore   = Store{}
+	_ vectorstores.Upserter      = Store{}
+	_ vectorstores.IDDeleter     = Store{}
+	_ vectorstores.FilterDeleter = Store{}
+	_ vectorstores.Getter        = Store{}
+)
 
 // New creates an active client connection to the (specified, or default) collection in the Chroma server
 // and returns the `Store` object needed by the other accessors.
@@ -74,9 +86,33 @@ func New(opts ...Option) (Store, error) {
 }
 
 // AddDocuments adds the text and metadata from the documents to the Chroma collection associated with 'Store'.
-func (s Store) AddDocuments(_ context.Context, docs []schema.Document, options ...vectorstores.Option) error {
+// The documents are stored under random ids, use UpsertDocuments to choose them.
+func (s Store) AddDocuments(ctx context.Context, docs []schema.Document, options ...vectorstores.Option) error {
+	ids := make([]string, len(docs))
+	for docIdx := range docs {
+		ids[docIdx] = uuid.New().String()
+	}
+	return s.addDocuments(ctx, ids, docs, false, options...)
+}
+
+// UpsertDocuments adds the documents to the Chroma collection under the given ids, replacing the
+// documents already stored under them. Since ids are shared by all name spaces, the ids of a name
+// space are stored as UUIDs derived from them and the name space, see documentID.
+func (s Store) UpsertDocuments(ctx context.Context, ids []string, docs []schema.Document,
+	options ...vectorstores.Option,
+) error {
+	if err := vectorstores.CheckIDs(ids, docs); err != nil {
+		return err
+	}
+	return s.addDocuments(ctx, ids, docs, true, options...)
+}
+
+func (s Store) addDocuments(_ context.Context, ids []string, docs []schema.Document, upsert bool,
+	options ...vectorstores.Option,
+) error {
 	opts := s.getOptions(options...)
-	if opts.Embedder != nil || opts.ScoreThreshold != 0 || opts.Filters != nil {
+	// The score threshold only applies to searches and is ignored, as by the other vector stores.
+	if opts.Embedder != nil || opts.Filters != nil {
 		return ErrUnsupportedOptions
 	}
 
@@ -85,11 +121,14 @@ func (s Store) AddDocuments(_ context.Context, docs []schema.Document, options .
 		return fmt.Errorf("%w: nameSpace without nameSpaceKey", ErrUnsupportedOptions)
 	}
 
-	ids := make([]string, len(docs))
+	documentIDs := make([]string, len(ids))
+	for i, id := range ids {
+		documentIDs[i] = documentID(nameSpace, id)
+	}
+
 	texts := make([]string, len(docs))
 	metadatas := make([]map[string]any, len(docs))
 	for docIdx, doc := range docs {
-		ids[docIdx] = uuid.New().String() // TODO (noodnik2): find & use something more meaningful
 		texts[docIdx] = doc.PageContent
 		mc := make(map[string]any, 0)
 		maps.Copy(mc, doc.Metadata)
@@ -100,13 +139,130 @@ func (s Store) AddDocuments(_ context.Context, docs []schema.Document, options .
 	}
 
 	col := s.collection
-	if _, addErr := col.Add(nil, metadatas, texts, ids); addErr != nil {
+	add := col.Add
+	if upsert {
+		add = col.Upsert
+	}
+	if _, addErr := add(nil, metadatas, texts, documentIDs); addErr != nil {
 		return fmt.Errorf("%w: %w", ErrAddDocument, addErr)
 	}
 	return nil
 }
 
-func (s Store) SimilaritySearch(_ context.Context, query string, numDocuments int,
+// DeleteDocumentsByID deletes the documents with the given ids from the name space.
+func (s Store) DeleteDocumentsByID(ctx context.Context, ids []string, options ...vectorstores.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	opts := s.getOptions(options...)
+	where, err := s.getNamespacedFilter(opts)
+	if err != nil {
+		return err
+	}
+
+	nameSpace := s.getNameSpace(opts)
+	documentIDs := make([]string, len(ids))
+	for i, id := range ids {
+		documentIDs[i] = documentID(nameSpace, id)
+	}
+	return s.deleteDocuments(ctx, documentIDs, where)
+}
+
+// DeleteDocuments deletes the documents of the name space matching the filters. All documents
+// of the name space are deleted if no filters are given and vectorstores.WithDeleteAll is set.
+func (s Store) DeleteDocuments(ctx context.Context, options ...vectorstores.Option) error {
+	opts := s.getOptions(options...)
+	if err := vectorstores.CheckDeleteFilters(opts); err != nil {
+		return err
+	}
+
+	where, err := s.getNamespacedFilter(opts)
+	if err != nil {
+		return err
+	}
+	if len(where) == 0 {
+		// Without a name space, all documents of the collection are deleted.
+		if !opts.DeleteAll {
+			return vectorstores.ErrDeleteWithoutFilters
+		}
+		where = map[string]any{}
+	}
+	return s.deleteDocuments(ctx, nil, where)
+}
+
+func (s Store) deleteDocuments(ctx context.Context, ids []string, where map[string]any) error {
+	collectionID, err := s.getCollectionID(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Collection.Delete of chromago exits the program on errors, so the API is used directly.
+	_, _, err = s.client.ApiClient.DefaultApi.Delete(ctx, collectionID).
+		DeleteEmbedding(chromaopenapi.DeleteEmbedding{Ids: ids, Where: where}).
+		Execute()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrDeleteDocuments, err)
+	}
+	return nil
+}
+
+// GetDocumentsByID returns the documents of the name space with the given ids.
+func (s Store) GetDocumentsByID(ctx context.Context, ids []string,
+	options ...vectorstores.Option,
+) (map[string]schema.Document, error) {
+	docs := make(map[string]schema.Document, len(ids))
+	if len(ids) == 0 {
+		return docs, nil
+	}
+
+	opts := s.getOptions(options...)
+	where, err := s.getNamespacedFilter(opts)
+	if err != nil {
+		return nil, err
+	}
+	collectionID, err := s.getCollectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	nameSpace := s.getNameSpace(opts)
+	idsByDocumentID := make(map[string]string, len(ids))
+	documentIDs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		idsByDocumentID[documentID(nameSpace, id)] = id
+		documentIDs = append(documentIDs, documentID(nameSpace, id))
+	}
+
+	includeDocuments, includeMetadatas := "documents", "metadatas"
+	include := []chromaopenapi.IncludeInner{{String: &includeDocuments}, {String: &includeMetadatas}}
+	result, _, err := s.client.ApiClient.DefaultApi.Get(ctx, collectionID).
+		GetEmbedding(chromaopenapi.GetEmbedding{
+			Ids:     documentIDs,
+			Where:   where,
+			Include: include,
+		}).
+		Execute()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.Documents) != len(result.Ids) || len(result.Metadatas) != len(result.Ids) {
+		return nil, fmt.Errorf("%w: result.Ids[%d], result.Documents[%d], result.Metadatas[%d]",
+			ErrUnexpectedResponseLength, len(result.Ids), len(result.Documents), len(result.Metadatas))
+	}
+	for i, id := range result.Ids {
+		metadata := make(map[string]any, len(result.Metadatas[i]))
+		for key, value := range result.Metadatas[i] {
+			metadata[key] = metadataValue(value)
+		}
+		docs[idsByDocumentID[id]] = schema.Document{PageContent: result.Documents[i], Metadata: metadata}
+	}
+	return docs, nil
+}
+
+// SimilaritySearch returns the documents of the collection most similar to the query, with
+// their relevance score.
+func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments int,
 	options ...vectorstores.Option,
 ) ([]schema.Document, error) {
 	opts := s.getOptions(options...)
@@ -121,8 +277,15 @@ func (s Store) SimilaritySearch(_ context.Context, query string, numDocuments in
 		return nil, stErr
 	}
 
-	filter := s.getNamespacedFilter(opts)
-	qr, queryErr := s.collection.Query([]string{query}, int32(numDocuments), filter, nil, s.includes)
+	where, err := s.getNamespacedFilter(opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.MMR != nil {
+		return s.mmrSearch(ctx, query, numDocuments, where, scoreThreshold, *opts.MMR)
+	}
+
+	qr, queryErr := s.collection.Query([]string{query}, int32(numDocuments), where, nil, s.includes)
 	if queryErr != nil {
 		return nil, queryErr
 	}
@@ -134,10 +297,12 @@ func (s Store) SimilaritySearch(_ context.Context, query string, numDocuments in
 	var sDocs []schema.Document
 	for docsI := range qr.Documents {
 		for docI := range qr.Documents[docsI] {
-			if (1.0 - qr.Distances[docsI][docI]) >= scoreThreshold {
+			score := s.relevance(qr.Distances[docsI][docI])
+			if score >= scoreThreshold {
 				sDocs = append(sDocs, schema.Document{
 					Metadata:    qr.Metadatas[docsI][docI],
 					PageContent: qr.Documents[docsI][docI],
+					Score:       score,
 				})
 			}
 		}
@@ -157,6 +322,114 @@ func (s Store) RemoveCollection() error {
 	return nil
 }
 
+// mmrSearch fetches the candidates of a maximal marginal relevance search together with
+// their embeddings and selects numDocuments of them.
+func (s Store) mmrSearch(ctx context.Context, query string, numDocuments int, where map[string]any,
+	scoreThreshold float32, mmr vectorstores.MMROptions,
+) ([]schema.Document, error) {
+	if err := mmr.Validate(); err != nil {
+		return nil, err
+	}
+
+	collectionID, err := s.getCollectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	queryEmbeddings, err := s.collection.EmbeddingFunction.CreateEmbedding([]string{query})
+	if err != nil {
+		return nil, err
+	}
+
+	fetchK := int32(mmr.GetFetchK(numDocuments))
+	includeDocuments, includeMetadatas := "documents", "metadatas"
+	includeDistances, includeEmbeddings := "distances", "embeddings"
+	result, _, err := s.client.ApiClient.DefaultApi.GetNearestNeighbors(ctx, collectionID).
+		QueryEmbedding(chromaopenapi.QueryEmbedding{
+			Where:           where,
+			QueryEmbeddings: chromago.ConvertEmbeds(queryEmbeddings),
+			NResults:        &fetchK,
+			Include: []chromaopenapi.IncludeInner{
+				{String: &includeDocuments}, {String: &includeMetadatas},
+				{String: &includeDistances}, {String: &includeEmbeddings},
+			},
+		}).
+		Execute()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.Documents) != 1 || len(result.Metadatas) != 1 ||
+		len(result.Distances) != 1 || len(result.Embeddings) != 1 {
+		return nil, fmt.Errorf("%w: result.Documents[%d], result.Metadatas[%d], result.Distances[%d], "+
+			"result.Embeddings[%d]", ErrUnexpectedResponseLength, len(result.Documents), len(result.Metadatas),
+			len(result.Distances), len(result.Embeddings))
+	}
+
+	var docs []schema.Document
+	var embeddings [][]float32
+	for i, pageContent := range result.Documents[0] {
+		if i >= len(result.Metadatas[0]) || i >= len(result.Distances[0]) || i >= len(result.Embeddings[0]) {
+			return nil, ErrUnexpectedResponseLength
+		}
+		score := s.relevance(result.Distances[0][i])
+		if score < scoreThreshold {
+			continue
+		}
+
+		metadata := make(map[string]any, len(result.Metadatas[0][i]))
+		for key, value := range result.Metadatas[0][i] {
+			metadata[key] = metadataValue(value)
+		}
+		docs = append(docs, schema.Document{PageContent: pageContent, Metadata: metadata, Score: score})
+
+		var embedding []float32
+		if result.Embeddings[0][i].ArrayOfFloat32 != nil {
+			embedding = *result.Embeddings[0][i].ArrayOfFloat32
+		}
+		embeddings = append(embeddings, embedding)
+	}
+
+	return vectorstores.MaximalMarginalRelevanceDocuments(queryEmbeddings[0], docs, embeddings, mmr.Lambda,
+		numDocuments), nil
+}
+
+// relevance returns the relevance score of a distance computed with the distance function
+// of the collection. Chroma's "l2" distance is the squared Euclidean distance and its "ip"
+// distance is 1 minus the inner product.
+func (s Store) relevance(distance float32) float32 {
+	if s.distanceFunction == chromago.L2 {
+		return vectorstores.RelevanceFromSquaredL2Distance(distance)
+	}
+	return vectorstores.RelevanceFromCosineDistance(distance)
+}
+
+func (s Store) getCollectionID(ctx context.Context) (string, error) {
+	if s.client == nil || s.collection == nil {
+		return "", fmt.Errorf("%w: no collection", ErrUnsupportedOptions)
+	}
+	col, _, err := s.client.ApiClient.DefaultApi.GetCollection(ctx, s.collection.Name).Execute()
+	if err != nil {
+		return "", err
+	}
+	return col.Id, nil
+}
+
+// metadataValue returns the value of a metadata entry returned by the Chroma API.
+func metadataValue(value chromaopenapi.MetadatasInnerValue) any {
+	switch {
+	case value.String != nil:
+		return *value.String
+	case value.Int32 != nil:
+		return *value.Int32
+	case value.Float32 != nil:
+		return *value.Float32
+	case value.Bool != nil:
+		return *value.Bool
+	default:
+		return nil
+	}
+}
+
 func (s Store) getOptions(options ...vectorstores.Option) vectorstores.Options {
 	opts := vectorstores.Options{}
 	for _, opt := range options {
@@ -172,6 +445,16 @@ func (s Store) getScoreThreshold(opts vectorstores.Options) (float32, error) {
 	return opts.ScoreThreshold, nil
 }
 
+// documentID returns the id of a name space as a Chroma document id. Outside of name spaces, ids
+// are kept. In a name space, ids are mapped to a UUID derived from the name space and the id, so
+// that the same id can be used in several name spaces.
+func documentID(nameSpace, id string) string {
+	if nameSpace == "" {
+		return id
+	}
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(nameSpace+"\x00"+id)).String()
+}
+
 func (s Store) getNameSpace(opts vectorstores.Options) string {
 	if opts.NameSpace != "" {
 		return opts.NameSpace
@@ -179,18 +462,32 @@ func (s Store) getNameSpace(opts vectorstores.Options) string {
 	return s.nameSpace
 }
 
-func (s Store) getNamespacedFilter(opts vectorstores.Options) map[string]any {
-	filter, _ := opts.Filters.(map[string]any)
+// getNamespacedFilter returns the where clause of the filters, a filter.Filter or a Chroma
+// where clause, restricted to the name space.
+func (s Store) getNamespacedFilter(opts vectorstores.Options) (map[string]any, error) {
+	var where map[string]any
+	switch filters := opts.Filters.(type) {
+	case nil:
+	case map[string]any:
+		where = filters
+	case filter.Filter:
+		var err error
+		if where, err = filter.ToMongo(filters); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrInvalidFilter, filters)
+	}
 
 	nameSpace := s.getNameSpace(opts)
 	if nameSpace == "" || s.nameSpaceKey == "" {
-		return filter
+		return where, nil
 	}
 
 	nameSpaceFilter := map[string]any{s.nameSpaceKey: nameSpace}
-	if filter == nil {
-		return nameSpaceFilter
+	if where == nil {
+		return nameSpaceFilter, nil
 	}
 
-	return map[string]any{"$and": []map[string]any{nameSpaceFilter, filter}}
+	return map[string]any{"$and": []map[string]any{nameSpaceFilter, where}}, nil
 }
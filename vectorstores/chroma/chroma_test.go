@@ -100,7 +100,7 @@ func TestChromaStoreRestWithScoreThreshold(t *testing.T) {
 		{PageContent: "Paris"},
 		{PageContent: "London "},
 		{PageContent: "New York"},
-	})
+	}, vectorstores.WithScoreThreshold(0.8)) // Ignored when adding documents.
 	require.NoError(t, err)
 
 	// test with a score threshold of 0.8, expected 6 documents
@@ -109,6 +109,10 @@ func TestChromaStoreRestWithScoreThreshold(t *testing.T) {
 		vectorstores.WithScoreThreshold(0.8))
 	require.NoError(t, err)
 	require.Len(t, docs, 6)
+	for _, doc := range docs {
+		require.GreaterOrEqual(t, doc.Score, float32(0.8))
+		require.LessOrEqual(t, doc.Score, float32(1))
+	}
 
 	// test with a score threshold of 0, expected all 10 documents
 	docs, err = s.SimilaritySearch(context.Background(),
@@ -563,6 +567,104 @@ func TestChromaAsRetrieverWithMetadataFilters(t *testing.T) {
 	require.Contains(t, result, "purple", "expected black in purple")
 }
 
+func TestChromaStoreUpsertDeleteAndGet(t *testing.T) {
+	t.Parallel()
+
+	testChromaURL, openaiAPIKey := getValues(t)
+	e, err := openaiEmbeddings.NewOpenAI()
+	require.NoError(t, err)
+
+	s, err := chroma.New(
+		chroma.WithOpenAiAPIKey(openaiAPIKey),
+		chroma.WithChromaURL(testChromaURL),
+		chroma.WithNameSpace(getTestNameSpace()),
+		chroma.WithCollectionName(getTestCollectionName()),
+		chroma.WithEmbedder(e),
+	)
+	require.NoError(t, err)
+
+	defer cleanupTestArtifacts(t, s)
+
+	ids := []string{"city", "vegetable"}
+	err = s.UpsertDocuments(context.Background(), ids, []schema.Document{
+		{PageContent: "tokyo", Metadata: map[string]any{"country": "japan"}},
+		{PageContent: "potato", Metadata: map[string]any{"country": "peru"}},
+	})
+	require.NoError(t, err)
+
+	// Upserting replaces the document with the same id.
+	err = s.UpsertDocuments(context.Background(), ids[:1], []schema.Document{
+		{PageContent: "kyoto", Metadata: map[string]any{"country": "japan"}},
+	})
+	require.NoError(t, err)
+
+	docs, err := s.GetDocumentsByID(context.Background(), append(ids, "missing"))
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	require.Equal(t, "kyoto", docs["city"].PageContent)
+	require.Equal(t, "japan", docs["city"].Metadata["country"])
+
+	// The same id is a different document in another name space.
+	otherNameSpace := vectorstores.WithNameSpace(getTestNameSpace())
+	err = s.UpsertDocuments(context.Background(), ids[:1], []schema.Document{
+		{PageContent: "paris", Metadata: map[string]any{"country": "france"}},
+	}, otherNameSpace)
+	require.NoError(t, err)
+	docs, err = s.GetDocumentsByID(context.Background(), ids[:1], otherNameSpace)
+	require.NoError(t, err)
+	require.Equal(t, "paris", docs["city"].PageContent)
+	docs, err = s.GetDocumentsByID(context.Background(), ids[:1])
+	require.NoError(t, err)
+	require.Equal(t, "kyoto", docs["city"].PageContent)
+
+	require.NoError(t, s.DeleteDocumentsByID(context.Background(), ids[:1]))
+	require.NoError(t, s.DeleteDocuments(context.Background(),
+		vectorstores.WithFilters(map[string]any{"country": "peru"})))
+
+	docs, err = s.GetDocumentsByID(context.Background(), ids)
+	require.NoError(t, err)
+	require.Empty(t, docs)
+}
+
+func TestChromaStoreMMR(t *testing.T) {
+	t.Parallel()
+
+	testChromaURL, openaiAPIKey := getValues(t)
+	e, err := openaiEmbeddings.NewOpenAI()
+	require.NoError(t, err)
+
+	s, err := chroma.New(
+		chroma.WithOpenAiAPIKey(openaiAPIKey),
+		chroma.WithChromaURL(testChromaURL),
+		chroma.WithDistanceFunction(chromago.COSINE),
+		chroma.WithNameSpace(getTestNameSpace()),
+		chroma.WithCollectionName(getTestCollectionName()),
+		chroma.WithEmbedder(e),
+	)
+	require.NoError(t, err)
+
+	defer cleanupTestArtifacts(t, s)
+
+	err = s.AddDocuments(context.Background(), []schema.Document{
+		{PageContent: "Tokyo is the capital of Japan"},
+		{PageContent: "Tokyo is the capital city of Japan"},
+		{PageContent: "Kyoto was the capital of Japan"},
+		{PageContent: "potato"},
+	})
+	require.NoError(t, err)
+
+	docs, err := s.SimilaritySearch(context.Background(), "capital of Japan", 2,
+		vectorstores.WithMMR(4, 0.3))
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	require.Contains(t, docs[0].PageContent, "Tokyo")
+	require.Equal(t, "Kyoto was the capital of Japan", docs[1].PageContent)
+
+	_, err = s.SimilaritySearch(context.Background(), "capital of Japan", 2,
+		vectorstores.WithMMR(4, 1.5))
+	require.ErrorIs(t, err, vectorstores.ErrInvalidLambda)
+}
+
 func getValues(t *testing.T) (string, string) {
 	t.Helper()
 
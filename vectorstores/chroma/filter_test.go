@@ -0,0 +1,42 @@
+package chroma
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/vectorstores"
+	"github.com/tmc/langchaingo/vectorstores/filter"
+)
+
+func TestGetNamespacedFilter(t *testing.T) {
+	t.Parallel()
+
+	s := Store{nameSpace: "ns", nameSpaceKey: "nameSpace"}
+	where, err := s.getNamespacedFilter(vectorstores.Options{Filters: filter.Eq("location", "patio")})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"$and": []map[string]any{
+		{"nameSpace": "ns"},
+		{"location": map[string]any{"$eq": "patio"}},
+	}}, where)
+
+	// A filter matching all documents only restricts to the name space.
+	where, err = s.getNamespacedFilter(vectorstores.Options{Filters: filter.And()})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"nameSpace": "ns"}, where)
+}
+
+func TestDeleteDocumentsWithInvalidFilters(t *testing.T) {
+	t.Parallel()
+
+	s := Store{}
+	_, err := s.getNamespacedFilter(vectorstores.Options{Filters: map[string]string{"location": "patio"}})
+	require.ErrorIs(t, err, ErrInvalidFilter)
+
+	// Filters that can not be translated, or match all documents, delete nothing.
+	err = s.DeleteDocuments(context.Background(), vectorstores.WithFilters(map[string]string{"location": "patio"}))
+	require.ErrorIs(t, err, ErrInvalidFilter)
+	err = s.DeleteDocuments(context.Background(), vectorstores.WithFilters(map[string]any{}))
+	require.ErrorIs(t, err, vectorstores.ErrDeleteWithoutFilters)
+}
@@ -7,9 +7,21 @@ The main components of this package are:
 - VectorStore interface: a common interface for saving and querying vector embeddings of documents.
 - Options: a set of options for similarity search and document addition.
 - Retriever: a retriever for vector stores that implements the schema.Retriever interface.
+- WithMMR: an option for searching with maximal marginal relevance, returning relevant but
+diverse documents, in the vector stores and in their retrievers.
+- filter: a store-agnostic language for the metadata filters given with WithFilters.
+- Upserter, IDDeleter, FilterDeleter and Getter: optional interfaces implemented by vector stores
+that can store documents under stable ids, delete them and fetch them.
 
 The package provides a flexible way to handle different types of vector stores
 by using the VectorStore interface as an abstraction.
 It supports customization of the search and storage operation via the Options mechanism.
+
+The Score of the documents returned by SimilaritySearch is a relevance score between 0 and 1,
+higher scores meaning more relevant documents. For normalized embeddings, such as the ones of
+most embedding models, it is the cosine similarity of the query and document embeddings,
+clamped to [0, 1], whatever the distance used by the vector store. The RelevanceFrom functions
+convert the distances and similarities returned by vector stores to relevance scores, and
+WithScoreThreshold filters the documents by relevance score.
 */
 package vectorstores
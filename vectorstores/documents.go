@@ -0,0 +1,79 @@
+package vectorstores
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores/filter"
+)
+
+var (
+	// ErrMismatchedIDs is returned when the number of ids does not match the number of documents.
+	ErrMismatchedIDs = errors.New("number of ids does not match number of documents")
+	// ErrDeleteWithoutFilters is returned when deleting documents without filters, unless
+	// WithDeleteAll is set.
+	ErrDeleteWithoutFilters = errors.New("deleting documents without filters")
+)
+
+// Upserter is implemented by vector stores that can store documents under ids chosen by the
+// caller. A document replaces the stored document with the same id, so adding the documents
+// of an updated source again does not duplicate them.
+type Upserter interface {
+	UpsertDocuments(ctx context.Context, ids []string, docs []schema.Document, options ...Option) error
+}
+
+// IDDeleter is implemented by vector stores that can delete documents by id. Ids that are
+// not stored are ignored.
+type IDDeleter interface {
+	DeleteDocumentsByID(ctx context.Context, ids []string, options ...Option) error
+}
+
+// FilterDeleter is implemented by vector stores that can delete the documents matching the
+// filters set with WithFilters, in the name space set with WithNameSpace. So that a missing
+// filter does not wipe out the store, ErrDeleteWithoutFilters is returned when no filters are
+// set, or filters matching all documents such as an empty filter.And, unless WithDeleteAll is
+// set, in which case all documents of the name space are deleted.
+type FilterDeleter interface {
+	DeleteDocuments(ctx context.Context, options ...Option) error
+}
+
+// Getter is implemented by vector stores that can fetch documents by id. The documents are
+// returned by id, ids that are not stored are missing from the result.
+type Getter interface {
+	GetDocumentsByID(ctx context.Context, ids []string, options ...Option) (map[string]schema.Document, error)
+}
+
+// CheckDeleteFilters returns ErrDeleteWithoutFilters if the options set neither filters nor
+// WithDeleteAll, or set filters matching all documents without WithDeleteAll: a filter.Filter
+// simplifying to an empty filter.And, or an empty map.
+func CheckDeleteFilters(opts Options) error {
+	if !opts.DeleteAll && matchesAll(opts.Filters) {
+		return ErrDeleteWithoutFilters
+	}
+	return nil
+}
+
+// matchesAll reports whether the filters obviously match all documents.
+func matchesAll(filters any) bool {
+	switch f := filters.(type) {
+	case nil:
+		return true
+	case filter.Filter:
+		simplified := filter.Simplify(f)
+		l, ok := simplified.(filter.Logical)
+		return filter.IsEmpty(simplified) && ok && l.Operator == filter.OpAnd
+	case map[string]any:
+		return len(f) == 0
+	default:
+		return false
+	}
+}
+
+// CheckIDs returns ErrMismatchedIDs if there is not exactly one id per document.
+func CheckIDs(ids []string, docs []schema.Document) error {
+	if len(ids) != len(docs) {
+		return ErrMismatchedIDs
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package vectorstores
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tmc/langchaingo/vectorstores/filter"
+)
+
+func TestCheckDeleteFilters(t *testing.T) {
+	t.Parallel()
+
+	getOptions := func(options ...Option) Options {
+		opts := Options{}
+		for _, opt := range options {
+			opt(&opts)
+		}
+		return opts
+	}
+
+	assert.ErrorIs(t, CheckDeleteFilters(getOptions()), ErrDeleteWithoutFilters)
+	assert.ErrorIs(t, CheckDeleteFilters(getOptions(WithNameSpace("a"))), ErrDeleteWithoutFilters)
+	assert.NoError(t, CheckDeleteFilters(getOptions(WithDeleteAll())))
+	assert.NoError(t, CheckDeleteFilters(getOptions(WithFilters(filter.Eq("a", 1)))))
+
+	// Filters matching all documents need WithDeleteAll.
+	for _, filters := range []any{
+		filter.And(),
+		filter.And(filter.Or(filter.And()), filter.And()),
+		filter.Not(filter.Or()),
+		map[string]any{},
+	} {
+		assert.ErrorIs(t, CheckDeleteFilters(getOptions(WithFilters(filters))), ErrDeleteWithoutFilters)
+		assert.NoError(t, CheckDeleteFilters(getOptions(WithFilters(filters), WithDeleteAll())))
+	}
+	assert.NoError(t, CheckDeleteFilters(getOptions(WithFilters(filter.Or()))))
+}
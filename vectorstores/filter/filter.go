@@ -0,0 +1,395 @@
+// Package filter contains a store-agnostic language for filtering documents by metadata.
+//
+// Filters are built with Eq, Ne, In, Nin, Gt, Gte, Lt, Lte, And, Or and Not and given to
+// vector stores with vectorstores.WithFilters. The vector stores supporting them translate
+// them to their own filter syntax, and in-process stores evaluate them with Match:
+//
+//	vectorstores.WithFilters(filter.And(
+//		filter.Eq("country", "japan"),
+//		filter.Gte("population", 1_000_000),
+//	))
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"golang.org/x/exp/slices"
+)
+
+// ErrUnsupportedFilter is returned when a filter can not be translated to the syntax of a
+// vector store.
+var ErrUnsupportedFilter = errors.New("unsupported filter")
+
+// Filter is a filter on the metadata of documents. It is a Comparison, a Logical or a
+// Negation.
+type Filter interface {
+	// Match reports whether the metadata of a document matches the filter.
+	Match(metadata map[string]any) bool
+
+	isFilter()
+}
+
+// Operator is the operator of a Comparison.
+type Operator string
+
+const (
+	// OpEq matches documents whose value is equal to the value of the comparison.
+	OpEq Operator = "eq"
+	// OpNe matches documents whose value is not equal to the value of the comparison,
+	// including documents without the key.
+	OpNe Operator = "ne"
+	// OpIn matches documents whose value is one of the values of the comparison.
+	OpIn Operator = "in"
+	// OpNin matches documents whose value is none of the values of the comparison,
+	// including documents without the key.
+	OpNin Operator = "nin"
+	// OpGt matches documents whose value is greater than the value of the comparison.
+	OpGt Operator = "gt"
+	// OpGte matches documents whose value is greater than or equal to the value of the
+	// comparison.
+	OpGte Operator = "gte"
+	// OpLt matches documents whose value is less than the value of the comparison.
+	OpLt Operator = "lt"
+	// OpLte matches documents whose value is less than or equal to the value of the
+	// comparison.
+	OpLte Operator = "lte"
+)
+
+// Comparison compares the metadata value of a key with a value. The value is a string, a
+// bool or a number, or a slice of them for OpIn and OpNin.
+type Comparison struct {
+	Key      string
+	Operator Operator
+	Value    any
+}
+
+// LogicalOperator is the operator of a Logical filter.
+type LogicalOperator string
+
+const (
+	// OpAnd matches documents matching all the filters.
+	OpAnd LogicalOperator = "and"
+	// OpOr matches documents matching at least one of the filters.
+	OpOr LogicalOperator = "or"
+)
+
+// Logical combines filters with a logical operator.
+type Logical struct {
+	Operator LogicalOperator
+	Filters  []Filter
+}
+
+// Negation matches documents not matching its filter.
+type Negation struct {
+	Filter Filter
+}
+
+// Statically assert that the filter types implement the Filter interface.
+var (
+	_ Filter = Comparison{}
+	_ Filter = Logical{}
+	_ Filter = Negation{}
+)
+
+// Eq returns a filter matching documents whose value of the key is equal to value.
+func Eq(key string, value any) Comparison {
+	return Comparison{Key: key, Operator: OpEq, Value: value}
+}
+
+// Ne returns a filter matching documents whose value of the key is not equal to value.
+func Ne(key string, value any) Comparison {
+	return Comparison{Key: key, Operator: OpNe, Value: value}
+}
+
+// In returns a filter matching documents whose value of the key is one of values.
+func In(key string, values ...any) Comparison {
+	return Comparison{Key: key, Operator: OpIn, Value: values}
+}
+
+// Nin returns a filter matching documents whose value of the key is none of values.
+func Nin(key string, values ...any) Comparison {
+	return Comparison{Key: key, Operator: OpNin, Value: values}
+}
+
+// Gt returns a filter matching documents whose value of the key is greater than value.
+func Gt(key string, value any) Comparison {
+	return Comparison{Key: key, Operator: OpGt, Value: value}
+}
+
+// Gte returns a filter matching documents whose value of the key is greater than or equal
+// to value.
+func Gte(key string, value any) Comparison {
+	return Comparison{Key: key, Operator: OpGte, Value: value}
+}
+
+// Lt returns a filter matching documents whose value of the key is less than value.
+func Lt(key string, value any) Comparison {
+	return Comparison{Key: key, Operator: OpLt, Value: value}
+}
+
+// Lte returns a filter matching documents whose value of the key is less than or equal to
+// value.
+func Lte(key string, value any) Comparison {
+	return Comparison{Key: key, Operator: OpLte, Value: value}
+}
+
+// And returns a filter matching documents matching all the filters.
+func And(filters ...Filter) Logical {
+	return Logical{Operator: OpAnd, Filters: filters}
+}
+
+// Or returns a filter matching documents matching at least one of the filters.
+func Or(filters ...Filter) Logical {
+	return Logical{Operator: OpOr, Filters: filters}
+}
+
+// Not returns a filter matching documents not matching the filter.
+func Not(filter Filter) Negation {
+	return Negation{Filter: filter}
+}
+
+// Values returns the values of an OpIn or OpNin comparison.
+func (c Comparison) Values() ([]any, error) {
+	switch values := c.Value.(type) {
+	case []any:
+		return values, nil
+	case []string:
+		return toAnySlice(values), nil
+	case []int:
+		return toAnySlice(values), nil
+	case []float64:
+		return toAnySlice(values), nil
+	default:
+		return nil, fmt.Errorf("%w: %s needs a slice of values, got %T",
+			ErrUnsupportedFilter, c.Operator, c.Value)
+	}
+}
+
+// Match reports whether the metadata value of the key compares to the value of the
+// comparison. Numbers of different types are compared as float64, and strings compare
+// lexicographically.
+func (c Comparison) Match(metadata map[string]any) bool {
+	value, ok := metadata[c.Key]
+
+	switch c.Operator {
+	case OpEq:
+		return ok && equal(value, c.Value)
+	case OpNe:
+		return !ok || !equal(value, c.Value)
+	case OpIn, OpNin:
+		values, err := c.Values()
+		if err != nil {
+			return false
+		}
+		found := ok && slices.ContainsFunc(values, func(v any) bool { return equal(value, v) })
+		return found == (c.Operator == OpIn)
+	default:
+		cmp, isComparable := compare(value, c.Value)
+		return ok && isComparable && matchOrder(c.Operator, cmp)
+	}
+}
+
+// matchOrder reports whether the result of a comparison matches a range operator.
+func matchOrder(operator Operator, cmp int) bool {
+	switch operator { //nolint:exhaustive
+	case OpGt:
+		return cmp > 0
+	case OpGte:
+		return cmp >= 0
+	case OpLt:
+		return cmp < 0
+	case OpLte:
+		return cmp <= 0
+	default:
+		return false
+	}
+}
+
+// Match reports whether the metadata matches all the filters, for OpAnd, or at least one of
+// them, for OpOr.
+func (l Logical) Match(metadata map[string]any) bool {
+	for _, filter := range l.Filters {
+		if filter.Match(metadata) == (l.Operator == OpOr) {
+			return l.Operator == OpOr
+		}
+	}
+	return l.Operator == OpAnd
+}
+
+// Match reports whether the metadata does not match the filter.
+func (n Negation) Match(metadata map[string]any) bool {
+	return !n.Filter.Match(metadata)
+}
+
+func (Comparison) isFilter() {}
+func (Logical) isFilter()    {}
+func (Negation) isFilter()   {}
+
+// PushDownNot returns a filter equivalent to the filter without Negation, for stores whose
+// syntax has no negation. Negations of logical filters are rewritten with De Morgan's laws
+// and negations of comparisons use the opposite operator. Negated range comparisons, such as
+// Not(Gt(key, 1)) rewritten as Lte(key, 1), no longer match documents without the key.
+func PushDownNot(filter Filter) (Filter, error) {
+	return pushDownNot(filter, false)
+}
+
+func pushDownNot(filter Filter, negate bool) (Filter, error) {
+	switch f := filter.(type) {
+	case Comparison:
+		if !negate {
+			return f, nil
+		}
+		opposite, ok := _oppositeOperators[f.Operator]
+		if !ok {
+			return nil, fmt.Errorf("%w: operator %q", ErrUnsupportedFilter, f.Operator)
+		}
+		return Comparison{Key: f.Key, Operator: opposite, Value: f.Value}, nil
+	case Logical:
+		operator := f.Operator
+		if negate {
+			operator = OpOr
+			if f.Operator == OpOr {
+				operator = OpAnd
+			}
+		}
+		filters := make([]Filter, 0, len(f.Filters))
+		for _, sub := range f.Filters {
+			pushed, err := pushDownNot(sub, negate)
+			if err != nil {
+				return nil, err
+			}
+			filters = append(filters, pushed)
+		}
+		return Logical{Operator: operator, Filters: filters}, nil
+	case Negation:
+		return pushDownNot(f.Filter, !negate)
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedFilter, filter)
+	}
+}
+
+// Simplify returns a filter equivalent to the filter whose logical filters have at least two
+// operands, for stores whose syntax has no empty or single-operand logical expressions. Empty
+// logical filters are only left when the whole filter is one: an empty And, which matches
+// all documents, or an empty Or, which matches none. Negations of empty logical filters are
+// rewritten as the opposite empty logical filter.
+func Simplify(filter Filter) Filter {
+	switch f := filter.(type) {
+	case Logical:
+		// An empty And is true and an empty Or is false. True operands are dropped from an And
+		// and make an Or true, false operands are dropped from an Or and make an And false.
+		filters := make([]Filter, 0, len(f.Filters))
+		for _, sub := range f.Filters {
+			simplified := Simplify(sub)
+			if l, ok := simplified.(Logical); ok && len(l.Filters) == 0 {
+				if l.Operator == f.Operator {
+					continue
+				}
+				return Logical{Operator: l.Operator}
+			}
+			filters = append(filters, simplified)
+		}
+		switch len(filters) {
+		case 0:
+			return Logical{Operator: f.Operator}
+		case 1:
+			return filters[0]
+		}
+		return Logical{Operator: f.Operator, Filters: filters}
+	case Negation:
+		simplified := Simplify(f.Filter)
+		if l, ok := simplified.(Logical); ok && len(l.Filters) == 0 {
+			if l.Operator == OpAnd {
+				return Or()
+			}
+			return And()
+		}
+		return Negation{Filter: simplified}
+	default:
+		return filter
+	}
+}
+
+// IsEmpty reports whether the filter is an empty logical filter, an And matching all
+// documents or an Or matching none, see Simplify.
+func IsEmpty(filter Filter) bool {
+	l, ok := filter.(Logical)
+	return ok && len(l.Filters) == 0
+}
+
+var _oppositeOperators = map[Operator]Operator{ //nolint:gochecknoglobals
+	OpEq:  OpNe,
+	OpNe:  OpEq,
+	OpIn:  OpNin,
+	OpNin: OpIn,
+	OpGt:  OpLte,
+	OpGte: OpLt,
+	OpLt:  OpGte,
+	OpLte: OpGt,
+}
+
+// equal reports whether two metadata values are equal, comparing numbers as float64.
+func equal(a, b any) bool {
+	if cmp, ok := compare(a, b); ok {
+		return cmp == 0
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// compare returns -1, 0 or 1 if a is less than, equal to or greater than b, and whether the
+// values are comparable: both numbers or both strings.
+func compare(a, b any) (int, bool) {
+	if x, ok := toFloat64(a); ok {
+		y, ok := toFloat64(b)
+		if !ok {
+			return 0, false
+		}
+		return compareOrdered(x, y), true
+	}
+
+	x, ok := a.(string)
+	if !ok {
+		return 0, false
+	}
+	y, ok := b.(string)
+	if !ok {
+		return 0, false
+	}
+	return compareOrdered(x, y), true
+}
+
+func compareOrdered[T float64 | string](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// toFloat64 returns a number of any numeric type as a float64.
+func toFloat64(value any) (float64, bool) {
+	v := reflect.ValueOf(value)
+	switch {
+	case v.CanInt():
+		return float64(v.Int()), true
+	case v.CanUint():
+		return float64(v.Uint()), true
+	case v.CanFloat():
+		return v.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+func toAnySlice[T any](values []T) []any {
+	anyValues := make([]any, 0, len(values))
+	for _, v := range values {
+		anyValues = append(anyValues, v)
+	}
+	return anyValues
+}
@@ -0,0 +1,123 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	metadata := map[string]any{
+		"country":    "japan",
+		"population": 13_960_000,
+		"area":       2194.07,
+		"capital":    true,
+	}
+
+	tests := []struct {
+		filter Filter
+		want   bool
+	}{
+		{Eq("country", "japan"), true},
+		{Eq("country", "france"), false},
+		{Eq("population", 13_960_000.0), true},
+		{Eq("capital", true), true},
+		{Eq("missing", "x"), false},
+		{Ne("country", "france"), true},
+		{Ne("missing", "x"), true},
+		{In("country", "france", "japan"), true},
+		{In("country", "france", "italy"), false},
+		{Comparison{Key: "country", Operator: OpIn, Value: []string{"japan"}}, true},
+		{Nin("country", "france", "italy"), true},
+		{Nin("missing", "x"), true},
+		{Gt("population", 10_000_000), true},
+		{Gte("area", 2194.07), true},
+		{Lt("area", 2000), false},
+		{Lte("country", "japan"), true},
+		{Gt("country", 1), false},
+		{Gt("missing", 1), false},
+		{And(Eq("country", "japan"), Gte("population", 1_000_000)), true},
+		{And(Eq("country", "japan"), Lt("population", 1_000_000)), false},
+		{Or(Eq("country", "france"), Eq("capital", true)), true},
+		{Or(Eq("country", "france"), Eq("capital", false)), false},
+		{Not(Eq("country", "japan")), false},
+		{Not(And(Eq("country", "france"), Eq("capital", true))), true},
+		{And(), true},
+		{Or(), false},
+	}
+	for _, tc := range tests {
+		assert.Equal(t, tc.want, tc.filter.Match(metadata), "%#v", tc.filter)
+	}
+}
+
+func TestPushDownNot(t *testing.T) {
+	t.Parallel()
+
+	pushed, err := PushDownNot(Not(And(
+		Eq("country", "japan"),
+		Or(In("city", "tokyo", "osaka"), Not(Gt("population", 100))),
+	)))
+	require.NoError(t, err)
+	assert.Equal(t, Or(
+		Ne("country", "japan"),
+		And(Nin("city", "tokyo", "osaka"), Gt("population", 100)),
+	), pushed)
+
+	pushed, err = PushDownNot(Not(Not(Lte("population", 100))))
+	require.NoError(t, err)
+	assert.Equal(t, Lte("population", 100), pushed)
+
+	_, err = PushDownNot(Not(Comparison{Key: "a", Operator: "like", Value: "b"}))
+	assert.ErrorIs(t, err, ErrUnsupportedFilter)
+}
+
+func TestSimplify(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, Eq("a", 1), Simplify(And(Eq("a", 1))))
+	assert.Equal(t, Eq("a", 1), Simplify(And(And(), Eq("a", 1), Or(And()))))
+	assert.Equal(t, And(), Simplify(Or(Eq("a", 1), And())))
+	assert.Equal(t, Or(), Simplify(And(Eq("a", 1), Or())))
+	assert.Equal(t, Eq("a", 1), Simplify(Or(Or(), Eq("a", 1))))
+	assert.Equal(t, Or(), Simplify(Not(And())))
+	assert.Equal(t, And(Eq("a", 1), Not(Eq("b", 2))), Simplify(And(Eq("a", 1), Not(Or(Eq("b", 2))))))
+	assert.True(t, IsEmpty(Simplify(And(And(), And()))))
+	assert.False(t, IsEmpty(Eq("a", 1)))
+}
+
+func TestToMongo(t *testing.T) {
+	t.Parallel()
+
+	query, err := ToMongo(Or(
+		Eq("genre", "drama"),
+		Not(Or(Lt("year", 2000), Gt("year", 2010))),
+		And(In("tag", "a", "b")),
+	))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"$or": []map[string]any{
+		{"genre": map[string]any{"$eq": "drama"}},
+		{"$and": []map[string]any{
+			{"year": map[string]any{"$gte": 2000}},
+			{"year": map[string]any{"$lte": 2010}},
+		}},
+		{"tag": map[string]any{"$in": []any{"a", "b"}}},
+	}}, query)
+
+	// Empty logical filters are not sent as {"$and": []}.
+	query, err = ToMongo(And(Eq("genre", "drama"), And()))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"genre": map[string]any{"$eq": "drama"}}, query)
+
+	query, err = ToMongo(And())
+	require.NoError(t, err)
+	assert.Nil(t, query)
+
+	_, err = ToMongo(Or())
+	assert.ErrorIs(t, err, ErrUnsupportedFilter)
+
+	_, err = ToMongo(Comparison{Key: "genre", Operator: OpIn, Value: "drama"})
+	assert.ErrorIs(t, err, ErrUnsupportedFilter)
+}
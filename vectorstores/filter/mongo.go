@@ -0,0 +1,51 @@
+package filter
+
+import "fmt"
+
+// ToMongo translates a filter to the syntax modeled on MongoDB queries of Chroma and
+// Pinecone, e.g. {"$and": [{"genre": {"$eq": "drama"}}, {"year": {"$gte": 2000}}]}. That
+// syntax has no negation and no empty logical expressions, Chroma also needing two operands,
+// so the filter is rewritten with PushDownNot and Simplify first. A filter matching all
+// documents is translated to nil, a filter matching none returns ErrUnsupportedFilter.
+func ToMongo(filter Filter) (map[string]any, error) {
+	pushed, err := PushDownNot(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	simplified := Simplify(pushed)
+	if IsEmpty(simplified) {
+		if simplified.(Logical).Operator == OpOr { //nolint:forcetypeassert
+			return nil, fmt.Errorf("%w: empty or", ErrUnsupportedFilter)
+		}
+		return nil, nil
+	}
+	return toMongo(simplified)
+}
+
+func toMongo(filter Filter) (map[string]any, error) {
+	switch f := filter.(type) {
+	case Comparison:
+		value := f.Value
+		if f.Operator == OpIn || f.Operator == OpNin {
+			values, err := f.Values()
+			if err != nil {
+				return nil, err
+			}
+			value = values
+		}
+		return map[string]any{f.Key: map[string]any{"$" + string(f.Operator): value}}, nil
+	case Logical:
+		operands := make([]map[string]any, 0, len(f.Filters))
+		for _, sub := range f.Filters {
+			operand, err := toMongo(sub)
+			if err != nil {
+				return nil, err
+			}
+			operands = append(operands, operand)
+		}
+		return map[string]any{"$" + string(f.Operator): operands}, nil
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedFilter, filter)
+	}
+}
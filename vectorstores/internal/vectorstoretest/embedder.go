@@ -0,0 +1,49 @@
+// Package vectorstoretest contains helpers shared by the tests of the vector stores.
+package vectorstoretest
+
+import (
+	"context"
+	"strings"
+
+	"github.com/tmc/langchaingo/embeddings"
+)
+
+// _words are the words counted by WordEmbedder.
+var _words = []string{"tokyo", "kyoto", "japan", "paris", "france", "potato"} //nolint:gochecknoglobals
+
+// WordEmbedderDimensions is the number of dimensions of the vectors of WordEmbedder.
+const WordEmbedderDimensions = 7
+
+// WordEmbedder embeds texts as the counts of a few words, so tests do not need an
+// embedding model.
+type WordEmbedder struct{}
+
+var _ embeddings.Embedder = WordEmbedder{}
+
+// EmbedDocuments embeds each text.
+func (e WordEmbedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, 0, len(texts))
+	for _, text := range texts {
+		vector, err := e.EmbedQuery(ctx, text)
+		if err != nil {
+			return nil, err
+		}
+		vectors = append(vectors, vector)
+	}
+	return vectors, nil
+}
+
+// EmbedQuery embeds the text as the counts of the words, plus a small constant component
+// to avoid zero vectors.
+func (WordEmbedder) EmbedQuery(_ context.Context, text string) ([]float32, error) {
+	vector := make([]float32, WordEmbedderDimensions)
+	vector[len(_words)] = 0.01
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		for i, w := range _words {
+			if word == w {
+				vector[i]++
+			}
+		}
+	}
+	return vector, nil
+}
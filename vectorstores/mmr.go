@@ -0,0 +1,140 @@
+package vectorstores
+
+import (
+	"errors"
+	"math"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+const _defaultMMRFetchK = 20
+
+// ErrInvalidLambda is returned when the lambda of a maximal marginal relevance search is
+// not between 0 and 1.
+var ErrInvalidLambda = errors.New("lambda must be between 0 and 1")
+
+// MMROptions are the options of a maximal marginal relevance search, see WithMMR.
+type MMROptions struct {
+	// FetchK is the number of candidates fetched by similarity before diversifying them.
+	FetchK int
+	// Lambda trades relevance, with 1, for diversity, with 0.
+	Lambda float32
+}
+
+// WithMMR returns an Option for searching documents with maximal marginal relevance: fetchK
+// candidates are fetched by similarity with the query, and the documents returned are picked
+// one by one, each maximizing lambda * similarity to the query - (1 - lambda) * the highest
+// similarity to the documents already picked. This avoids returning near-duplicates. If fetchK
+// is not positive, 20 candidates are fetched. Vector stores that can not return the vectors
+// of the documents return an error.
+func WithMMR(fetchK int, lambda float32) Option {
+	return func(o *Options) {
+		o.MMR = &MMROptions{FetchK: fetchK, Lambda: lambda}
+	}
+}
+
+// GetFetchK returns the number of candidates to fetch to return numDocuments documents.
+func (o MMROptions) GetFetchK(numDocuments int) int {
+	fetchK := o.FetchK
+	if fetchK <= 0 {
+		fetchK = _defaultMMRFetchK
+	}
+	if fetchK < numDocuments {
+		return numDocuments
+	}
+	return fetchK
+}
+
+// Validate returns ErrInvalidLambda if lambda is not between 0 and 1.
+func (o MMROptions) Validate() error {
+	if o.Lambda < 0 || o.Lambda > 1 {
+		return ErrInvalidLambda
+	}
+	return nil
+}
+
+// MaximalMarginalRelevance returns the indexes of the k embeddings selected by maximal marginal
+// relevance with the query embedding, in the order they were selected. Lambda trades relevance
+// with the query, with 1, for diversity among the selected embeddings, with 0. Similarities are
+// cosine similarities.
+func MaximalMarginalRelevance(queryEmbedding []float32, embeddings [][]float32, lambda float32, k int) []int {
+	if k > len(embeddings) {
+		k = len(embeddings)
+	}
+	if k <= 0 {
+		return []int{}
+	}
+
+	relevance := make([]float64, len(embeddings))
+	for i, embedding := range embeddings {
+		relevance[i] = CosineSimilarity(queryEmbedding, embedding)
+	}
+
+	// redundancy[i] is the highest similarity of the i-th embedding to the selected ones.
+	redundancy := make([]float64, len(embeddings))
+	for i := range redundancy {
+		redundancy[i] = math.Inf(-1)
+	}
+
+	selected := make([]int, 0, k)
+	isSelected := make([]bool, len(embeddings))
+	for len(selected) < k {
+		best, bestScore := -1, math.Inf(-1)
+		for i := range embeddings {
+			if isSelected[i] {
+				continue
+			}
+
+			score := float64(lambda) * relevance[i]
+			if len(selected) > 0 {
+				score -= float64(1-lambda) * redundancy[i]
+			}
+			// Embeddings with NaN values are ranked last instead of never being selected.
+			if math.IsNaN(score) {
+				score = math.Inf(-1)
+			}
+			if best < 0 || score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+
+		selected = append(selected, best)
+		isSelected[best] = true
+		for i, embedding := range embeddings {
+			if !isSelected[i] {
+				redundancy[i] = math.Max(redundancy[i], CosineSimilarity(embeddings[best], embedding))
+			}
+		}
+	}
+
+	return selected
+}
+
+// MaximalMarginalRelevanceDocuments returns the k documents selected by maximal marginal
+// relevance, see MaximalMarginalRelevance. The i-th embedding is the embedding of the i-th
+// document.
+func MaximalMarginalRelevanceDocuments(
+	queryEmbedding []float32, docs []schema.Document, embeddings [][]float32, lambda float32, k int,
+) []schema.Document {
+	selected := MaximalMarginalRelevance(queryEmbedding, embeddings, lambda, k)
+
+	mmrDocs := make([]schema.Document, 0, len(selected))
+	for _, i := range selected {
+		mmrDocs = append(mmrDocs, docs[i])
+	}
+	return mmrDocs
+}
+
+// CosineSimilarity returns the cosine similarity of two vectors, or 0 if one of them is zero.
+func CosineSimilarity(a, b []float32) float64 {
+	var dot, normA, normB float64
+	for i := 0; i < len(a) && i < len(b); i++ {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
@@ -0,0 +1,64 @@
+package vectorstores
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestCosineSimilarity(t *testing.T) {
+	t.Parallel()
+
+	assert.InDelta(t, 1, CosineSimilarity([]float32{1, 2}, []float32{2, 4}), 1e-9)
+	assert.InDelta(t, 0, CosineSimilarity([]float32{1, 0}, []float32{0, 1}), 1e-9)
+	assert.InDelta(t, -1, CosineSimilarity([]float32{1, 0}, []float32{-1, 0}), 1e-9)
+	assert.InDelta(t, 0, CosineSimilarity([]float32{0, 0}, []float32{1, 0}), 1e-9)
+}
+
+func TestMaximalMarginalRelevance(t *testing.T) {
+	t.Parallel()
+
+	query := []float32{1, 1, 0}
+	embeddings := [][]float32{
+		{1, 0.3, 0},
+		{1, 0.31, 0}, // The most relevant, and a near-duplicate of the first embedding.
+		{0.3, 1, 0},  // As relevant as the first embedding but different.
+		{0, 0, 1},
+	}
+
+	// With lambda 1, the embeddings are ordered by relevance.
+	assert.Equal(t, []int{1, 0, 2}, MaximalMarginalRelevance(query, embeddings, 1, 3))
+	// With a lower lambda, the near-duplicate is skipped.
+	assert.Equal(t, []int{1, 2}, MaximalMarginalRelevance(query, embeddings, 0.5, 2))
+	// k is capped to the number of embeddings.
+	assert.Len(t, MaximalMarginalRelevance(query, embeddings, 0.5, 10), 4)
+	assert.Empty(t, MaximalMarginalRelevance(query, nil, 0.5, 2))
+
+	// Embeddings with NaN values are selected last.
+	nan := float32(math.NaN())
+	withNaN := [][]float32{{nan, 0, 0}, {0, 1, 0}, {nan, nan, nan}}
+	assert.Equal(t, []int{1, 0, 2}, MaximalMarginalRelevance(query, withNaN, 0.5, 3))
+	assert.Len(t, MaximalMarginalRelevance(query, withNaN[2:], 0.5, 1), 1)
+
+	docs := []schema.Document{{PageContent: "a"}, {PageContent: "a'"}, {PageContent: "b"}, {PageContent: "c"}}
+	assert.Equal(t, []schema.Document{{PageContent: "a'"}, {PageContent: "b"}},
+		MaximalMarginalRelevanceDocuments(query, docs, embeddings, 0.5, 2))
+}
+
+func TestMMROptions(t *testing.T) {
+	t.Parallel()
+
+	var opts Options
+	WithMMR(0, 0.5)(&opts)
+	require.NotNil(t, opts.MMR)
+	assert.Equal(t, _defaultMMRFetchK, opts.MMR.GetFetchK(4))
+	assert.Equal(t, 30, opts.MMR.GetFetchK(30))
+	require.NoError(t, opts.MMR.Validate())
+
+	assert.Equal(t, 10, MMROptions{FetchK: 10}.GetFetchK(4))
+	assert.ErrorIs(t, MMROptions{Lambda: 1.5}.Validate(), ErrInvalidLambda)
+	assert.ErrorIs(t, MMROptions{Lambda: -0.1}.Validate(), ErrInvalidLambda)
+}
@@ -0,0 +1,6 @@
+// Package opensearch contains an implementation of the vectorStore interface using the
+// k-NN plugin of OpenSearch, through its REST API. Each name space is a separate index,
+// created when documents are first added to it. Elasticsearch is not supported: its
+// dense_vector fields and knn searches differ from the knn_vector fields and k-NN queries
+// of OpenSearch used by this package.
+package opensearch
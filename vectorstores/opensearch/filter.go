@@ -0,0 +1,79 @@
+package opensearch
+
+import (
+	"fmt"
+
+	"github.com/tmc/langchaingo/vectorstores/filter"
+)
+
+// queryFromFilter translates a filter to an OpenSearch query on the metadata fields of the
+// documents. Strings are mapped as keywords, so they are compared exactly.
+func queryFromFilter(f filter.Filter) (map[string]any, error) {
+	switch f := f.(type) {
+	case filter.Comparison:
+		return translateComparison(f)
+	case filter.Logical:
+		if f.Operator == filter.OpOr && len(f.Filters) == 0 {
+			return map[string]any{"match_none": map[string]any{}}, nil
+		}
+		queries, err := translateFilters(f.Filters)
+		if err != nil {
+			return nil, err
+		}
+		if f.Operator == filter.OpOr {
+			return map[string]any{"bool": map[string]any{"should": queries, "minimum_should_match": 1}}, nil
+		}
+		return boolQuery("filter", queries), nil
+	case filter.Negation:
+		queries, err := translateFilters([]filter.Filter{f.Filter})
+		if err != nil {
+			return nil, err
+		}
+		return boolQuery("must_not", queries), nil
+	default:
+		return nil, fmt.Errorf("%w: %T", filter.ErrUnsupportedFilter, f)
+	}
+}
+
+func translateFilters(filters []filter.Filter) ([]map[string]any, error) {
+	queries := make([]map[string]any, 0, len(filters))
+	for _, sub := range filters {
+		query, err := queryFromFilter(sub)
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, query)
+	}
+	return queries, nil
+}
+
+func translateComparison(c filter.Comparison) (map[string]any, error) {
+	field := fmt.Sprintf("%s.%s", _metadataField, c.Key)
+
+	switch c.Operator {
+	case filter.OpEq:
+		return map[string]any{"term": map[string]any{field: c.Value}}, nil
+	case filter.OpNe:
+		term := map[string]any{"term": map[string]any{field: c.Value}}
+		return boolQuery("must_not", []map[string]any{term}), nil
+	case filter.OpIn, filter.OpNin:
+		values, err := c.Values()
+		if err != nil {
+			return nil, err
+		}
+		terms := map[string]any{"terms": map[string]any{field: values}}
+		if c.Operator == filter.OpNin {
+			return boolQuery("must_not", []map[string]any{terms}), nil
+		}
+		return terms, nil
+	case filter.OpGt, filter.OpGte, filter.OpLt, filter.OpLte:
+		return map[string]any{"range": map[string]any{field: map[string]any{string(c.Operator): c.Value}}}, nil
+	default:
+		return nil, fmt.Errorf("%w: operator %q", filter.ErrUnsupportedFilter, c.Operator)
+	}
+}
+
+// boolQuery returns a bool query with the queries in the clause.
+func boolQuery(clause string, queries []map[string]any) map[string]any {
+	return map[string]any{"bool": map[string]any{clause: queries}}
+}
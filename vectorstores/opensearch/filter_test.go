@@ -0,0 +1,54 @@
+package opensearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/vectorstores/filter"
+)
+
+func TestQueryFromFilter(t *testing.T) {
+	t.Parallel()
+
+	query, err := queryFromFilter(filter.Eq("city", "London"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"term": map[string]any{"metadata.city": "London"}}, query)
+
+	query, err = queryFromFilter(filter.Or(
+		filter.And(filter.In("city", "London", "Berlin"), filter.Gte("price", 10)),
+		filter.Not(filter.Ne("color", "red")),
+	))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"bool": map[string]any{
+		"minimum_should_match": 1,
+		"should": []map[string]any{
+			{"bool": map[string]any{"filter": []map[string]any{
+				{"terms": map[string]any{"metadata.city": []any{"London", "Berlin"}}},
+				{"range": map[string]any{"metadata.price": map[string]any{"gte": 10}}},
+			}}},
+			{"bool": map[string]any{"must_not": []map[string]any{
+				{"bool": map[string]any{"must_not": []map[string]any{
+					{"term": map[string]any{"metadata.color": "red"}},
+				}}},
+			}}},
+		},
+	}}, query)
+
+	query, err = queryFromFilter(filter.Or())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"match_none": map[string]any{}}, query)
+}
+
+func TestRelevance(t *testing.T) {
+	t.Parallel()
+
+	// The scores of documents with a cosine similarity of 0.5 with the query.
+	assert.InDelta(t, 0.5, Store{spaceType: SpaceTypeCosine, engine: EngineLucene}.relevance(0.75), 1e-6)
+	assert.InDelta(t, 0.5, Store{spaceType: SpaceTypeCosine, engine: EngineNmslib}.relevance(1/1.5), 1e-6)
+	assert.InDelta(t, 0.5, Store{spaceType: SpaceTypeInnerProduct}.relevance(1.5), 1e-6)
+	// Normalized embeddings with a cosine similarity of 0.5 have a squared distance of 1.
+	assert.InDelta(t, 0.5, Store{spaceType: SpaceTypeL2}.relevance(0.5), 1e-6)
+
+	assert.InDelta(t, 0, Store{spaceType: SpaceTypeInnerProduct}.relevance(0.5), 1e-6)
+}
@@ -0,0 +1,298 @@
+package opensearch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+	"github.com/tmc/langchaingo/vectorstores/filter"
+)
+
+const (
+	_textField      = "text"
+	_metadataField  = "metadata"
+	_embeddingField = "embedding"
+)
+
+var (
+	// ErrEmbedderWrongNumberVectors is returned when if the embedder returns a number
+	// of vectors that is not equal to the number of documents given.
+	ErrEmbedderWrongNumberVectors = errors.New(
+		"number of vectors from embedder does not match number of documents",
+	)
+	// ErrInvalidScoreThreshold is returned when the score threshold is not between 0 and 1.
+	ErrInvalidScoreThreshold = errors.New("score threshold must be between 0 and 1")
+	// ErrInvalidNameSpace is returned when a name space can not be part of an index name.
+	ErrInvalidNameSpace = errors.New("invalid name space")
+)
+
+// Store is a vector store using the k-NN plugin of OpenSearch. The documents are stored in
+// an index with their text, metadata and embedding. Each name space is a separate index.
+type Store struct {
+	embedder embeddings.Embedder
+	client   *http.Client
+
+	url       string
+	username  string
+	password  string
+	indexName string
+	spaceType SpaceType
+	engine    Engine
+}
+
+var (
+	_ vectorstores.VectorStore   = Store{}
+	_ vectorstores.Upserter      = Store{}
+	_ vectorstores.IDDeleter     = Store{}
+	_ vectorstores.FilterDeleter = Store{}
+	_ vectorstores.Getter        = Store{}
+)
+
+// New creates a new Store with options. Options for the embedder and the url must be set.
+// Indexes are created when documents are first added to them, since their mapping depends
+// on the dimensions of the embeddings.
+func New(opts ...Option) (Store, error) {
+	return applyClientOptions(opts...)
+}
+
+// AddDocuments creates vector embeddings from the documents using the embedder and
+// indexes them under random ids.
+func (s Store) AddDocuments(ctx context.Context, docs []schema.Document, options ...vectorstores.Option) error {
+	ids := make([]string, 0, len(docs))
+	for range docs {
+		ids = append(ids, uuid.New().String())
+	}
+	return s.indexDocuments(ctx, ids, docs, options...)
+}
+
+// UpsertDocuments creates vector embeddings from the documents using the embedder and
+// indexes them under the given ids, replacing the documents with the same ids.
+func (s Store) UpsertDocuments(
+	ctx context.Context, ids []string, docs []schema.Document, options ...vectorstores.Option,
+) error {
+	if err := vectorstores.CheckIDs(ids, docs); err != nil {
+		return err
+	}
+	return s.indexDocuments(ctx, ids, docs, options...)
+}
+
+func (s Store) indexDocuments(
+	ctx context.Context, ids []string, docs []schema.Document, options ...vectorstores.Option,
+) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	opts := s.getOptions(options...)
+	index, err := s.getIndex(opts)
+	if err != nil {
+		return err
+	}
+
+	texts := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		texts = append(texts, doc.PageContent)
+	}
+	vectors, err := s.getEmbedder(opts).EmbedDocuments(ctx, texts)
+	if err != nil {
+		return err
+	}
+	if len(vectors) != len(docs) {
+		return ErrEmbedderWrongNumberVectors
+	}
+
+	exists, err := s.restIndexExists(ctx, index)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if err := s.restCreateIndex(ctx, index, len(vectors[0])); err != nil {
+			return err
+		}
+	}
+
+	sources := make([]source, 0, len(docs))
+	for i, doc := range docs {
+		sources = append(sources, source{Text: doc.PageContent, Metadata: doc.Metadata, Embedding: vectors[i]})
+	}
+	return s.restBulkIndex(ctx, index, ids, sources)
+}
+
+// SimilaritySearch creates a vector embedding from the query using the embedder and
+// returns the most similar documents, with their relevance score. Filters are a
+// filter.Filter or an OpenSearch query, and are applied during the search by the Lucene
+// and Faiss engines.
+func (s Store) SimilaritySearch(
+	ctx context.Context, query string, numDocuments int, options ...vectorstores.Option,
+) ([]schema.Document, error) {
+	opts := s.getOptions(options...)
+	if opts.ScoreThreshold < 0 || opts.ScoreThreshold > 1 {
+		return nil, ErrInvalidScoreThreshold
+	}
+	filters, err := s.getFilters(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	vector, err := s.getEmbedder(opts).EmbedQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := numDocuments
+	if opts.MMR != nil {
+		if err := opts.MMR.Validate(); err != nil {
+			return nil, err
+		}
+		limit = opts.MMR.GetFetchK(numDocuments)
+	}
+
+	index, err := s.getIndex(opts)
+	if err != nil {
+		return nil, err
+	}
+	docs, vectors, err := s.restSearch(ctx, index, vector, limit, filters, opts.MMR != nil)
+	if err != nil {
+		return nil, err
+	}
+
+	relevantDocs := make([]schema.Document, 0, len(docs))
+	relevantVectors := make([][]float32, 0, len(docs))
+	for i, doc := range docs {
+		if doc.Score >= opts.ScoreThreshold {
+			relevantDocs = append(relevantDocs, doc)
+			relevantVectors = append(relevantVectors, vectors[i])
+		}
+	}
+
+	if opts.MMR == nil {
+		return relevantDocs, nil
+	}
+	return vectorstores.MaximalMarginalRelevanceDocuments(
+		vector, relevantDocs, relevantVectors, opts.MMR.Lambda, numDocuments), nil
+}
+
+// DeleteDocumentsByID deletes the documents with the given ids.
+func (s Store) DeleteDocumentsByID(ctx context.Context, ids []string, options ...vectorstores.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	index, err := s.getIndex(s.getOptions(options...))
+	if err != nil {
+		return err
+	}
+	return s.restDeleteByQuery(ctx, index, map[string]any{"ids": map[string]any{"values": ids}})
+}
+
+// DeleteDocuments deletes the documents matching the filters. All the documents of the
+// index are deleted if no filters are given and vectorstores.WithDeleteAll is set.
+func (s Store) DeleteDocuments(ctx context.Context, options ...vectorstores.Option) error {
+	opts := s.getOptions(options...)
+	if err := vectorstores.CheckDeleteFilters(opts); err != nil {
+		return err
+	}
+	filters, err := s.getFilters(opts)
+	if err != nil {
+		return err
+	}
+	if filters == nil {
+		filters = map[string]any{"match_all": map[string]any{}}
+	}
+	index, err := s.getIndex(opts)
+	if err != nil {
+		return err
+	}
+	return s.restDeleteByQuery(ctx, index, filters)
+}
+
+// GetDocumentsByID returns the documents with the given ids.
+func (s Store) GetDocumentsByID(
+	ctx context.Context, ids []string, options ...vectorstores.Option,
+) (map[string]schema.Document, error) {
+	if len(ids) == 0 {
+		return map[string]schema.Document{}, nil
+	}
+	index, err := s.getIndex(s.getOptions(options...))
+	if err != nil {
+		return nil, err
+	}
+	return s.restGetDocuments(ctx, index, ids)
+}
+
+// RemoveIndex deletes the index of the name space set with vectorstores.WithNameSpace, or
+// the index of the store if none is set.
+func (s Store) RemoveIndex(ctx context.Context, options ...vectorstores.Option) error {
+	index, err := s.getIndex(s.getOptions(options...))
+	if err != nil {
+		return err
+	}
+	return s.restDeleteIndex(ctx, index)
+}
+
+// relevance returns the relevance score of a score returned by the k-NN plugin, which
+// depends on the space type and, for the cosine similarity, on the engine.
+func (s Store) relevance(score float32) float32 {
+	if score <= 0 {
+		return 0
+	}
+	switch s.spaceType {
+	case SpaceTypeL2:
+		// score = 1 / (1 + squared distance)
+		return vectorstores.RelevanceFromSquaredL2Distance(1/score - 1)
+	case SpaceTypeInnerProduct:
+		// score = 1 + inner product for positive inner products, 1 / (1 - inner product)
+		// otherwise.
+		if score >= 1 {
+			return vectorstores.RelevanceFromSimilarity(score - 1)
+		}
+		return vectorstores.RelevanceFromSimilarity(1 - 1/score)
+	default:
+		// Lucene scores are (1 + cosine similarity) / 2, the other engines score
+		// 1 / (1 + cosine distance).
+		if s.engine == EngineLucene {
+			return vectorstores.RelevanceFromSimilarity(2*score - 1)
+		}
+		return vectorstores.RelevanceFromCosineDistance(1/score - 1)
+	}
+}
+
+// getFilters returns the filters of the options, translated to an OpenSearch query if they
+// are a filter.Filter.
+func (s Store) getFilters(opts vectorstores.Options) (any, error) {
+	if f, ok := opts.Filters.(filter.Filter); ok {
+		return queryFromFilter(f)
+	}
+	return opts.Filters, nil
+}
+
+// getIndex returns the index of the name space of the options. The name space must
+// make a valid index name, see validIndexName.
+func (s Store) getIndex(opts vectorstores.Options) (string, error) {
+	if opts.NameSpace == "" {
+		return s.indexName, nil
+	}
+	index := s.indexName + "_" + opts.NameSpace
+	if !validIndexName(index) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidNameSpace, opts.NameSpace)
+	}
+	return index, nil
+}
+
+func (s Store) getOptions(options ...vectorstores.Option) vectorstores.Options {
+	opts := vectorstores.Options{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	return opts
+}
+
+func (s Store) getEmbedder(opts vectorstores.Options) embeddings.Embedder {
+	if opts.Embedder != nil {
+		return opts.Embedder
+	}
+	return s.embedder
+}
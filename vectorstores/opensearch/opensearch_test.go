@@ -0,0 +1,235 @@
+package opensearch_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+	"github.com/tmc/langchaingo/vectorstores/filter"
+	"github.com/tmc/langchaingo/vectorstores/internal/vectorstoretest"
+	"github.com/tmc/langchaingo/vectorstores/opensearch"
+)
+
+func newTestStore(t *testing.T, opts ...opensearch.Option) opensearch.Store {
+	t.Helper()
+
+	// export LANGCHAINGO_TEST_OPENSEARCH=http://localhost:9200
+	url := os.Getenv("LANGCHAINGO_TEST_OPENSEARCH")
+	if url == "" {
+		t.Skip("LANGCHAINGO_TEST_OPENSEARCH not set")
+	}
+
+	opts = append([]opensearch.Option{
+		opensearch.WithURL(url),
+		opensearch.WithEmbedder(vectorstoretest.WordEmbedder{}),
+		opensearch.WithIndexName(fmt.Sprintf("test_%s", strings.ReplaceAll(uuid.New().String(), "-", ""))),
+	}, opts...)
+	s, err := opensearch.New(opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, s.RemoveIndex(context.Background()))
+		require.NoError(t, s.RemoveIndex(context.Background(), vectorstores.WithNameSpace("ns")))
+	})
+	return s
+}
+
+func TestOpenSearchStore(t *testing.T) {
+	t.Parallel()
+
+	s := newTestStore(t)
+
+	err := s.AddDocuments(context.Background(), []schema.Document{
+		{PageContent: "tokyo japan", Metadata: map[string]any{"country": "japan", "population": 14}},
+		{PageContent: "kyoto japan", Metadata: map[string]any{"country": "japan", "population": 1.5}},
+		{PageContent: "paris france", Metadata: map[string]any{"country": "france", "population": 2.1}},
+		{PageContent: "potato"},
+	})
+	require.NoError(t, err)
+
+	docs, err := s.SimilaritySearch(context.Background(), "tokyo", 1)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Equal(t, "tokyo japan", docs[0].PageContent)
+	require.Equal(t, "japan", docs[0].Metadata["country"])
+	require.InDelta(t, 0.7, docs[0].Score, 0.01)
+
+	docs, err = s.SimilaritySearch(context.Background(), "japan", 4, vectorstores.WithScoreThreshold(0.5))
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+
+	docs, err = s.SimilaritySearch(context.Background(), "japan", 4,
+		vectorstores.WithFilters(filter.And(filter.Eq("country", "japan"), filter.Lt("population", 10))))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Equal(t, "kyoto japan", docs[0].PageContent)
+
+	// The population of the first document is an integer, but fractional values are not
+	// truncated.
+	docs, err = s.SimilaritySearch(context.Background(), "japan", 4,
+		vectorstores.WithFilters(filter.And(filter.Eq("country", "japan"), filter.Gt("population", 1.2))))
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+
+	docs, err = s.SimilaritySearch(context.Background(), "japan", 4,
+		vectorstores.WithFilters(filter.Not(filter.Eq("country", "japan"))))
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+
+	// Name spaces are separate indexes.
+	err = s.AddDocuments(context.Background(), []schema.Document{{PageContent: "paris"}},
+		vectorstores.WithNameSpace("ns"))
+	require.NoError(t, err)
+	docs, err = s.SimilaritySearch(context.Background(), "japan", 10, vectorstores.WithNameSpace("ns"))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+}
+
+func TestOpenSearchStoreUpsertDeleteAndGet(t *testing.T) {
+	t.Parallel()
+
+	s := newTestStore(t, opensearch.WithSpaceType(opensearch.SpaceTypeL2))
+
+	ids := []string{"city", "vegetable"}
+	err := s.UpsertDocuments(context.Background(), ids, []schema.Document{
+		{PageContent: "tokyo", Metadata: map[string]any{"country": "japan"}},
+		{PageContent: "potato", Metadata: map[string]any{"country": "peru"}},
+	})
+	require.NoError(t, err)
+
+	// Upserting replaces the document with the same id.
+	err = s.UpsertDocuments(context.Background(), ids[:1], []schema.Document{
+		{PageContent: "kyoto", Metadata: map[string]any{"country": "japan"}},
+	})
+	require.NoError(t, err)
+
+	docs, err := s.GetDocumentsByID(context.Background(), append(ids, "missing"))
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	require.Equal(t, "kyoto", docs["city"].PageContent)
+
+	require.NoError(t, s.DeleteDocumentsByID(context.Background(), ids[:1]))
+	require.ErrorIs(t, s.DeleteDocuments(context.Background()), vectorstores.ErrDeleteWithoutFilters)
+	require.NoError(t, s.DeleteDocuments(context.Background(),
+		vectorstores.WithFilters(filter.Eq("country", "peru"))))
+
+	docs, err = s.GetDocumentsByID(context.Background(), ids)
+	require.NoError(t, err)
+	require.Empty(t, docs)
+}
+
+func TestOpenSearchStoreSearchRequest(t *testing.T) {
+	t.Parallel()
+
+	var request map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/langchaingo_ns/_search", r.URL.Path)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		fmt.Fprint(w, `{"hits": {"hits": [
+			{"_id": "1", "_score": 0.9, "_source": {"text": "tokyo japan", "metadata": {"country": "japan"}}},
+			{"_id": "2", "_score": 0.6, "_source": {"text": "paris france", "metadata": {"country": "france"}}}
+		]}}`)
+	}))
+	defer server.Close()
+
+	s, err := opensearch.New(opensearch.WithURL(server.URL+"/"), opensearch.WithEmbedder(vectorstoretest.WordEmbedder{}))
+	require.NoError(t, err)
+
+	docs, err := s.SimilaritySearch(context.Background(), "tokyo", 2,
+		vectorstores.WithNameSpace("ns"),
+		vectorstores.WithScoreThreshold(0.5),
+		vectorstores.WithFilters(filter.Eq("country", "japan")))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Equal(t, "tokyo japan", docs[0].PageContent)
+	require.InDelta(t, 0.8, docs[0].Score, 1e-6)
+
+	knn := request["query"].(map[string]any)["knn"].(map[string]any)["embedding"].(map[string]any) //nolint:forcetypeassert
+	require.Equal(t, float64(2), knn["k"])
+	require.Equal(t, map[string]any{"term": map[string]any{"metadata.country": "japan"}}, knn["filter"])
+}
+
+func TestOpenSearchStoreCreateIndexRequest(t *testing.T) {
+	t.Parallel()
+
+	var mappings map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodHead:
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodPut && r.URL.Path == "/langchaingo":
+			var request map[string]any
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+			mappings, _ = request["mappings"].(map[string]any)
+			fmt.Fprint(w, `{"acknowledged": true}`)
+		default:
+			fmt.Fprint(w, `{"errors": false, "items": []}`)
+		}
+	}))
+	defer server.Close()
+
+	s, err := opensearch.New(opensearch.WithURL(server.URL), opensearch.WithEmbedder(vectorstoretest.WordEmbedder{}))
+	require.NoError(t, err)
+	err = s.AddDocuments(context.Background(), []schema.Document{
+		{PageContent: "tokyo", Metadata: map[string]any{"population": 14}},
+	})
+	require.NoError(t, err)
+
+	require.Contains(t, mappings["dynamic_templates"], map[string]any{
+		"metadata_numbers": map[string]any{
+			"path_match":         "metadata.*",
+			"match_mapping_type": "long",
+			"mapping":            map[string]any{"type": "double"},
+		},
+	})
+}
+
+func TestNewWithInvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	_, err := opensearch.New(opensearch.WithURL("http://localhost:9200"))
+	require.ErrorIs(t, err, opensearch.ErrInvalidOptions)
+
+	_, err = opensearch.New(
+		opensearch.WithURL("http://localhost:9200"),
+		opensearch.WithEmbedder(vectorstoretest.WordEmbedder{}),
+		opensearch.WithIndexName("Documents"))
+	require.ErrorIs(t, err, opensearch.ErrInvalidOptions)
+
+	_, err = opensearch.New(
+		opensearch.WithURL("http://localhost:9200"),
+		opensearch.WithEmbedder(vectorstoretest.WordEmbedder{}),
+		opensearch.WithSpaceType("hamming"))
+	require.ErrorIs(t, err, opensearch.ErrInvalidOptions)
+
+	_, err = opensearch.New(
+		opensearch.WithURL("http://localhost:9200"),
+		opensearch.WithEmbedder(vectorstoretest.WordEmbedder{}),
+		opensearch.WithIndexName("_documents"))
+	require.ErrorIs(t, err, opensearch.ErrInvalidOptions)
+}
+
+func TestInvalidNameSpace(t *testing.T) {
+	t.Parallel()
+
+	s, err := opensearch.New(
+		opensearch.WithURL("http://localhost:9200"),
+		opensearch.WithEmbedder(vectorstoretest.WordEmbedder{}))
+	require.NoError(t, err)
+
+	for _, nameSpace := range []string{"Users", "a/b", "a b"} {
+		_, err = s.SimilaritySearch(context.Background(), "tokyo", 1, vectorstores.WithNameSpace(nameSpace))
+		require.ErrorIs(t, err, opensearch.ErrInvalidNameSpace)
+		err = s.RemoveIndex(context.Background(), vectorstores.WithNameSpace(nameSpace))
+		require.ErrorIs(t, err, opensearch.ErrInvalidNameSpace)
+	}
+}
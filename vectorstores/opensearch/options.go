@@ -0,0 +1,164 @@
+package opensearch
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/tmc/langchaingo/embeddings"
+)
+
+const (
+	_opensearchURLEnvVarName = "OPENSEARCH_URL"
+	_defaultIndexName        = "langchaingo"
+	_maxIndexNameLength      = 255
+)
+
+// ErrInvalidOptions is returned when the options given are invalid.
+var ErrInvalidOptions = errors.New("invalid options")
+
+// SpaceType is the space, or distance, used by the k-NN plugin to compare vectors.
+type SpaceType string
+
+const (
+	// SpaceTypeCosine is the cosine similarity.
+	SpaceTypeCosine SpaceType = "cosinesimil"
+	// SpaceTypeL2 is the Euclidean distance.
+	SpaceTypeL2 SpaceType = "l2"
+	// SpaceTypeInnerProduct is the inner product.
+	SpaceTypeInnerProduct SpaceType = "innerproduct"
+)
+
+// Engine is the library used by the k-NN plugin to build and search the index.
+type Engine string
+
+const (
+	// EngineLucene is the Lucene engine. It supports filtering during the search.
+	EngineLucene Engine = "lucene"
+	// EngineFaiss is the Faiss engine. It supports filtering during the search since
+	// OpenSearch 2.9.
+	EngineFaiss Engine = "faiss"
+	// EngineNmslib is the NMSLIB engine. It does not support filters.
+	EngineNmslib Engine = "nmslib"
+)
+
+// Option is a function type that can be used to modify the client.
+type Option func(s *Store)
+
+// WithEmbedder is an option for setting the embedder to use. Must be set.
+func WithEmbedder(e embeddings.Embedder) Option {
+	return func(s *Store) {
+		s.embedder = e
+	}
+}
+
+// WithURL is an option for setting the url of the OpenSearch cluster, e.g.
+// https://localhost:9200. If the option is not set the url is read from the
+// OPENSEARCH_URL environment variable. If the variable is not present, an error will be
+// returned.
+func WithURL(url string) Option {
+	return func(s *Store) {
+		s.url = url
+	}
+}
+
+// WithBasicAuth is an option for authenticating to the cluster with a username and a
+// password.
+func WithBasicAuth(username, password string) Option {
+	return func(s *Store) {
+		s.username = username
+		s.password = password
+	}
+}
+
+// WithHTTPClient is an option for setting the http client used to call the cluster, e.g.
+// to configure TLS. Defaults to http.DefaultClient.
+func WithHTTPClient(client *http.Client) Option {
+	return func(s *Store) {
+		s.client = client
+	}
+}
+
+// WithIndexName is an option for setting the name of the index the documents are stored
+// in. The documents of a name space are stored in the index named after the index name
+// and the name space, joined with an underscore. Defaults to "langchaingo".
+func WithIndexName(name string) Option {
+	return func(s *Store) {
+		s.indexName = name
+	}
+}
+
+// WithSpaceType is an option for setting the space used to compare vectors when creating
+// an index. Defaults to SpaceTypeCosine.
+func WithSpaceType(spaceType SpaceType) Option {
+	return func(s *Store) {
+		s.spaceType = spaceType
+	}
+}
+
+// WithEngine is an option for setting the engine of the indexes created. Defaults to
+// EngineLucene.
+func WithEngine(engine Engine) Option {
+	return func(s *Store) {
+		s.engine = engine
+	}
+}
+
+func applyClientOptions(opts ...Option) (Store, error) {
+	s := Store{
+		client:    http.DefaultClient,
+		indexName: _defaultIndexName,
+		spaceType: SpaceTypeCosine,
+		engine:    EngineLucene,
+	}
+
+	for _, opt := range opts {
+		opt(&s)
+	}
+
+	if s.embedder == nil {
+		return Store{}, fmt.Errorf("%w: missing embedder", ErrInvalidOptions)
+	}
+
+	if s.url == "" {
+		s.url = os.Getenv(_opensearchURLEnvVarName)
+		if s.url == "" {
+			return Store{}, fmt.Errorf(
+				"%w: missing url. Pass it as an option or set the %s environment variable",
+				ErrInvalidOptions,
+				_opensearchURLEnvVarName,
+			)
+		}
+	}
+	s.url = strings.TrimSuffix(s.url, "/")
+
+	if !validIndexName(s.indexName) {
+		return Store{}, fmt.Errorf("%w: invalid index name %q", ErrInvalidOptions, s.indexName)
+	}
+
+	switch s.spaceType {
+	case SpaceTypeCosine, SpaceTypeL2, SpaceTypeInnerProduct:
+	default:
+		return Store{}, fmt.Errorf("%w: unknown space type %q", ErrInvalidOptions, s.spaceType)
+	}
+	switch s.engine {
+	case EngineLucene, EngineFaiss, EngineNmslib:
+	default:
+		return Store{}, fmt.Errorf("%w: unknown engine %q", ErrInvalidOptions, s.engine)
+	}
+
+	return s, nil
+}
+
+// validIndexName reports whether the name follows the OpenSearch rules for index names:
+// lowercase, at most 255 bytes, not starting with '-', '_' or '+' and without the
+// characters `\/*?"<>| ,#:`.
+func validIndexName(name string) bool {
+	return name != "" && name != "." && name != ".." &&
+		len(name) <= _maxIndexNameLength &&
+		name == strings.ToLower(name) &&
+		!strings.ContainsAny(name[:1], "-_+") &&
+		!strings.ContainsAny(name, "\\/*?\"<>| ,#:")
+}
@@ -0,0 +1,301 @@
+package opensearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// APIError is an error type returned if the status code from the rest
+// api is not a success.
+type APIError struct {
+	Task    string
+	Message string
+}
+
+func newAPIError(task string, body io.Reader) APIError {
+	buf := new(bytes.Buffer)
+	_, err := io.Copy(buf, body)
+	if err != nil {
+		return APIError{Task: "reading body of error message", Message: err.Error()}
+	}
+
+	return APIError{Task: task, Message: buf.String()}
+}
+
+func (e APIError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Task, e.Message)
+}
+
+// source is the source of an OpenSearch document.
+type source struct {
+	Text      string         `json:"text"`
+	Metadata  map[string]any `json:"metadata"`
+	Embedding []float32      `json:"embedding,omitempty"`
+}
+
+// restIndexExists reports whether the index exists.
+func (s Store) restIndexExists(ctx context.Context, index string) (bool, error) {
+	response, err := s.doRequest(ctx, http.MethodHead, "/"+url.PathEscape(index), nil)
+	if err != nil {
+		return false, err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, newAPIError("checking index", response.Body)
+	}
+}
+
+// restCreateIndex creates a k-NN index for embeddings of the given dimensions. Metadata
+// strings are mapped as keywords, so they can be filtered on exactly.
+func (s Store) restCreateIndex(ctx context.Context, index string, dimensions int) error {
+	payload := map[string]any{
+		"settings": map[string]any{"index": map[string]any{"knn": true}},
+		"mappings": map[string]any{
+			"dynamic_templates": []map[string]any{{
+				"metadata_strings": map[string]any{
+					"path_match":         _metadataField + ".*",
+					"match_mapping_type": "string",
+					"mapping":            map[string]any{"type": "keyword"},
+				},
+			}, {
+				// Integers are mapped as doubles, so fractional values of the same key, added
+				// later, are not truncated.
+				"metadata_numbers": map[string]any{
+					"path_match":         _metadataField + ".*",
+					"match_mapping_type": "long",
+					"mapping":            map[string]any{"type": "double"},
+				},
+			}},
+			"properties": map[string]any{
+				_textField: map[string]any{"type": "text"},
+				_embeddingField: map[string]any{
+					"type":      "knn_vector",
+					"dimension": dimensions,
+					"method": map[string]any{
+						"name":       "hnsw",
+						"space_type": s.spaceType,
+						"engine":     s.engine,
+					},
+				},
+			},
+		},
+	}
+
+	return s.doJSONRequest(ctx, http.MethodPut, "/"+url.PathEscape(index), payload, "creating index", nil)
+}
+
+// restDeleteIndex deletes the index, if it exists.
+func (s Store) restDeleteIndex(ctx context.Context, index string) error {
+	response, err := s.doRequest(ctx, http.MethodDelete, "/"+url.PathEscape(index), nil)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusOK || response.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	return newAPIError("deleting index", response.Body)
+}
+
+type bulkItemResult struct {
+	Error any `json:"error"`
+}
+
+type bulkResponse struct {
+	Errors bool                        `json:"errors"`
+	Items  []map[string]bulkItemResult `json:"items"`
+}
+
+// restBulkIndex indexes the documents under the ids, replacing the documents with the
+// same ids. The index is refreshed so the documents can be searched on return.
+func (s Store) restBulkIndex(ctx context.Context, index string, ids []string, sources []source) error {
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for i, id := range ids {
+		action := map[string]any{"index": map[string]any{"_index": index, "_id": id}}
+		if err := encoder.Encode(action); err != nil {
+			return err
+		}
+		if err := encoder.Encode(sources[i]); err != nil {
+			return err
+		}
+	}
+
+	response, err := s.doRequest(ctx, http.MethodPost, "/_bulk?refresh=wait_for", &body)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return newAPIError("indexing documents", response.Body)
+	}
+
+	var bulk bulkResponse
+	if err := json.NewDecoder(response.Body).Decode(&bulk); err != nil {
+		return err
+	}
+	if !bulk.Errors {
+		return nil
+	}
+	for _, item := range bulk.Items {
+		for _, result := range item {
+			if result.Error != nil {
+				message, _ := json.Marshal(result.Error)
+				return APIError{Task: "indexing documents", Message: string(message)}
+			}
+		}
+	}
+	return APIError{Task: "indexing documents", Message: "unknown error"}
+}
+
+type searchResponse struct {
+	Hits struct {
+		Hits []struct {
+			ID     string  `json:"_id"`
+			Score  float32 `json:"_score"`
+			Source source  `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+// restSearch returns the numDocuments documents nearest to the vector matching the
+// filter, with their score and, if withEmbeddings is true, their embedding.
+func (s Store) restSearch(
+	ctx context.Context,
+	index string,
+	vector []float32,
+	numDocuments int,
+	filter any,
+	withEmbeddings bool,
+) ([]schema.Document, [][]float32, error) {
+	knn := map[string]any{"vector": vector, "k": numDocuments}
+	if filter != nil {
+		knn["filter"] = filter
+	}
+	payload := map[string]any{
+		"size":  numDocuments,
+		"query": map[string]any{"knn": map[string]any{_embeddingField: knn}},
+	}
+	if !withEmbeddings {
+		payload["_source"] = map[string]any{"excludes": []string{_embeddingField}}
+	}
+
+	var response searchResponse
+	err := s.doJSONRequest(ctx, http.MethodPost, "/"+url.PathEscape(index)+"/_search", payload,
+		"searching documents", &response)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	docs := make([]schema.Document, 0, len(response.Hits.Hits))
+	vectors := make([][]float32, 0, len(response.Hits.Hits))
+	for _, hit := range response.Hits.Hits {
+		docs = append(docs, schema.Document{
+			PageContent: hit.Source.Text,
+			Metadata:    hit.Source.Metadata,
+			Score:       s.relevance(hit.Score),
+		})
+		vectors = append(vectors, hit.Source.Embedding)
+	}
+	return docs, vectors, nil
+}
+
+// restDeleteByQuery deletes the documents matching the query. Deleting from an index that
+// does not exist does nothing.
+func (s Store) restDeleteByQuery(ctx context.Context, index string, query any) error {
+	payload, err := json.Marshal(map[string]any{"query": query})
+	if err != nil {
+		return err
+	}
+
+	response, err := s.doRequest(ctx, http.MethodPost,
+		"/"+url.PathEscape(index)+"/_delete_by_query?refresh=true", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusOK || response.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	return newAPIError("deleting documents", response.Body)
+}
+
+type mgetResponse struct {
+	Docs []struct {
+		ID     string `json:"_id"`
+		Found  bool   `json:"found"`
+		Source source `json:"_source"`
+	} `json:"docs"`
+}
+
+// restGetDocuments returns the documents with the given ids.
+func (s Store) restGetDocuments(ctx context.Context, index string, ids []string) (map[string]schema.Document, error) {
+	var response mgetResponse
+	err := s.doJSONRequest(ctx, http.MethodPost,
+		"/"+url.PathEscape(index)+"/_mget?_source_excludes="+_embeddingField,
+		map[string]any{"ids": ids}, "getting documents", &response)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make(map[string]schema.Document, len(response.Docs))
+	for _, doc := range response.Docs {
+		if doc.Found {
+			docs[doc.ID] = schema.Document{PageContent: doc.Source.Text, Metadata: doc.Source.Metadata}
+		}
+	}
+	return docs, nil
+}
+
+// doJSONRequest sends the payload encoded as JSON and decodes the response in result, if
+// it is not nil. Responses with a status code other than 200 are returned as an APIError.
+func (s Store) doJSONRequest(ctx context.Context, method, path string, payload any, task string, result any) error {
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	response, err := s.doRequest(ctx, method, path, bytes.NewReader(payloadBytes))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return newAPIError(task, response.Body)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(response.Body).Decode(result)
+}
+
+func (s Store) doRequest(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, s.url+path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if s.username != "" || s.password != "" {
+		req.SetBasicAuth(s.username, s.password)
+	}
+
+	return s.client.Do(req)
+}
@@ -11,6 +11,8 @@ type Options struct {
 	ScoreThreshold float32
 	Filters        any
 	Embedder       embeddings.Embedder
+	MMR            *MMROptions
+	DeleteAll      bool
 }
 
 // WithNameSpace returns an Option for setting the name space.
@@ -20,6 +22,9 @@ func WithNameSpace(nameSpace string) Option {
 	}
 }
 
+// WithScoreThreshold returns an Option for returning only the documents whose relevance score,
+// between 0 and 1, is at least scoreThreshold. See the package documentation for the meaning
+// of scores. A threshold of 0 returns all documents.
 func WithScoreThreshold(scoreThreshold float32) Option {
 	return func(o *Options) {
 		o.ScoreThreshold = scoreThreshold
@@ -30,6 +35,9 @@ func WithScoreThreshold(scoreThreshold float32) Option {
 // filters retrieve exactly the number of nearest-neighbors results that match the filters. In
 // most cases the search latency will be lower than unfiltered searches
 // See https://docs.pinecone.io/docs/metadata-filtering
+//
+// The filters are either a filter.Filter, which every vector store of langchaingo translates
+// to its own syntax, or a filter in the native syntax of the vector store.
 func WithFilters(filters any) Option {
 	return func(o *Options) {
 		o.Filters = filters
@@ -44,3 +52,11 @@ func WithEmbedder(embedder embeddings.Embedder) Option {
 		o.Embedder = embedder
 	}
 }
+
+// WithDeleteAll returns an Option allowing FilterDeleter.DeleteDocuments to delete all the
+// documents of the name space when no filters are set.
+func WithDeleteAll() Option {
+	return func(o *Options) {
+		o.DeleteAll = true
+	}
+}
@@ -0,0 +1,5 @@
+// Package pgvector contains an implementation of the vectorStore interface using
+// PostgreSQL with the pgvector extension. Each collection is a table storing the text,
+// the metadata, as JSONB, and the embedding of the documents, with a column for the name
+// space of each document.
+package pgvector
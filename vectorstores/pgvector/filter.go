@@ -0,0 +1,130 @@
+package pgvector
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/vectorstores/filter"
+)
+
+// whereFromFilter translates a filter.Filter to an SQL condition on the metadata column,
+// appending its parameters to args. Values are compared as JSONB, so numbers of any type
+// compare numerically, and range comparisons only match values of the same JSON type.
+// Conditions never evaluate to NULL, so negations match documents without the key.
+func whereFromFilter(filters any, args *[]any) (string, error) {
+	f, ok := filters.(filter.Filter)
+	if !ok {
+		return "", fmt.Errorf("%w: %T", ErrInvalidFilter, filters)
+	}
+	return translateFilter(f, args)
+}
+
+func translateFilter(f filter.Filter, args *[]any) (string, error) {
+	switch f := f.(type) {
+	case filter.Comparison:
+		return translateComparison(f, args)
+	case filter.Logical:
+		if len(f.Filters) == 0 {
+			if f.Operator == filter.OpOr {
+				return "FALSE", nil
+			}
+			return "TRUE", nil
+		}
+		conditions := make([]string, 0, len(f.Filters))
+		for _, sub := range f.Filters {
+			condition, err := translateFilter(sub, args)
+			if err != nil {
+				return "", err
+			}
+			conditions = append(conditions, condition)
+		}
+		return "(" + strings.Join(conditions, " "+strings.ToUpper(string(f.Operator))+" ") + ")", nil
+	case filter.Negation:
+		condition, err := translateFilter(f.Filter, args)
+		if err != nil {
+			return "", err
+		}
+		return "NOT " + condition, nil
+	default:
+		return "", fmt.Errorf("%w: %T", filter.ErrUnsupportedFilter, f)
+	}
+}
+
+// _comparisonOperators are the SQL operators of the comparisons on a single value.
+var _comparisonOperators = map[filter.Operator]string{ //nolint:gochecknoglobals
+	filter.OpEq:  "=",
+	filter.OpGt:  ">",
+	filter.OpGte: ">=",
+	filter.OpLt:  "<",
+	filter.OpLte: "<=",
+}
+
+func translateComparison(c filter.Comparison, args *[]any) (string, error) {
+	if c.Operator == filter.OpIn || c.Operator == filter.OpNin {
+		return translateInComparison(c, args)
+	}
+
+	value := metadataValue(args, c.Key)
+	jsonValue, err := addJSONArg(args, c.Value)
+	if err != nil {
+		return "", err
+	}
+	if c.Operator == filter.OpNe {
+		return fmt.Sprintf("(%s IS DISTINCT FROM %s)", value, jsonValue), nil
+	}
+
+	operator, ok := _comparisonOperators[c.Operator]
+	if !ok {
+		return "", fmt.Errorf("%w: operator %q", filter.ErrUnsupportedFilter, c.Operator)
+	}
+	return fmt.Sprintf("COALESCE(jsonb_typeof(%[1]s) = jsonb_typeof(%[2]s) AND %[1]s %[3]s %[2]s, FALSE)",
+		value, jsonValue, operator), nil
+}
+
+func translateInComparison(c filter.Comparison, args *[]any) (string, error) {
+	values, err := c.Values()
+	if err != nil {
+		return "", err
+	}
+
+	condition := "FALSE"
+	if len(values) > 0 {
+		value := metadataValue(args, c.Key)
+		jsonValues := make([]string, 0, len(values))
+		for _, v := range values {
+			jsonValue, err := addJSONArg(args, v)
+			if err != nil {
+				return "", err
+			}
+			jsonValues = append(jsonValues, jsonValue)
+		}
+		condition = fmt.Sprintf("COALESCE(%s IN (%s), FALSE)", value, strings.Join(jsonValues, ", "))
+	}
+
+	if c.Operator == filter.OpNin {
+		condition = "NOT " + condition
+	}
+	return condition, nil
+}
+
+// metadataValue appends the key to args and returns the expression of its metadata value.
+func metadataValue(args *[]any, key string) string {
+	return fmt.Sprintf("metadata -> %s::text", addArg(args, key))
+}
+
+// addArg appends a parameter to args and returns its placeholder.
+func addArg(args *[]any, value any) string {
+	*args = append(*args, value)
+	return fmt.Sprintf("$%d", len(*args))
+}
+
+// addJSONArg appends a value encoded as JSON to args and returns its placeholder cast to
+// JSONB.
+func addJSONArg(args *[]any, value any) (string, error) {
+	jsonValue, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return addArg(args, string(jsonValue)) + "::jsonb", nil
+}
@@ -0,0 +1,77 @@
+package pgvector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores/filter"
+)
+
+func TestWhereFromFilter(t *testing.T) {
+	t.Parallel()
+
+	var args []any
+	where, err := whereFromFilter(filter.And(
+		filter.Eq("country", "japan"),
+		filter.Not(filter.In("city", "tokyo", "osaka")),
+		filter.Ne("capital", true),
+	), &args)
+	require.NoError(t, err)
+	assert.Equal(t, "("+
+		"COALESCE(jsonb_typeof(metadata -> $1::text) = jsonb_typeof($2::jsonb) "+
+		"AND metadata -> $1::text = $2::jsonb, FALSE) AND "+
+		"NOT COALESCE(metadata -> $3::text IN ($4::jsonb, $5::jsonb), FALSE) AND "+
+		"(metadata -> $6::text IS DISTINCT FROM $7::jsonb))", where)
+	assert.Equal(t, []any{"country", `"japan"`, "city", `"tokyo"`, `"osaka"`, "capital", "true"}, args)
+
+	args = []any{"[1,2]"}
+	where, err = whereFromFilter(filter.Or(filter.Gte("population", 1000), filter.Nin("city")), &args)
+	require.NoError(t, err)
+	assert.Equal(t, "("+
+		"COALESCE(jsonb_typeof(metadata -> $2::text) = jsonb_typeof($3::jsonb) "+
+		"AND metadata -> $2::text >= $3::jsonb, FALSE) OR NOT FALSE)", where)
+	assert.Equal(t, []any{"[1,2]", "population", "1000"}, args)
+
+	_, err = whereFromFilter(map[int]string{}, &args)
+	assert.ErrorIs(t, err, ErrInvalidFilter)
+}
+
+func TestWhereClause(t *testing.T) {
+	t.Parallel()
+
+	var args []any
+	where, err := Store{}.whereClause(map[string]any{"country": "japan"}, &args)
+	require.NoError(t, err)
+	assert.Equal(t, "metadata @> $1::jsonb", where)
+	assert.Equal(t, []any{`{"country":"japan"}`}, args)
+
+	where, err = Store{}.whereClause(nil, &args)
+	require.NoError(t, err)
+	assert.Equal(t, "TRUE", where)
+}
+
+func TestVectorEncoding(t *testing.T) {
+	t.Parallel()
+
+	vector := []float32{1, -2.5, 0.125}
+	assert.Equal(t, "[1,-2.5,0.125]", encodeVector(vector))
+
+	decoded, err := decodeVector("[1,-2.5,0.125]")
+	require.NoError(t, err)
+	assert.Equal(t, vector, decoded)
+
+	_, err = decodeVector("1,2")
+	assert.ErrorIs(t, err, ErrInvalidVector)
+}
+
+func TestLastByID(t *testing.T) {
+	t.Parallel()
+
+	ids, docs := lastByID([]string{"a", "b", "a"}, []schema.Document{
+		{PageContent: "a1"}, {PageContent: "b"}, {PageContent: "a2"},
+	})
+	assert.Equal(t, []string{"b", "a"}, ids)
+	assert.Equal(t, []schema.Document{{PageContent: "b"}, {PageContent: "a2"}}, docs)
+}
@@ -0,0 +1,548 @@
+package pgvector
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	_ "github.com/jackc/pgx/v5/stdlib" // postgresql driver
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// _driverName is the name of the pgx database/sql driver.
+const _driverName = "pgx"
+
+var (
+	// ErrEmbedderWrongNumberVectors is returned when if the embedder returns a number
+	// of vectors that is not equal to the number of documents given.
+	ErrEmbedderWrongNumberVectors = errors.New(
+		"number of vectors from embedder does not match number of documents",
+	)
+	// ErrInvalidScoreThreshold is returned when the score threshold is not between 0 and 1.
+	ErrInvalidScoreThreshold = errors.New("score threshold must be between 0 and 1")
+	// ErrInvalidFilter is returned when the filters are neither a filter.Filter nor a
+	// map[string]any.
+	ErrInvalidFilter = errors.New("invalid filter")
+	// ErrInvalidVector is returned when a vector read from the database can not be parsed.
+	ErrInvalidVector = errors.New("invalid vector")
+)
+
+//nolint:gochecknoglobals
+var (
+	_distanceOperators = map[Distance]string{
+		DistanceCosine:       "<=>",
+		DistanceL2:           "<->",
+		DistanceInnerProduct: "<#>",
+	}
+	_distanceOperatorClasses = map[Distance]string{
+		DistanceCosine:       "vector_cosine_ops",
+		DistanceL2:           "vector_l2_ops",
+		DistanceInnerProduct: "vector_ip_ops",
+	}
+)
+
+// Store is a vector store storing the documents of a collection in a PostgreSQL table
+// with the pgvector extension.
+type Store struct {
+	embedder embeddings.Embedder
+	db       *sql.DB
+	ownsDB   bool
+
+	connectionURL   string
+	collectionName  string
+	nameSpace       string
+	createExtension bool
+	distance        Distance
+	dimensions      int
+	indexType       IndexType
+	indexParameters map[string]int
+	batchSize       int
+}
+
+var (
+	_ vectorstores.VectorStore   = Store{}
+	_ vectorstores.Upserter      = Store{}
+	_ vectorstores.IDDeleter     = Store{}
+	_ vectorstores.FilterDeleter = Store{}
+	_ vectorstores.Getter        = Store{}
+)
+
+// New creates a new Store with options. The pgvector extension, unless disabled with
+// WithCreateExtension, the table of the collection and its indexes are created if they do
+// not exist. An IVFFlat index is not created, see CreateIndex. Options for the embedder and
+// the connection url or database must be set.
+func New(ctx context.Context, opts ...Option) (Store, error) {
+	s, err := applyClientOptions(opts...)
+	if err != nil {
+		return Store{}, err
+	}
+
+	if s.db == nil {
+		s.db, err = sql.Open(_driverName, s.connectionURL)
+		if err != nil {
+			return Store{}, err
+		}
+		s.ownsDB = true
+	}
+
+	if err := s.init(ctx); err != nil {
+		if s.ownsDB {
+			s.db.Close()
+		}
+		return Store{}, err
+	}
+
+	return s, nil
+}
+
+// init creates the extension, the table of the collection and its indexes, except an
+// IVFFlat index.
+func (s Store) init(ctx context.Context) error {
+	embeddingType := "vector"
+	if s.dimensions > 0 {
+		embeddingType = fmt.Sprintf("vector(%d)", s.dimensions)
+	}
+
+	var statements []string
+	if s.createExtension {
+		statements = append(statements, "CREATE EXTENSION IF NOT EXISTS vector")
+	}
+	statements = append(statements,
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			namespace TEXT NOT NULL DEFAULT '',
+			id TEXT NOT NULL,
+			document TEXT NOT NULL,
+			metadata JSONB NOT NULL DEFAULT '{}',
+			embedding %s NOT NULL,
+			PRIMARY KEY (namespace, id)
+		)`, s.table(), embeddingType),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s USING gin (metadata)",
+			s.identifier("metadata_idx"), s.table()),
+	)
+	if s.indexType == IndexHNSW {
+		statements = append(statements, s.createIndexStatement())
+	}
+
+	for _, statement := range statements {
+		if _, err := s.db.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateIndex creates the approximate nearest neighbor index set with WithHNSWIndex or
+// WithIVFFlatIndex, if it does not exist. An IVFFlat index computes its lists from the rows
+// of the collection, so it is not created by New and CreateIndex should be called once the
+// collection holds data. An HNSW index is already created by New.
+func (s Store) CreateIndex(ctx context.Context) error {
+	if s.indexType == IndexNone {
+		return fmt.Errorf("%w: no index set", ErrInvalidOptions)
+	}
+	_, err := s.db.ExecContext(ctx, s.createIndexStatement())
+	return err
+}
+
+// createIndexStatement returns the statement creating the approximate nearest neighbor
+// index of the embeddings.
+func (s Store) createIndexStatement() string {
+	parameters := make([]string, 0, len(s.indexParameters))
+	for _, name := range []string{"m", "ef_construction", "lists"} {
+		if value := s.indexParameters[name]; value > 0 {
+			parameters = append(parameters, fmt.Sprintf("%s = %d", name, value))
+		}
+	}
+
+	statement := fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s USING %s (embedding %s)",
+		s.identifier(fmt.Sprintf("embedding_%s_idx", s.indexType)), s.table(), s.indexType,
+		_distanceOperatorClasses[s.distance])
+	if len(parameters) > 0 {
+		statement += fmt.Sprintf(" WITH (%s)", strings.Join(parameters, ", "))
+	}
+	return statement
+}
+
+// AddDocuments creates vector embeddings from the documents using the embedder and
+// inserts them in the collection under random ids.
+func (s Store) AddDocuments(ctx context.Context, docs []schema.Document, options ...vectorstores.Option) error {
+	ids := make([]string, 0, len(docs))
+	for range docs {
+		ids = append(ids, uuid.New().String())
+	}
+	return s.insertDocuments(ctx, ids, docs, false, options...)
+}
+
+// UpsertDocuments creates vector embeddings from the documents using the embedder and
+// stores them under the given ids, replacing the documents with the same ids. If an id
+// is given more than once, its last document is stored.
+func (s Store) UpsertDocuments(
+	ctx context.Context, ids []string, docs []schema.Document, options ...vectorstores.Option,
+) error {
+	if err := vectorstores.CheckIDs(ids, docs); err != nil {
+		return err
+	}
+	ids, docs = lastByID(ids, docs)
+	return s.insertDocuments(ctx, ids, docs, true, options...)
+}
+
+// lastByID returns the ids and documents without the documents whose id is given again
+// later, since a statement can not update a row twice.
+func lastByID(ids []string, docs []schema.Document) ([]string, []schema.Document) {
+	last := make(map[string]int, len(ids))
+	for i, id := range ids {
+		last[id] = i
+	}
+	if len(last) == len(ids) {
+		return ids, docs
+	}
+
+	uniqueIDs := make([]string, 0, len(last))
+	uniqueDocs := make([]schema.Document, 0, len(last))
+	for i, id := range ids {
+		if last[id] == i {
+			uniqueIDs = append(uniqueIDs, id)
+			uniqueDocs = append(uniqueDocs, docs[i])
+		}
+	}
+	return uniqueIDs, uniqueDocs
+}
+
+func (s Store) insertDocuments(
+	ctx context.Context, ids []string, docs []schema.Document, upsert bool, options ...vectorstores.Option,
+) error {
+	opts := s.getOptions(options...)
+
+	texts := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		texts = append(texts, doc.PageContent)
+	}
+	vectors, err := s.getEmbedder(opts).EmbedDocuments(ctx, texts)
+	if err != nil {
+		return err
+	}
+	if len(vectors) != len(docs) {
+		return ErrEmbedderWrongNumberVectors
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	for start := 0; start < len(docs); start += s.batchSize {
+		end := start + s.batchSize
+		if end > len(docs) {
+			end = len(docs)
+		}
+		err := s.insertBatch(ctx, tx, s.getNameSpace(opts), ids[start:end], docs[start:end], vectors[start:end], upsert)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// insertBatch inserts documents in the name space with a single statement.
+func (s Store) insertBatch(
+	ctx context.Context, tx *sql.Tx, nameSpace string, ids []string, docs []schema.Document, vectors [][]float32,
+	upsert bool,
+) error {
+	values := make([]string, 0, len(docs))
+	args := make([]any, 0, 5*len(docs)) //nolint:gomnd
+	for i, doc := range docs {
+		metadata, err := json.Marshal(doc.Metadata)
+		if err != nil {
+			return err
+		}
+		if doc.Metadata == nil {
+			metadata = []byte("{}")
+		}
+
+		n := len(args)
+		values = append(values, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d::vector)", n+1, n+2, n+3, n+4, n+5))
+		args = append(args, nameSpace, ids[i], doc.PageContent, string(metadata), encodeVector(vectors[i]))
+	}
+
+	statement := fmt.Sprintf("INSERT INTO %s (namespace, id, document, metadata, embedding) VALUES %s",
+		s.table(), strings.Join(values, ", "))
+	if upsert {
+		statement += ` ON CONFLICT (namespace, id) DO UPDATE SET document = EXCLUDED.document,
+			metadata = EXCLUDED.metadata, embedding = EXCLUDED.embedding`
+	}
+
+	_, err := tx.ExecContext(ctx, statement, args...)
+	return err
+}
+
+// SimilaritySearch creates a vector embedding from the query using the embedder and
+// returns the documents of the collection closest to it, with their relevance score.
+// Filters are a filter.Filter or a map[string]any matched against the metadata with
+// the JSONB containment operator.
+func (s Store) SimilaritySearch(
+	ctx context.Context, query string, numDocuments int, options ...vectorstores.Option,
+) ([]schema.Document, error) {
+	opts := s.getOptions(options...)
+	if opts.ScoreThreshold < 0 || opts.ScoreThreshold > 1 {
+		return nil, ErrInvalidScoreThreshold
+	}
+
+	vector, err := s.getEmbedder(opts).EmbedQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := numDocuments
+	if opts.MMR != nil {
+		if err := opts.MMR.Validate(); err != nil {
+			return nil, err
+		}
+		limit = opts.MMR.GetFetchK(numDocuments)
+	}
+
+	docs, vectors, err := s.query(ctx, vector, limit, opts)
+	if err != nil || opts.MMR == nil {
+		return docs, err
+	}
+	return vectorstores.MaximalMarginalRelevanceDocuments(vector, docs, vectors, opts.MMR.Lambda, numDocuments), nil
+}
+
+// query returns the documents closest to the vector matching the filters, with their
+// embeddings if they are needed for maximal marginal relevance.
+func (s Store) query(
+	ctx context.Context, vector []float32, limit int, opts vectorstores.Options,
+) ([]schema.Document, [][]float32, error) {
+	args := []any{encodeVector(vector), s.getNameSpace(opts)}
+	where, err := s.whereClause(opts.Filters, &args)
+	if err != nil {
+		return nil, nil, err
+	}
+	args = append(args, limit)
+
+	embeddingColumn := "''"
+	if opts.MMR != nil {
+		embeddingColumn = "embedding::text"
+	}
+
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`SELECT document, metadata, %[5]s,
+			embedding %[1]s $1::vector AS distance
+		FROM %[2]s WHERE namespace = $2 AND %[3]s ORDER BY distance LIMIT $%[4]d`,
+		_distanceOperators[s.distance], s.table(), where, len(args), embeddingColumn), args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var docs []schema.Document
+	var vectors [][]float32
+	for rows.Next() {
+		var doc schema.Document
+		var metadata []byte
+		var embedding string
+		var distance float32
+		if err := rows.Scan(&doc.PageContent, &metadata, &embedding, &distance); err != nil {
+			return nil, nil, err
+		}
+		if err := json.Unmarshal(metadata, &doc.Metadata); err != nil {
+			return nil, nil, err
+		}
+
+		doc.Score = s.relevance(distance)
+		if doc.Score < opts.ScoreThreshold {
+			continue
+		}
+
+		docs = append(docs, doc)
+		if opts.MMR == nil {
+			continue
+		}
+		docVector, err := decodeVector(embedding)
+		if err != nil {
+			return nil, nil, err
+		}
+		vectors = append(vectors, docVector)
+	}
+
+	return docs, vectors, rows.Err()
+}
+
+// DeleteDocumentsByID deletes the documents of the name space with the given ids.
+func (s Store) DeleteDocumentsByID(ctx context.Context, ids []string, options ...vectorstores.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	nameSpace := s.getNameSpace(s.getOptions(options...))
+	_, err := s.db.ExecContext(ctx,
+		fmt.Sprintf("DELETE FROM %s WHERE namespace = $1 AND id = ANY($2)", s.table()), nameSpace, ids)
+	return err
+}
+
+// DeleteDocuments deletes the documents of the name space matching the filters. All the
+// documents of the name space are deleted if no filters are given and
+// vectorstores.WithDeleteAll is set.
+func (s Store) DeleteDocuments(ctx context.Context, options ...vectorstores.Option) error {
+	opts := s.getOptions(options...)
+	if err := vectorstores.CheckDeleteFilters(opts); err != nil {
+		return err
+	}
+
+	args := []any{s.getNameSpace(opts)}
+	where, err := s.whereClause(opts.Filters, &args)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx,
+		fmt.Sprintf("DELETE FROM %s WHERE namespace = $1 AND %s", s.table(), where), args...)
+	return err
+}
+
+// GetDocumentsByID returns the documents of the name space with the given ids.
+func (s Store) GetDocumentsByID(
+	ctx context.Context, ids []string, options ...vectorstores.Option,
+) (map[string]schema.Document, error) {
+	docs := make(map[string]schema.Document, len(ids))
+	if len(ids) == 0 {
+		return docs, nil
+	}
+
+	nameSpace := s.getNameSpace(s.getOptions(options...))
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(
+		"SELECT id, document, metadata FROM %s WHERE namespace = $1 AND id = ANY($2)", s.table()),
+		nameSpace, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		var doc schema.Document
+		var metadata []byte
+		if err := rows.Scan(&id, &doc.PageContent, &metadata); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(metadata, &doc.Metadata); err != nil {
+			return nil, err
+		}
+		docs[id] = doc
+	}
+
+	return docs, rows.Err()
+}
+
+// RemoveCollection drops the table of the collection.
+func (s Store) RemoveCollection(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, fmt.Sprintf("DROP TABLE IF EXISTS %s", s.table()))
+	return err
+}
+
+// Close closes the database if it was opened by the store.
+func (s Store) Close() error {
+	if !s.ownsDB {
+		return nil
+	}
+	return s.db.Close()
+}
+
+// relevance returns the relevance score of a distance.
+func (s Store) relevance(distance float32) float32 {
+	switch s.distance { //nolint:exhaustive
+	case DistanceL2:
+		return vectorstores.RelevanceFromL2Distance(distance)
+	case DistanceInnerProduct:
+		return vectorstores.RelevanceFromSimilarity(-distance)
+	default:
+		return vectorstores.RelevanceFromCosineDistance(distance)
+	}
+}
+
+// whereClause returns the SQL condition of the filters, appending its parameters to args.
+func (s Store) whereClause(filters any, args *[]any) (string, error) {
+	switch filters := filters.(type) {
+	case nil:
+		return "TRUE", nil
+	case map[string]any:
+		metadata, err := json.Marshal(filters)
+		if err != nil {
+			return "", err
+		}
+		*args = append(*args, string(metadata))
+		return fmt.Sprintf("metadata @> $%d::jsonb", len(*args)), nil
+	default:
+		return whereFromFilter(filters, args)
+	}
+}
+
+// table returns the quoted name of the table of the collection.
+func (s Store) table() string {
+	return pgx.Identifier{s.collectionName}.Sanitize()
+}
+
+// identifier returns a quoted identifier prefixed by the name of the collection.
+func (s Store) identifier(suffix string) string {
+	return pgx.Identifier{s.collectionName + "_" + suffix}.Sanitize()
+}
+
+func (s Store) getOptions(options ...vectorstores.Option) vectorstores.Options {
+	opts := vectorstores.Options{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	return opts
+}
+
+func (s Store) getNameSpace(opts vectorstores.Options) string {
+	if opts.NameSpace != "" {
+		return opts.NameSpace
+	}
+	return s.nameSpace
+}
+
+func (s Store) getEmbedder(opts vectorstores.Options) embeddings.Embedder {
+	if opts.Embedder != nil {
+		return opts.Embedder
+	}
+	return s.embedder
+}
+
+// encodeVector returns the text representation of a vector, such as [1,2.5,3].
+func encodeVector(vector []float32) string {
+	values := make([]string, 0, len(vector))
+	for _, v := range vector {
+		values = append(values, strconv.FormatFloat(float64(v), 'f', -1, 32))
+	}
+	return "[" + strings.Join(values, ",") + "]"
+}
+
+// decodeVector parses the text representation of a vector.
+func decodeVector(text string) ([]float32, error) {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "[") || !strings.HasSuffix(text, "]") {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidVector, text)
+	}
+	text = text[1 : len(text)-1]
+	if text == "" {
+		return []float32{}, nil
+	}
+
+	values := strings.Split(text, ",")
+	vector := make([]float32, 0, len(values))
+	for _, value := range values {
+		v, err := strconv.ParseFloat(strings.TrimSpace(value), 32)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidVector, err)
+		}
+		vector = append(vector, float32(v))
+	}
+	return vector, nil
+}
@@ -5,7 +5,6 @@ import (
 	"crypto/tls"
 	"fmt"
 
-	"github.com/google/uuid"
 	"github.com/pinecone-io/go-pinecone/pinecone_grpc"
 	"github.com/tmc/langchaingo/schema"
 	"google.golang.org/grpc"
@@ -44,6 +43,7 @@ func (s Store) getGRPCConn(ctx context.Context) (*grpc.ClientConn, error) {
 
 func (s Store) grpcUpsert(
 	ctx context.Context,
+	ids []string,
 	vectors [][]float32,
 	metadatas []map[string]any,
 	nameSpace string,
@@ -58,7 +58,7 @@ func (s Store) grpcUpsert(
 		pineconeVectors = append(
 			pineconeVectors,
 			&pinecone_grpc.Vector{
-				Id:       uuid.New().String(),
+				Id:       ids[i],
 				Values:   vectors[i],
 				Metadata: metadataStruct,
 			},
@@ -73,12 +73,44 @@ func (s Store) grpcUpsert(
 	return err
 }
 
+func (s Store) grpcDelete(ctx context.Context, ids []string, nameSpace string) error {
+	_, err := s.client.Delete(ctx, &pinecone_grpc.DeleteRequest{
+		Ids:       ids,
+		Namespace: nameSpace,
+	})
+
+	return err
+}
+
+func (s Store) grpcFetch(ctx context.Context, ids []string, nameSpace string) (map[string]schema.Document, error) {
+	fetchResult, err := s.client.Fetch(ctx, &pinecone_grpc.FetchRequest{
+		Ids:       ids,
+		Namespace: nameSpace,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make(map[string]schema.Document, len(fetchResult.Vectors))
+	for id, v := range fetchResult.Vectors {
+		doc, err := s.documentFromMetadata(v.Metadata.AsMap())
+		if err != nil {
+			return nil, err
+		}
+		docs[id] = doc
+	}
+
+	return docs, nil
+}
+
 func (s Store) grpcQuery(
 	ctx context.Context,
 	vector []float32,
 	numDocs int,
 	nameSpace string,
-) ([]schema.Document, error) {
+	scoreThreshold float32,
+	includeValues bool,
+) ([]schema.Document, [][]float32, error) {
 	queryResult, err := s.client.Query(
 		ctx,
 		&pinecone_grpc.QueryRequest{
@@ -86,33 +118,40 @@ func (s Store) grpcQuery(
 				{Values: vector},
 			},
 			TopK:          uint32(numDocs),
-			IncludeValues: false,
+			IncludeValues: includeValues,
 			Namespace:     nameSpace,
 		},
 	)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if len(queryResult.Results) == 0 {
-		return nil, ErrEmptyResponse
+		return nil, nil, ErrEmptyResponse
 	}
 
 	resultDocuments := make([]schema.Document, 0)
+	vectors := make([][]float32, 0)
 	for _, match := range queryResult.Results[0].Matches {
 		metadata := match.Metadata.AsMap()
 
 		pageContent, ok := metadata[s.textKey].(string)
 		if !ok {
-			return nil, ErrMissingTextKey
+			return nil, nil, ErrMissingTextKey
 		}
 		delete(metadata, s.textKey)
 
+		score := s.relevance(match.Score)
+		if score < scoreThreshold {
+			continue
+		}
 		resultDocuments = append(resultDocuments, schema.Document{
 			PageContent: pageContent,
 			Metadata:    metadata,
+			Score:       score,
 		})
+		vectors = append(vectors, match.Values)
 	}
 
-	return resultDocuments, nil
+	return resultDocuments, vectors, nil
 }
@@ -13,6 +13,18 @@ const (
 	_defaultTextKey    = "text"
 )
 
+// Metric is the distance metric of a pinecone index.
+type Metric string
+
+const (
+	// MetricCosine is the cosine similarity metric, the default of pinecone indexes.
+	MetricCosine Metric = "cosine"
+	// MetricDotProduct is the dot product metric.
+	MetricDotProduct Metric = "dotproduct"
+	// MetricEuclidean is the squared Euclidean distance metric.
+	MetricEuclidean Metric = "euclidean"
+)
+
 // ErrInvalidOptions is returned when the options given are invalid.
 var ErrInvalidOptions = errors.New("invalid options")
 
@@ -66,6 +78,14 @@ func WithTextKey(textKey string) Option {
 	}
 }
 
+// WithMetric is an option for setting the metric of the index, used to convert the scores
+// returned by pinecone to relevance scores. Defaults to MetricCosine.
+func WithMetric(metric Metric) Option {
+	return func(p *Store) {
+		p.metric = metric
+	}
+}
+
 // NameSpace is an option for setting the nameSpace to upsert and query the vectors
 // from. Must be set.
 func WithNameSpace(nameSpace string) Option {
@@ -84,6 +104,7 @@ func withGrpc() Option { // nolint: unused
 func applyClientOptions(opts ...Option) (Store, error) {
 	o := &Store{
 		textKey: _defaultTextKey,
+		metric:  MetricCosine,
 	}
 
 	for _, opt := range opts {
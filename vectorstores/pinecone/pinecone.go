@@ -4,10 +4,12 @@ import (
 	"context"
 	"errors"
 
+	"github.com/google/uuid"
 	"github.com/pinecone-io/go-pinecone/pinecone_grpc"
 	"github.com/tmc/langchaingo/embeddings"
 	"github.com/tmc/langchaingo/schema"
 	"github.com/tmc/langchaingo/vectorstores"
+	"github.com/tmc/langchaingo/vectorstores/filter"
 	"google.golang.org/grpc"
 )
 
@@ -38,10 +40,17 @@ type Store struct {
 	apiKey      string
 	textKey     string
 	nameSpace   string
+	metric      Metric
 	useGRPC     bool
 }
 
-var _ vectorstores.VectorStore = Store{}
+var (
+	_ vectorstores.VectorStore   = Store{}
+	_ vectorstores.Upserter      = Store{}
+	_ vectorstores.IDDeleter     = Store{}
+	_ vectorstores.FilterDeleter = Store{}
+	_ vectorstores.Getter        = Store{}
+)
 
 // New creates a new Store with options. Options for index name, environment, project name
 // and embedder must be set.
@@ -68,6 +77,22 @@ func New(ctx context.Context, opts ...Option) (Store, error) {
 // AddDocuments creates vector embeddings from the documents using the embedder
 // and upsert the vectors to the pinecone index.
 func (s Store) AddDocuments(ctx context.Context, docs []schema.Document, options ...vectorstores.Option) error {
+	ids := make([]string, 0, len(docs))
+	for range docs {
+		ids = append(ids, uuid.New().String())
+	}
+	return s.UpsertDocuments(ctx, ids, docs, options...)
+}
+
+// UpsertDocuments creates vector embeddings from the documents using the embedder
+// and upsert the vectors to the pinecone index with the given ids.
+func (s Store) UpsertDocuments(
+	ctx context.Context, ids []string, docs []schema.Document, options ...vectorstores.Option,
+) error {
+	if err := vectorstores.CheckIDs(ids, docs); err != nil {
+		return err
+	}
+
 	opts := s.getOptions(options...)
 
 	nameSpace := s.getNameSpace(opts)
@@ -98,20 +123,23 @@ func (s Store) AddDocuments(ctx context.Context, docs []schema.Document, options
 	}
 
 	if s.useGRPC {
-		return s.grpcUpsert(ctx, vectors, metadatas, nameSpace)
+		return s.grpcUpsert(ctx, ids, vectors, metadatas, nameSpace)
 	}
 
-	return s.restUpsert(ctx, vectors, metadatas, nameSpace)
+	return s.restUpsert(ctx, ids, vectors, metadatas, nameSpace)
 }
 
 // SimilaritySearch creates a vector embedding from the query using the embedder
-// and queries to find the most similar documents.
+// and queries to find the most similar documents, with their relevance score.
 func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments int, options ...vectorstores.Option) ([]schema.Document, error) { //nolint:lll
 	opts := s.getOptions(options...)
 
 	nameSpace := s.getNameSpace(opts)
 
-	filters := s.getFilters(opts)
+	filters, err := s.getFilters(opts)
+	if err != nil {
+		return nil, err
+	}
 
 	scoreThreshold, err := s.getScoreThreshold(opts)
 	if err != nil {
@@ -123,12 +151,90 @@ func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments
 		return nil, err
 	}
 
+	if opts.MMR == nil {
+		docs, _, err := s.query(ctx, vector, numDocuments, nameSpace, scoreThreshold, filters, false)
+		return docs, err
+	}
+
+	if err := opts.MMR.Validate(); err != nil {
+		return nil, err
+	}
+	docs, vectors, err := s.query(ctx, vector, opts.MMR.GetFetchK(numDocuments), nameSpace, scoreThreshold,
+		filters, true)
+	if err != nil {
+		return nil, err
+	}
+	return vectorstores.MaximalMarginalRelevanceDocuments(vector, docs, vectors, opts.MMR.Lambda, numDocuments), nil
+}
+
+// query returns the documents most similar to the vector and, if includeValues is true
+// or the REST API is used, their vectors.
+func (s Store) query(
+	ctx context.Context,
+	vector []float32,
+	numDocuments int,
+	nameSpace string,
+	scoreThreshold float32,
+	filters any,
+	includeValues bool,
+) ([]schema.Document, [][]float32, error) {
+	if s.useGRPC {
+		return s.grpcQuery(ctx, vector, numDocuments, nameSpace, scoreThreshold, includeValues)
+	}
+
+	return s.restQuery(ctx, vector, numDocuments, nameSpace, scoreThreshold, filters)
+}
+
+// DeleteDocumentsByID deletes the vectors with the given ids from the name space.
+func (s Store) DeleteDocumentsByID(ctx context.Context, ids []string, options ...vectorstores.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	nameSpace := s.getNameSpace(s.getOptions(options...))
 	if s.useGRPC {
-		return s.grpcQuery(ctx, vector, numDocuments, nameSpace)
+		return s.grpcDelete(ctx, ids, nameSpace)
 	}
 
-	return s.restQuery(ctx, vector, numDocuments, nameSpace, scoreThreshold,
-		filters)
+	return s.restDelete(ctx, deletePayload{IDs: ids, Namespace: nameSpace})
+}
+
+// DeleteDocuments deletes the vectors of the name space matching the filters. All vectors
+// of the name space are deleted if no filters are given and vectorstores.WithDeleteAll is set.
+func (s Store) DeleteDocuments(ctx context.Context, options ...vectorstores.Option) error {
+	opts := s.getOptions(options...)
+	if err := vectorstores.CheckDeleteFilters(opts); err != nil {
+		return err
+	}
+
+	filters, err := s.getFilters(opts)
+	if err != nil {
+		return err
+	}
+
+	payload := deletePayload{Namespace: s.getNameSpace(opts), Filter: filters}
+	if payload.Filter == nil {
+		payload.DeleteAll = true
+	}
+
+	// Deleting by filter is not supported by the grpc api.
+	return s.restDelete(ctx, payload)
+}
+
+// GetDocumentsByID returns the documents of the name space with the given ids.
+func (s Store) GetDocumentsByID(
+	ctx context.Context, ids []string, options ...vectorstores.Option,
+) (map[string]schema.Document, error) {
+	if len(ids) == 0 {
+		return map[string]schema.Document{}, nil
+	}
+
+	nameSpace := s.getNameSpace(s.getOptions(options...))
+	if s.useGRPC {
+		return s.grpcFetch(ctx, ids, nameSpace)
+	}
+
+	return s.restFetch(ctx, ids, nameSpace)
 }
 
 // Close closes the grpc connection.
@@ -136,6 +242,17 @@ func (s Store) Close() error {
 	return s.grpcConn.Close()
 }
 
+// documentFromMetadata returns the document stored in the metadata of a vector.
+func (s Store) documentFromMetadata(metadata map[string]any) (schema.Document, error) {
+	pageContent, ok := metadata[s.textKey].(string)
+	if !ok {
+		return schema.Document{}, ErrMissingTextKey
+	}
+	delete(metadata, s.textKey)
+
+	return schema.Document{PageContent: pageContent, Metadata: metadata}, nil
+}
+
 func (s Store) getNameSpace(opts vectorstores.Options) string {
 	if opts.NameSpace != "" {
 		return opts.NameSpace
@@ -143,6 +260,15 @@ func (s Store) getNameSpace(opts vectorstores.Options) string {
 	return s.nameSpace
 }
 
+// relevance returns the relevance score of a score returned by pinecone, which is a squared
+// distance for the euclidean metric and a similarity otherwise.
+func (s Store) relevance(score float32) float32 {
+	if s.metric == MetricEuclidean {
+		return vectorstores.RelevanceFromSquaredL2Distance(score)
+	}
+	return vectorstores.RelevanceFromSimilarity(score)
+}
+
 func (s Store) getScoreThreshold(opts vectorstores.Options) (float32, error) {
 	if opts.ScoreThreshold < 0 || opts.ScoreThreshold > 1 {
 		return 0, ErrInvalidScoreThreshold
@@ -150,12 +276,18 @@ func (s Store) getScoreThreshold(opts vectorstores.Options) (float32, error) {
 	return opts.ScoreThreshold, nil
 }
 
-func (s Store) getFilters(opts vectorstores.Options) any {
-	if opts.Filters != nil {
-		return opts.Filters
+// getFilters returns the filters of the options, translated to a pinecone metadata filter if
+// they are a filter.Filter.
+func (s Store) getFilters(opts vectorstores.Options) (any, error) {
+	if f, ok := opts.Filters.(filter.Filter); ok {
+		metadataFilter, err := filter.ToMongo(f)
+		if metadataFilter == nil || err != nil {
+			// A filter matching all vectors is sent as no filter, not as a nil map.
+			return nil, err
+		}
+		return metadataFilter, nil
 	}
-
-	return nil
+	return opts.Filters, nil
 }
 
 func (s Store) getOptions(options ...vectorstores.Option) vectorstores.Options {
@@ -609,3 +609,50 @@ func TestPineconeAsRetrieverWithMetadataFilters(t *testing.T) {
 
 	require.Contains(t, result, "purple", "expected black in purple")
 }
+
+func TestPineconeStoreRestUpsertDeleteAndGet(t *testing.T) {
+	t.Parallel()
+
+	environment, apiKey, indexName, projectName := getValues(t)
+	e, err := openaiEmbeddings.NewOpenAI()
+	require.NoError(t, err)
+
+	storer, err := pinecone.New(
+		context.Background(),
+		pinecone.WithAPIKey(apiKey),
+		pinecone.WithEnvironment(environment),
+		pinecone.WithIndexName(indexName),
+		pinecone.WithProjectName(projectName),
+		pinecone.WithEmbedder(e),
+		pinecone.WithNameSpace(uuid.New().String()),
+	)
+	require.NoError(t, err)
+
+	ids := []string{"city", "vegetable"}
+	err = storer.UpsertDocuments(context.Background(), ids, []schema.Document{
+		{PageContent: "tokyo", Metadata: map[string]any{"country": "japan"}},
+		{PageContent: "potato"},
+	})
+	require.NoError(t, err)
+
+	// Upserting replaces the document with the same id.
+	err = storer.UpsertDocuments(context.Background(), ids[:1], []schema.Document{
+		{PageContent: "kyoto", Metadata: map[string]any{"country": "japan"}},
+	})
+	require.NoError(t, err)
+
+	docs, err := storer.GetDocumentsByID(context.Background(), ids)
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	require.Equal(t, "kyoto", docs["city"].PageContent)
+
+	err = storer.DeleteDocumentsByID(context.Background(), ids[1:])
+	require.NoError(t, err)
+
+	docs, err = storer.GetDocumentsByID(context.Background(), ids)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+
+	err = storer.UpsertDocuments(context.Background(), ids, []schema.Document{{PageContent: "tokyo"}})
+	require.ErrorIs(t, err, vectorstores.ErrMismatchedIDs)
+}
@@ -9,7 +9,6 @@ import (
 	"net/http"
 	"net/url"
 
-	"github.com/google/uuid"
 	"github.com/tmc/langchaingo/schema"
 )
 
@@ -47,6 +46,7 @@ type upsertPayload struct {
 
 func (s Store) restUpsert(
 	ctx context.Context,
+	ids []string,
 	vectors [][]float32,
 	metadatas []map[string]any,
 	nameSpace string,
@@ -56,7 +56,7 @@ func (s Store) restUpsert(
 		v = append(v, vector{
 			Values:   vectors[i],
 			Metadata: metadatas[i],
-			ID:       uuid.New().String(),
+			ID:       ids[i],
 		})
 	}
 
@@ -84,6 +84,77 @@ func (s Store) restUpsert(
 	return newAPIError("upserting vectors", body)
 }
 
+type deletePayload struct {
+	IDs       []string `json:"ids,omitempty"`
+	DeleteAll bool     `json:"deleteAll,omitempty"`
+	Namespace string   `json:"namespace"`
+	Filter    any      `json:"filter,omitempty"`
+}
+
+func (s Store) restDelete(ctx context.Context, payload deletePayload) error {
+	body, status, err := doRequest(
+		ctx,
+		payload,
+		getEndpoint(s.indexName, s.projectName, s.environment)+"/vectors/delete",
+		s.apiKey,
+		http.MethodPost,
+	)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	if status == http.StatusOK {
+		return nil
+	}
+
+	return newAPIError("deleting vectors", body)
+}
+
+type fetchResponse struct {
+	Vectors   map[string]vector `json:"vectors"`
+	Namespace string            `json:"namespace"`
+}
+
+func (s Store) restFetch(ctx context.Context, ids []string, nameSpace string) (map[string]schema.Document, error) {
+	query := url.Values{"ids": ids}
+	if nameSpace != "" {
+		query.Set("namespace", nameSpace)
+	}
+
+	body, status, err := doRequest(
+		ctx,
+		nil,
+		getEndpoint(s.indexName, s.projectName, s.environment)+"/vectors/fetch?"+query.Encode(),
+		s.apiKey,
+		http.MethodGet,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	if status != http.StatusOK {
+		return nil, newAPIError("fetching vectors", body)
+	}
+
+	var response fetchResponse
+	if err := json.NewDecoder(body).Decode(&response); err != nil {
+		return nil, err
+	}
+
+	docs := make(map[string]schema.Document, len(response.Vectors))
+	for id, v := range response.Vectors {
+		doc, err := s.documentFromMetadata(v.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		docs[id] = doc
+	}
+
+	return docs, nil
+}
+
 type sparseValues struct {
 	Indices []int     `json:"indices"`
 	Values  []float32 `json:"values"`
@@ -118,7 +189,7 @@ func (s Store) restQuery(
 	nameSpace string,
 	scoreThreshold float32,
 	filter any,
-) ([]schema.Document, error) {
+) ([]schema.Document, [][]float32, error) {
 	payload := queryPayload{
 		IncludeValues:   true,
 		IncludeMetadata: true,
@@ -136,12 +207,12 @@ func (s Store) restQuery(
 		http.MethodPost,
 	)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer body.Close()
 
 	if statusCode != http.StatusOK {
-		return nil, newAPIError("querying index", body)
+		return nil, nil, newAPIError("querying index", body)
 	}
 
 	var response queriesResponse
@@ -149,36 +220,35 @@ func (s Store) restQuery(
 	decoder := json.NewDecoder(body)
 	err = decoder.Decode(&response)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if len(response.Matches) == 0 {
-		return nil, ErrEmptyResponse
+		return nil, nil, ErrEmptyResponse
 	}
 
 	docs := make([]schema.Document, 0, len(response.Matches))
+	vectors := make([][]float32, 0, len(response.Matches))
 	for _, match := range response.Matches {
 		pageContent, ok := match.Metadata[s.textKey].(string)
 		if !ok {
-			return nil, ErrMissingTextKey
+			return nil, nil, ErrMissingTextKey
 		}
 		delete(match.Metadata, s.textKey)
 
 		doc := schema.Document{
 			PageContent: pageContent,
 			Metadata:    match.Metadata,
-			Score:       match.Score,
+			Score:       s.relevance(match.Score),
 		}
 
-		// If scoreThreshold is not 0, we only return matches with a score above the threshold.
-		if scoreThreshold != 0 && match.Score >= scoreThreshold {
-			docs = append(docs, doc)
-		} else if scoreThreshold == 0 { // If scoreThreshold is 0, we return all matches.
+		if doc.Score >= scoreThreshold {
 			docs = append(docs, doc)
+			vectors = append(vectors, match.Values)
 		}
 	}
 
-	return docs, nil
+	return docs, vectors, nil
 }
 
 func doRequest(ctx context.Context, payload any, url, apiKey, method string) (io.ReadCloser, int, error) {
@@ -0,0 +1,87 @@
+package qdrant
+
+import (
+	"fmt"
+
+	"github.com/tmc/langchaingo/vectorstores/filter"
+)
+
+// qdrantFilterFromFilter translates a filter to a qdrant filter on the metadata payload of
+// the points.
+func (s Store) qdrantFilterFromFilter(f filter.Filter) (map[string]any, error) {
+	translated, err := s.translateFilter(f)
+	if err != nil {
+		return nil, err
+	}
+	// Conditions must be wrapped in a filter at the top level.
+	if _, isCondition := translated["key"]; isCondition {
+		return map[string]any{"must": []map[string]any{translated}}, nil
+	}
+	return translated, nil
+}
+
+// translateFilter returns a qdrant condition for comparisons and a nested qdrant filter for
+// logical filters and negations.
+func (s Store) translateFilter(f filter.Filter) (map[string]any, error) {
+	switch f := f.(type) {
+	case filter.Comparison:
+		return s.translateComparison(f)
+	case filter.Logical:
+		clause := "must"
+		if f.Operator == filter.OpOr {
+			clause = "should"
+		}
+		conditions, err := s.translateFilters(f.Filters)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{clause: conditions}, nil
+	case filter.Negation:
+		conditions, err := s.translateFilters([]filter.Filter{f.Filter})
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"must_not": conditions}, nil
+	default:
+		return nil, fmt.Errorf("%w: %T", filter.ErrUnsupportedFilter, f)
+	}
+}
+
+func (s Store) translateFilters(filters []filter.Filter) ([]map[string]any, error) {
+	conditions := make([]map[string]any, 0, len(filters))
+	for _, sub := range filters {
+		condition, err := s.translateFilter(sub)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, condition)
+	}
+	return conditions, nil
+}
+
+func (s Store) translateComparison(c filter.Comparison) (map[string]any, error) {
+	key := fmt.Sprintf("%s.%s", s.metadataKey, c.Key)
+
+	switch c.Operator {
+	case filter.OpEq:
+		return map[string]any{"key": key, "match": map[string]any{"value": c.Value}}, nil
+	case filter.OpNe:
+		return map[string]any{"must_not": []map[string]any{
+			{"key": key, "match": map[string]any{"value": c.Value}},
+		}}, nil
+	case filter.OpIn, filter.OpNin:
+		values, err := c.Values()
+		if err != nil {
+			return nil, err
+		}
+		match := "any"
+		if c.Operator == filter.OpNin {
+			match = "except"
+		}
+		return map[string]any{"key": key, "match": map[string]any{match: values}}, nil
+	case filter.OpGt, filter.OpGte, filter.OpLt, filter.OpLte:
+		return map[string]any{"key": key, "range": map[string]any{string(c.Operator): c.Value}}, nil
+	default:
+		return nil, fmt.Errorf("%w: operator %q", filter.ErrUnsupportedFilter, c.Operator)
+	}
+}
@@ -0,0 +1,38 @@
+package qdrant
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/vectorstores/filter"
+)
+
+func TestQdrantFilterFromFilter(t *testing.T) {
+	t.Parallel()
+
+	s := Store{metadataKey: _defaultMetadataKey}
+
+	qdrantFilter, err := s.qdrantFilterFromFilter(filter.Eq("city", "London"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"must": []map[string]any{
+		{"key": "metadata.city", "match": map[string]any{"value": "London"}},
+	}}, qdrantFilter)
+
+	qdrantFilter, err = s.qdrantFilterFromFilter(filter.Or(
+		filter.And(filter.In("city", "London", "Berlin"), filter.Gte("price", 10)),
+		filter.Not(filter.Ne("color", "red")),
+	))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"should": []map[string]any{
+		{"must": []map[string]any{
+			{"key": "metadata.city", "match": map[string]any{"any": []any{"London", "Berlin"}}},
+			{"key": "metadata.price", "range": map[string]any{"gte": 10}},
+		}},
+		{"must_not": []map[string]any{
+			{"must_not": []map[string]any{
+				{"key": "metadata.color", "match": map[string]any{"value": "red"}},
+			}},
+		}},
+	}}, qdrantFilter)
+}
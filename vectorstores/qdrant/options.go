@@ -13,6 +13,8 @@ const (
 	_qdrantURLEnvVarName = "QDRANT_BASE_URL"
 	_defaultContentKey   = "page_content"
 	_defaultMetadataKey  = "metadata"
+	_distanceCosine      = "Cosine"
+	_distanceEuclid      = "Euclid"
 )
 
 var _defaultCollectionConfig = map[string]any{
@@ -26,7 +28,7 @@ var _defaultCollectionConfig = map[string]any{
 	},
 	"vectors": map[string]any{
 		"size":     1536,
-		"distance": "Cosine",
+		"distance": _distanceCosine,
 	},
 	"on_disk_payload": true,
 }
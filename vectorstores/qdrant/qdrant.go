@@ -5,9 +5,12 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/google/uuid"
 	"github.com/tmc/langchaingo/embeddings"
 	"github.com/tmc/langchaingo/schema"
 	"github.com/tmc/langchaingo/vectorstores"
+	"github.com/tmc/langchaingo/vectorstores/filter"
+	"golang.org/x/exp/maps"
 )
 
 var (
@@ -24,6 +27,9 @@ var (
 	ErrEmptyResponse         = errors.New("empty response")
 	ErrInvalidScoreThreshold = errors.New(
 		"score threshold must be between 0 and 1")
+	// ErrInvalidPointID is returned when the "__point_id" metadata of a document
+	// is not a string.
+	ErrInvalidPointID = errors.New("invalid point id")
 )
 
 // Store is a wrapper around the pinecone rest API and grpc client.
@@ -40,7 +46,13 @@ type Store struct {
 	collectionConfig map[string]any
 }
 
-var _ vectorstores.VectorStore = Store{}
+var (
+	_ vectorstores.VectorStore   = Store{}
+	_ vectorstores.Upserter      = Store{}
+	_ vectorstores.IDDeleter     = Store{}
+	_ vectorstores.FilterDeleter = Store{}
+	_ vectorstores.Getter        = Store{}
+)
 
 // New creates a new Store with options. Options for index name, environment, project name
 // and embedder must be set.
@@ -61,6 +73,29 @@ func New(ctx context.Context, opts ...Option) (Store, error) {
 // AddDocuments creates vector embeddings from the documents using the embedder
 // and upsert the vectors to the pinecone index.
 func (s Store) AddDocuments(ctx context.Context, docs []schema.Document, options ...vectorstores.Option) error {
+	return s.upsertDocuments(ctx, nil, docs, options...)
+}
+
+// UpsertDocuments creates vector embeddings from the documents using the embedder
+// and upsert them as points with the given ids. Ids that are not UUIDs are mapped to
+// UUIDs derived from them, since qdrant only accepts UUIDs and integers as point ids.
+func (s Store) UpsertDocuments(
+	ctx context.Context, ids []string, docs []schema.Document, options ...vectorstores.Option,
+) error {
+	if err := vectorstores.CheckIDs(ids, docs); err != nil {
+		return err
+	}
+	return s.upsertDocuments(ctx, ids, docs, options...)
+}
+
+func (s Store) upsertDocuments(
+	ctx context.Context, ids []string, docs []schema.Document, options ...vectorstores.Option,
+) error {
+	pointIDs, err := getPointIDs(ids, docs)
+	if err != nil {
+		return err
+	}
+
 	opts := s.getOptions(options...)
 	embedder := s.getEmbedder(opts)
 
@@ -92,7 +127,7 @@ func (s Store) AddDocuments(ctx context.Context, docs []schema.Document, options
 		if end > len(docs) {
 			end = len(docs)
 		}
-		err = s.restUpsert(ctx, texts[i:end], vectors[i:end], metadatas[i:end], s.collectionName)
+		err = s.restUpsert(ctx, pointIDs[i:end], texts[i:end], vectors[i:end], metadatas[i:end], s.collectionName)
 		if err != nil {
 			return err
 		}
@@ -102,12 +137,16 @@ func (s Store) AddDocuments(ctx context.Context, docs []schema.Document, options
 }
 
 // SimilaritySearch creates a vector embedding from the query using the embedder
-// and queries to find the most similar documents.
+// and queries to find the most similar documents, with their relevance score. The
+// Cosine, Dot and Euclid distances are supported.
 func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments int, options ...vectorstores.Option) ([]schema.Document, error) { //nolint:lll
 	opts := s.getOptions(options...)
 	embedder := s.getEmbedder(opts)
 
-	filters := s.getFilters(opts)
+	filters, err := s.getFilters(opts)
+	if err != nil {
+		return nil, err
+	}
 
 	scoreThreshold, err := s.getScoreThreshold(opts)
 	if err != nil {
@@ -119,26 +158,106 @@ func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments
 		return nil, err
 	}
 
-	return s.restQuery(ctx, vector, numDocuments, s.collectionName, scoreThreshold,
-		filters)
+	if opts.MMR == nil {
+		docs, _, err := s.restQuery(ctx, vector, numDocuments, s.collectionName, scoreThreshold, filters)
+		return docs, err
+	}
+
+	if err := opts.MMR.Validate(); err != nil {
+		return nil, err
+	}
+	docs, vectors, err := s.restQuery(ctx, vector, opts.MMR.GetFetchK(numDocuments), s.collectionName,
+		scoreThreshold, filters)
+	if err != nil {
+		return nil, err
+	}
+	return vectorstores.MaximalMarginalRelevanceDocuments(vector, docs, vectors, opts.MMR.Lambda, numDocuments), nil
 }
 
 func (s Store) ScrollPoints(ctx context.Context, req *ScrollPointsRequest) ([]schema.Document, string, error) {
 	return s.restScrollPoints(ctx, s.collectionName, req)
 }
 
-// DeleteDocuments deletes the documents
+// DeleteDocuments deletes the documents matching the filters. All documents are deleted if no
+// filters are given and vectorstores.WithDeleteAll is set.
 func (s Store) DeleteDocuments(ctx context.Context, options ...vectorstores.Option) error {
 	opts := s.getOptions(options...)
-	filters := s.getFilters(opts)
+	if err := vectorstores.CheckDeleteFilters(opts); err != nil {
+		return err
+	}
+
+	filters, err := s.getFilters(opts)
+	if err != nil {
+		return err
+	}
+	if filters == nil {
+		// The empty filter matches all points.
+		filters = map[string]any{}
+	}
 	return s.restDeletePoints(ctx, s.collectionName, filters)
 }
 
+// DeleteDocumentsByID deletes the points with the given ids.
+func (s Store) DeleteDocumentsByID(ctx context.Context, ids []string, _ ...vectorstores.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	pointIDs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		pointIDs = append(pointIDs, pointID(id))
+	}
+	return s.restDeletePointsByID(ctx, s.collectionName, pointIDs)
+}
+
+// GetDocumentsByID returns the documents stored in the points with the given ids.
+func (s Store) GetDocumentsByID(
+	ctx context.Context, ids []string, _ ...vectorstores.Option,
+) (map[string]schema.Document, error) {
+	docs := make(map[string]schema.Document, len(ids))
+	if len(ids) == 0 {
+		return docs, nil
+	}
+
+	idsByPointID := make(map[string]string, len(ids))
+	for _, id := range ids {
+		idsByPointID[pointID(id)] = id
+	}
+
+	pointDocs, err := s.restGetPoints(ctx, s.collectionName, maps.Keys(idsByPointID))
+	if err != nil {
+		return nil, err
+	}
+	for pointID, doc := range pointDocs {
+		docs[idsByPointID[pointID]] = doc
+	}
+
+	return docs, nil
+}
+
 // Close closes the grpc connection.
 func (s Store) Close() error {
 	return nil
 }
 
+// distance returns the distance of the collection vectors set in the collection config.
+func (s Store) distance() string {
+	vectors, _ := s.collectionConfig["vectors"].(map[string]any)
+	if distance, ok := vectors["distance"].(string); ok {
+		return distance
+	}
+	return _distanceCosine
+}
+
+// relevance returns the relevance score of a score returned by qdrant, which is a distance
+// for the Euclid distance and a similarity otherwise.
+func (s Store) relevance(score float32) float32 {
+	if s.distance() == _distanceEuclid {
+		return vectorstores.RelevanceFromL2Distance(score)
+	}
+	return vectorstores.RelevanceFromSimilarity(score)
+}
+
 func (s Store) getScoreThreshold(opts vectorstores.Options) (float32, error) {
 	if opts.ScoreThreshold < 0 || opts.ScoreThreshold > 1 {
 		return 0, ErrInvalidScoreThreshold
@@ -146,12 +265,13 @@ func (s Store) getScoreThreshold(opts vectorstores.Options) (float32, error) {
 	return opts.ScoreThreshold, nil
 }
 
-func (s Store) getFilters(opts vectorstores.Options) any {
-	if opts.Filters != nil {
-		return opts.Filters
+// getFilters returns the filters of the options, translated to a qdrant filter if they are
+// a filter.Filter.
+func (s Store) getFilters(opts vectorstores.Options) (any, error) {
+	if f, ok := opts.Filters.(filter.Filter); ok {
+		return s.qdrantFilterFromFilter(f)
 	}
-
-	return nil
+	return opts.Filters, nil
 }
 
 func (s Store) getOptions(options ...vectorstores.Option) vectorstores.Options {
@@ -183,6 +303,37 @@ func (s Store) NewMustEqualFilter(filters ...FilterMatch) any {
 	}
 }
 
+// getPointIDs returns the point ids of the documents: the given ids if any, else
+// the "__point_id" metadata of the documents, else random UUIDs.
+func getPointIDs(ids []string, docs []schema.Document) ([]string, error) {
+	pointIDs := make([]string, 0, len(docs))
+	for i, doc := range docs {
+		if ids != nil {
+			pointIDs = append(pointIDs, pointID(ids[i]))
+			continue
+		}
+
+		switch id := doc.Metadata["__point_id"].(type) {
+		case nil:
+			pointIDs = append(pointIDs, uuid.New().String())
+		case string:
+			pointIDs = append(pointIDs, pointID(id))
+		default:
+			return nil, fmt.Errorf("%w: %v of type %T", ErrInvalidPointID, id, id)
+		}
+	}
+	return pointIDs, nil
+}
+
+// pointID returns the id as a qdrant point id: UUIDs are kept and other ids are mapped
+// to a UUID derived from them.
+func pointID(id string) string {
+	if parsed, err := uuid.Parse(id); err == nil {
+		return parsed.String()
+	}
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(id)).String()
+}
+
 func (s Store) getEmbedder(options vectorstores.Options) embeddings.Embedder {
 	if options.Embedder != nil {
 		return options.Embedder
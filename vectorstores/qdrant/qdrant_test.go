@@ -0,0 +1,37 @@
+package qdrant
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestGetPointIDs(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.NewString()
+	docs := []schema.Document{
+		{PageContent: "uuid", Metadata: map[string]any{"__point_id": id}},
+		{PageContent: "name", Metadata: map[string]any{"__point_id": "doc-1"}},
+		{PageContent: "none"},
+	}
+
+	pointIDs, err := getPointIDs(nil, docs)
+	require.NoError(t, err)
+	require.Len(t, pointIDs, 3)
+	assert.Equal(t, id, pointIDs[0])
+	assert.Equal(t, pointID("doc-1"), pointIDs[1])
+	_, err = uuid.Parse(pointIDs[2])
+	require.NoError(t, err)
+
+	// The given ids take precedence over the metadata.
+	pointIDs, err = getPointIDs([]string{"a", "b", "c"}, docs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{pointID("a"), pointID("b"), pointID("c")}, pointIDs)
+
+	_, err = getPointIDs(nil, []schema.Document{{Metadata: map[string]any{"__point_id": 1}}})
+	assert.ErrorIs(t, err, ErrInvalidPointID)
+}
@@ -9,7 +9,6 @@ import (
 	"net/http"
 	"strings"
 
-	"github.com/google/uuid"
 	"github.com/tmc/langchaingo/schema"
 )
 
@@ -46,6 +45,7 @@ type upsertPayload struct {
 
 func (s Store) restUpsert(
 	ctx context.Context,
+	ids []string,
 	texts []string,
 	vectors [][]float32,
 	metadatas []map[string]any,
@@ -53,14 +53,10 @@ func (s Store) restUpsert(
 ) error {
 	v := make([]point, 0, len(vectors))
 	for i := 0; i < len(vectors); i++ {
-		ID := uuid.New().String()
-		if metadatas[i] != nil && metadatas[i]["__point_id"] != nil {
-			ID = metadatas[i]["__point_id"].(string)
-		}
 		v = append(v, point{
 			Vector:  vectors[i],
 			Payload: map[string]any{s.contentKey: texts[i], s.metadataKey: metadatas[i]},
-			ID:      ID,
+			ID:      ids[i],
 		})
 	}
 
@@ -112,6 +108,77 @@ func (s Store) restDeletePoints(ctx context.Context, collection string, filter a
 	}
 }
 
+func (s Store) restDeletePointsByID(ctx context.Context, collection string, ids []string) error {
+	payload := map[string]any{
+		"points": ids,
+	}
+	endpoint := getEndpoint(s.baseURL, collection, "/points/delete")
+	body, statusCode, err := doRequest(
+		ctx,
+		payload,
+		endpoint,
+		s.apiKey,
+		http.MethodPost,
+	)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	if statusCode == http.StatusOK {
+		return nil
+	}
+
+	return newAPIError("deleting points", body)
+}
+
+type pointsResponse struct {
+	Time   float32 `json:"time"`
+	Status string  `json:"status"`
+	Result []point `json:"result"`
+}
+
+func (s Store) restGetPoints(ctx context.Context, collection string, ids []string) (map[string]schema.Document, error) {
+	payload := map[string]any{
+		"ids":          ids,
+		"with_payload": true,
+		"with_vector":  false,
+	}
+	endpoint := getEndpoint(s.baseURL, collection, "/points")
+	body, statusCode, err := doRequest(
+		ctx,
+		payload,
+		endpoint,
+		s.apiKey,
+		http.MethodPost,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	if statusCode != http.StatusOK {
+		return nil, newAPIError("getting points", body)
+	}
+
+	var response pointsResponse
+	if err := json.NewDecoder(body).Decode(&response); err != nil {
+		return nil, err
+	}
+
+	docs := make(map[string]schema.Document, len(response.Result))
+	for _, p := range response.Result {
+		pageContent, ok := p.Payload[s.contentKey].(string)
+		if !ok {
+			return nil, ErrMissingTextKey
+		}
+		metadata, _ := p.Payload[s.metadataKey].(map[string]any)
+		docs[p.ID] = schema.Document{PageContent: pageContent, Metadata: metadata}
+	}
+
+	return docs, nil
+}
+
 type scoredPoint struct {
 	ID      string         `json:"id"`
 	Version int            `json:"version"`
@@ -192,14 +259,18 @@ func (s Store) restQuery(
 	collection string,
 	scoreThreshold float32,
 	filter any,
-) ([]schema.Document, error) {
+) ([]schema.Document, [][]float32, error) {
 	payload := queryPayload{
-		WithVector:     true,
-		WithPayload:    true,
-		Vector:         vector,
-		Limit:          numVectors,
-		Filter:         filter,
-		ScoreThreshold: scoreThreshold,
+		WithVector:  true,
+		WithPayload: true,
+		Vector:      vector,
+		Limit:       numVectors,
+		Filter:      filter,
+	}
+	// The scores of similarity metrics are relevance scores, so the threshold can be applied
+	// by qdrant. Distances are converted and filtered below.
+	if s.distance() != _distanceEuclid {
+		payload.ScoreThreshold = scoreThreshold
 	}
 
 	endpoint := getEndpoint(s.baseURL, collection, "/points/search")
@@ -211,12 +282,12 @@ func (s Store) restQuery(
 		http.MethodPost,
 	)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer body.Close()
 
 	if statusCode != http.StatusOK {
-		return nil, newAPIError("querying index", body)
+		return nil, nil, newAPIError("querying index", body)
 	}
 
 	var response queriesResponse
@@ -224,35 +295,34 @@ func (s Store) restQuery(
 	decoder := json.NewDecoder(body)
 	err = decoder.Decode(&response)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if len(response.Result) == 0 {
-		return nil, ErrEmptyResponse
+		return nil, nil, ErrEmptyResponse
 	}
 
 	docs := make([]schema.Document, 0, len(response.Result))
+	vectors := make([][]float32, 0, len(response.Result))
 	for _, spoint := range response.Result {
 		pageContent, ok := spoint.Payload[s.contentKey].(string)
 		if !ok {
-			return nil, ErrMissingTextKey
+			return nil, nil, ErrMissingTextKey
 		}
 
 		doc := schema.Document{
 			PageContent: pageContent,
 			Metadata:    spoint.Payload[s.metadataKey].(map[string]any),
-			Score:       spoint.Score,
+			Score:       s.relevance(spoint.Score),
 		}
 
-		// If scoreThreshold is not 0, we only return matches with a score above the threshold.
-		if scoreThreshold != 0 && spoint.Score >= scoreThreshold {
-			docs = append(docs, doc)
-		} else if scoreThreshold == 0 { // If scoreThreshold is 0, we return all matches.
+		if doc.Score >= scoreThreshold {
 			docs = append(docs, doc)
+			vectors = append(vectors, spoint.Vector)
 		}
 	}
 
-	return docs, nil
+	return docs, vectors, nil
 }
 
 type scrollResult struct {
@@ -0,0 +1,6 @@
+// Package redis contains an implementation of the vectorStore interface using the vector
+// similarity search of RediSearch, available in Redis Stack. The documents are stored in
+// hashes whose keys are prefixed by the name of their index, and each name space is a
+// separate index, created when documents are first added to it. The package talks to
+// Redis with the go-redis client.
+package redis
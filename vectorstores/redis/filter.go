@@ -0,0 +1,184 @@
+package redis
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/tmc/langchaingo/vectorstores/filter"
+	"golang.org/x/exp/slices"
+)
+
+// queryFromFilter translates a filter to a RediSearch query on the indexed metadata
+// fields. Comparisons on keys that are not indexed are not supported, nor are the empty
+// In filters and Or filters, which RediSearch queries can not express.
+func (s Store) queryFromFilter(f filter.Filter) (string, error) {
+	switch f := f.(type) {
+	case filter.Comparison:
+		return s.translateComparison(f)
+	case filter.Logical:
+		if len(f.Filters) == 0 {
+			if f.Operator == filter.OpOr {
+				return "", fmt.Errorf("%w: empty or", filter.ErrUnsupportedFilter)
+			}
+			return "*", nil
+		}
+		queries := make([]string, 0, len(f.Filters))
+		for _, sub := range f.Filters {
+			query, err := s.queryFromFilter(sub)
+			if err != nil {
+				return "", err
+			}
+			queries = append(queries, query)
+		}
+		separator := " "
+		if f.Operator == filter.OpOr {
+			separator = " | "
+		}
+		return "(" + strings.Join(queries, separator) + ")", nil
+	case filter.Negation:
+		query, err := s.queryFromFilter(f.Filter)
+		if err != nil {
+			return "", err
+		}
+		return "-(" + query + ")", nil
+	default:
+		return "", fmt.Errorf("%w: %T", filter.ErrUnsupportedFilter, f)
+	}
+}
+
+func (s Store) translateComparison(c filter.Comparison) (string, error) {
+	switch {
+	case slices.Contains(s.tagFields, c.Key):
+		return translateTagComparison(c)
+	case slices.Contains(s.numericFields, c.Key):
+		return translateNumericComparison(c)
+	default:
+		return "", fmt.Errorf("%w: key %q is not an indexed field", filter.ErrUnsupportedFilter, c.Key)
+	}
+}
+
+func translateTagComparison(c filter.Comparison) (string, error) {
+	field := "@" + fieldName(c.Key)
+
+	var values []any
+	switch c.Operator {
+	case filter.OpEq, filter.OpNe:
+		values = []any{c.Value}
+	case filter.OpIn, filter.OpNin:
+		var err error
+		if values, err = c.Values(); err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("%w: operator %q on tag field %q", filter.ErrUnsupportedFilter, c.Operator, c.Key)
+	}
+
+	if len(values) == 0 {
+		if c.Operator == filter.OpNin {
+			return "*", nil
+		}
+		return "", fmt.Errorf("%w: empty in", filter.ErrUnsupportedFilter)
+	}
+
+	tags := make([]string, 0, len(values))
+	for _, v := range values {
+		tags = append(tags, escapeTag(tagValue(v)))
+	}
+	query := fmt.Sprintf("%s:{%s}", field, strings.Join(tags, " | "))
+	if c.Operator == filter.OpNe || c.Operator == filter.OpNin {
+		return "-" + query, nil
+	}
+	return query, nil
+}
+
+func translateNumericComparison(c filter.Comparison) (string, error) {
+	field := "@" + fieldName(c.Key)
+
+	switch c.Operator {
+	case filter.OpIn, filter.OpNin:
+		values, err := c.Values()
+		if err != nil {
+			return "", err
+		}
+		if len(values) == 0 {
+			if c.Operator == filter.OpNin {
+				return "*", nil
+			}
+			return "", fmt.Errorf("%w: empty in", filter.ErrUnsupportedFilter)
+		}
+		ranges := make([]string, 0, len(values))
+		for _, v := range values {
+			n, err := numericValue(c.Key, v)
+			if err != nil {
+				return "", err
+			}
+			ranges = append(ranges, fmt.Sprintf("%s:[%s %[2]s]", field, n))
+		}
+		query := "(" + strings.Join(ranges, " | ") + ")"
+		if c.Operator == filter.OpNin {
+			return "-" + query, nil
+		}
+		return query, nil
+	case filter.OpEq, filter.OpNe, filter.OpGt, filter.OpGte, filter.OpLt, filter.OpLte:
+		n, err := numericValue(c.Key, c.Value)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf(_numericRanges[c.Operator], field, n), nil
+	default:
+		return "", fmt.Errorf("%w: operator %q", filter.ErrUnsupportedFilter, c.Operator)
+	}
+}
+
+// _numericRanges are the formats of the numeric comparisons, given the field and the value.
+var _numericRanges = map[filter.Operator]string{ //nolint:gochecknoglobals
+	filter.OpEq:  "%s:[%s %[2]s]",
+	filter.OpNe:  "-%s:[%s %[2]s]",
+	filter.OpGt:  "%s:[(%s +inf]",
+	filter.OpGte: "%s:[%s +inf]",
+	filter.OpLt:  "%s:[-inf (%s]",
+	filter.OpLte: "%s:[-inf %s]",
+}
+
+// fieldName returns the name of the hash field, and index field, of a metadata key.
+func fieldName(key string) string {
+	return _metadataField + "_" + key
+}
+
+// tagValue returns the value of a tag field.
+func tagValue(value any) string {
+	if b, ok := value.(bool); ok {
+		return strconv.FormatBool(b)
+	}
+	return fmt.Sprint(value)
+}
+
+// numericValue returns the value of a numeric field, or an error if the value is not a
+// number.
+func numericValue(key string, value any) (string, error) {
+	v := reflect.ValueOf(value)
+	switch {
+	case v.CanInt():
+		return strconv.FormatInt(v.Int(), 10), nil
+	case v.CanUint():
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case v.CanFloat():
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64), nil
+	default:
+		return "", fmt.Errorf("%w: non numeric value %v for numeric field %q", filter.ErrUnsupportedFilter, value, key)
+	}
+}
+
+// escapeTag escapes the punctuation and spaces of a tag value.
+func escapeTag(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		if strings.ContainsRune(",.<>{}[]\"':;!@#$%^&*()-+=~|/\\ ", r) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,40 @@
+package redis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/vectorstores/filter"
+)
+
+func TestQueryFromFilter(t *testing.T) {
+	t.Parallel()
+
+	s := Store{tagFields: []string{"city", "color"}, numericFields: []string{"price"}}
+
+	query, err := s.queryFromFilter(filter.Eq("city", "New York"))
+	require.NoError(t, err)
+	assert.Equal(t, `@metadata_city:{New\ York}`, query)
+
+	query, err = s.queryFromFilter(filter.Or(
+		filter.And(filter.In("city", "London", "Berlin"), filter.Gte("price", 10)),
+		filter.Not(filter.Ne("color", "red")),
+		filter.In("price", 1, 2.5),
+		filter.Lt("price", 3),
+	))
+	require.NoError(t, err)
+	assert.Equal(t, "((@metadata_city:{London | Berlin} @metadata_price:[10 +inf]) | "+
+		"-(-@metadata_color:{red}) | "+
+		"(@metadata_price:[1 1] | @metadata_price:[2.5 2.5]) | "+
+		"@metadata_price:[-inf (3])", query)
+
+	_, err = s.queryFromFilter(filter.Eq("country", "japan"))
+	require.ErrorIs(t, err, filter.ErrUnsupportedFilter)
+
+	_, err = s.queryFromFilter(filter.Gt("city", "London"))
+	require.ErrorIs(t, err, filter.ErrUnsupportedFilter)
+
+	_, err = s.queryFromFilter(filter.Eq("price", "cheap"))
+	require.ErrorIs(t, err, filter.ErrUnsupportedFilter)
+}
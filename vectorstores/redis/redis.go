@@ -0,0 +1,574 @@
+package redis
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+	"github.com/tmc/langchaingo/vectorstores/filter"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	_contentField   = "content"
+	_metadataField  = "metadata"
+	_embeddingField = "embedding"
+	_scoreField     = "score"
+
+	// _batchSize is the number of documents written, deleted or read per pipeline.
+	_batchSize = 500
+)
+
+var (
+	// ErrEmbedderWrongNumberVectors is returned when if the embedder returns a number
+	// of vectors that is not equal to the number of documents given.
+	ErrEmbedderWrongNumberVectors = errors.New(
+		"number of vectors from embedder does not match number of documents",
+	)
+	// ErrInvalidScoreThreshold is returned when the score threshold is not between 0 and 1.
+	ErrInvalidScoreThreshold = errors.New("score threshold must be between 0 and 1")
+	// ErrInvalidFilter is returned when the filters are neither a filter.Filter nor a
+	// RediSearch query string.
+	ErrInvalidFilter = errors.New("invalid filter")
+	// ErrUnexpectedReply is returned when Redis replies to a command with an unexpected
+	// value.
+	ErrUnexpectedReply = errors.New("unexpected reply")
+	// ErrInvalidNameSpace is returned when a name space contains a ':', which separates
+	// the key prefix of an index from the ids of its documents.
+	ErrInvalidNameSpace = errors.New("invalid name space")
+)
+
+// Store is a vector store using the vector similarity search of RediSearch. The documents
+// are stored in hashes with their content, metadata and embedding, and the metadata keys
+// set with WithTagFields and WithNumericFields. Each name space is a separate index.
+type Store struct {
+	embedder embeddings.Embedder
+	client   *goredis.Client
+
+	url            string
+	indexName      string
+	distanceMetric DistanceMetric
+	algorithm      Algorithm
+	tagFields      []string
+	numericFields  []string
+}
+
+var (
+	_ vectorstores.VectorStore   = Store{}
+	_ vectorstores.Upserter      = Store{}
+	_ vectorstores.IDDeleter     = Store{}
+	_ vectorstores.FilterDeleter = Store{}
+	_ vectorstores.Getter        = Store{}
+)
+
+// New creates a new Store with options and checks the connection to Redis. Options for the
+// embedder and the url must be set. Indexes are created when documents are first added to
+// them, since their schema depends on the dimensions of the embeddings.
+func New(ctx context.Context, opts ...Option) (Store, error) {
+	s, err := applyClientOptions(opts...)
+	if err != nil {
+		return Store{}, err
+	}
+
+	clientOptions, err := goredis.ParseURL(s.url)
+	if err != nil {
+		return Store{}, fmt.Errorf("%w: %s", ErrInvalidOptions, err.Error())
+	}
+	// The replies of RediSearch are parsed in their RESP2 form.
+	clientOptions.Protocol = 2
+
+	s.client = goredis.NewClient(clientOptions)
+	if err := s.client.Ping(ctx).Err(); err != nil {
+		s.client.Close()
+		return Store{}, err
+	}
+
+	return s, nil
+}
+
+// AddDocuments creates vector embeddings from the documents using the embedder and stores
+// them under random ids.
+func (s Store) AddDocuments(ctx context.Context, docs []schema.Document, options ...vectorstores.Option) error {
+	ids := make([]string, 0, len(docs))
+	for range docs {
+		ids = append(ids, uuid.New().String())
+	}
+	return s.storeDocuments(ctx, ids, docs, options...)
+}
+
+// UpsertDocuments creates vector embeddings from the documents using the embedder and
+// stores them under the given ids, replacing the documents with the same ids.
+func (s Store) UpsertDocuments(
+	ctx context.Context, ids []string, docs []schema.Document, options ...vectorstores.Option,
+) error {
+	if err := vectorstores.CheckIDs(ids, docs); err != nil {
+		return err
+	}
+	return s.storeDocuments(ctx, ids, docs, options...)
+}
+
+func (s Store) storeDocuments(
+	ctx context.Context, ids []string, docs []schema.Document, options ...vectorstores.Option,
+) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	opts := s.getOptions(options...)
+
+	texts := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		texts = append(texts, doc.PageContent)
+	}
+	vectors, err := s.getEmbedder(opts).EmbedDocuments(ctx, texts)
+	if err != nil {
+		return err
+	}
+	if len(vectors) != len(docs) {
+		return ErrEmbedderWrongNumberVectors
+	}
+
+	index, err := s.getIndex(opts)
+	if err != nil {
+		return err
+	}
+	if err := s.createIndex(ctx, index, len(vectors[0])); err != nil {
+		return err
+	}
+
+	for start := 0; start < len(docs); start += _batchSize {
+		end := start + _batchSize
+		if end > len(docs) {
+			end = len(docs)
+		}
+
+		_, err := s.client.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+			for i := start; i < end; i++ {
+				fields, err := s.hashFields(docs[i], vectors[i])
+				if err != nil {
+					return err
+				}
+				// Deleting the hash first removes the fields of metadata keys the document lost.
+				pipe.Del(ctx, key(index, ids[i]))
+				pipe.HSet(ctx, key(index, ids[i]), fields...)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// hashFields returns the names and values of the fields of the hash storing a document.
+func (s Store) hashFields(doc schema.Document, vector []float32) ([]any, error) {
+	metadata, err := json.Marshal(doc.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := []any{
+		_contentField, doc.PageContent,
+		_metadataField, metadata,
+		_embeddingField, encodeVector(vector),
+	}
+	for _, k := range s.tagFields {
+		if value, ok := doc.Metadata[k]; ok {
+			fields = append(fields, fieldName(k), tagValue(value))
+		}
+	}
+	for _, k := range s.numericFields {
+		// Values that are not numbers are not indexed.
+		if value, err := numericValue(k, doc.Metadata[k]); err == nil {
+			fields = append(fields, fieldName(k), value)
+		}
+	}
+	return fields, nil
+}
+
+// SimilaritySearch creates a vector embedding from the query using the embedder and
+// returns the most similar documents, with their relevance score. Filters are a
+// filter.Filter or a RediSearch query, and are applied during the search.
+func (s Store) SimilaritySearch(
+	ctx context.Context, query string, numDocuments int, options ...vectorstores.Option,
+) ([]schema.Document, error) {
+	opts := s.getOptions(options...)
+	if opts.ScoreThreshold < 0 || opts.ScoreThreshold > 1 {
+		return nil, ErrInvalidScoreThreshold
+	}
+	filterQuery, err := s.getFilterQuery(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	vector, err := s.getEmbedder(opts).EmbedQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := numDocuments
+	if opts.MMR != nil {
+		if err := opts.MMR.Validate(); err != nil {
+			return nil, err
+		}
+		limit = opts.MMR.GetFetchK(numDocuments)
+	}
+
+	returnFields := []any{_contentField, _metadataField, _scoreField}
+	if opts.MMR != nil {
+		returnFields = append(returnFields, _embeddingField)
+	}
+	if filterQuery != "*" {
+		filterQuery = "(" + filterQuery + ")"
+	}
+	index, err := s.getIndex(opts)
+	if err != nil {
+		return nil, err
+	}
+	command := append([]any{
+		"FT.SEARCH", index,
+		fmt.Sprintf("%s=>[KNN %d @%s $vector AS %s]", filterQuery, limit, _embeddingField, _scoreField),
+		"PARAMS", 2, "vector", encodeVector(vector),
+		"SORTBY", _scoreField, "ASC",
+		"RETURN", len(returnFields),
+	}, returnFields...)
+	command = append(command, "LIMIT", 0, limit, "DIALECT", 2)
+
+	reply, err := s.client.Do(ctx, command...).Result()
+	if err != nil {
+		return nil, err
+	}
+	results, err := parseSearchReply(reply)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make([]schema.Document, 0, len(results))
+	vectors := make([][]float32, 0, len(results))
+	for _, result := range results {
+		doc, err := s.documentFromFields(result.fields)
+		if err != nil {
+			return nil, err
+		}
+		if doc.Score >= opts.ScoreThreshold {
+			docs = append(docs, doc)
+			vectors = append(vectors, decodeVector(result.fields[_embeddingField]))
+		}
+	}
+
+	if opts.MMR == nil {
+		return docs, nil
+	}
+	return vectorstores.MaximalMarginalRelevanceDocuments(vector, docs, vectors, opts.MMR.Lambda, numDocuments), nil
+}
+
+// documentFromFields returns the document stored in the fields of a hash, with the
+// relevance score of its distance if it has one.
+func (s Store) documentFromFields(fields map[string]string) (schema.Document, error) {
+	doc := schema.Document{PageContent: fields[_contentField]}
+	if err := json.Unmarshal([]byte(fields[_metadataField]), &doc.Metadata); err != nil {
+		return schema.Document{}, err
+	}
+
+	if score, ok := fields[_scoreField]; ok {
+		distance, err := strconv.ParseFloat(score, 32)
+		if err != nil {
+			return schema.Document{}, fmt.Errorf("%w: score %q", ErrUnexpectedReply, score)
+		}
+		doc.Score = s.relevance(float32(distance))
+	}
+	return doc, nil
+}
+
+// DeleteDocumentsByID deletes the documents with the given ids.
+func (s Store) DeleteDocumentsByID(ctx context.Context, ids []string, options ...vectorstores.Option) error {
+	index, err := s.getIndex(s.getOptions(options...))
+	if err != nil {
+		return err
+	}
+
+	for start := 0; start < len(ids); start += _batchSize {
+		end := start + _batchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		keys := make([]string, 0, end-start)
+		for _, id := range ids[start:end] {
+			keys = append(keys, key(index, id))
+		}
+		if err := s.client.Del(ctx, keys...).Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteDocuments deletes the documents matching the filters. All the documents of the
+// index are deleted if no filters are given and vectorstores.WithDeleteAll is set.
+func (s Store) DeleteDocuments(ctx context.Context, options ...vectorstores.Option) error {
+	opts := s.getOptions(options...)
+	if err := vectorstores.CheckDeleteFilters(opts); err != nil {
+		return err
+	}
+	filterQuery, err := s.getFilterQuery(opts)
+	if err != nil {
+		return err
+	}
+
+	index, err := s.getIndex(opts)
+	if err != nil {
+		return err
+	}
+	exists, err := s.indexExists(ctx, index)
+	if err != nil || !exists {
+		return err
+	}
+
+	// Deleted documents leave the index, so searching again returns the next ones.
+	for {
+		reply, err := s.client.Do(ctx, "FT.SEARCH", index, filterQuery, "NOCONTENT",
+			"LIMIT", 0, _batchSize, "DIALECT", 2).Result()
+		if err != nil {
+			return err
+		}
+		results, err := parseSearchReply(reply)
+		if err != nil {
+			return err
+		}
+		if len(results) == 0 {
+			return nil
+		}
+
+		keys := make([]string, 0, len(results))
+		for _, result := range results {
+			keys = append(keys, result.key)
+		}
+		if err := s.client.Del(ctx, keys...).Err(); err != nil {
+			return err
+		}
+	}
+}
+
+// GetDocumentsByID returns the documents with the given ids.
+func (s Store) GetDocumentsByID(
+	ctx context.Context, ids []string, options ...vectorstores.Option,
+) (map[string]schema.Document, error) {
+	index, err := s.getIndex(s.getOptions(options...))
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make(map[string]schema.Document, len(ids))
+	for start := 0; start < len(ids); start += _batchSize {
+		end := start + _batchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		replies := make([]*goredis.SliceCmd, 0, end-start)
+		_, err := s.client.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+			for _, id := range ids[start:end] {
+				replies = append(replies, pipe.HMGet(ctx, key(index, id), _contentField, _metadataField))
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for i, reply := range replies {
+			values := reply.Val()
+			if len(values) != 2 {
+				return nil, fmt.Errorf("%w: %v", ErrUnexpectedReply, values)
+			}
+			content, found := values[0].(string)
+			if !found {
+				continue
+			}
+			metadata, _ := values[1].(string)
+			doc, err := s.documentFromFields(map[string]string{_contentField: content, _metadataField: metadata})
+			if err != nil {
+				return nil, err
+			}
+			docs[ids[start+i]] = doc
+		}
+	}
+	return docs, nil
+}
+
+// RemoveIndex deletes the index of the name space set with vectorstores.WithNameSpace, or
+// the index of the store if none is set, and its documents.
+func (s Store) RemoveIndex(ctx context.Context, options ...vectorstores.Option) error {
+	index, err := s.getIndex(s.getOptions(options...))
+	if err != nil {
+		return err
+	}
+	exists, err := s.indexExists(ctx, index)
+	if err != nil || !exists {
+		return err
+	}
+	return s.client.Do(ctx, "FT.DROPINDEX", index, "DD").Err()
+}
+
+// Close closes the connections to Redis.
+func (s Store) Close() error {
+	return s.client.Close()
+}
+
+// createIndex creates the index of the documents of a name space if it does not exist.
+func (s Store) createIndex(ctx context.Context, index string, dimensions int) error {
+	exists, err := s.indexExists(ctx, index)
+	if err != nil || exists {
+		return err
+	}
+
+	command := []any{
+		"FT.CREATE", index, "ON", "HASH", "PREFIX", 1, key(index, ""),
+		"SCHEMA",
+		_contentField, "TEXT",
+		_embeddingField, "VECTOR", string(s.algorithm), 6,
+		"TYPE", "FLOAT32", "DIM", dimensions, "DISTANCE_METRIC", string(s.distanceMetric),
+	}
+	for _, k := range s.tagFields {
+		command = append(command, fieldName(k), "TAG")
+	}
+	for _, k := range s.numericFields {
+		command = append(command, fieldName(k), "NUMERIC")
+	}
+
+	return s.client.Do(ctx, command...).Err()
+}
+
+// indexExists reports whether the index exists.
+func (s Store) indexExists(ctx context.Context, index string) (bool, error) {
+	names, err := s.client.Do(ctx, "FT._LIST").StringSlice()
+	if err != nil {
+		return false, err
+	}
+	return slices.Contains(names, index), nil
+}
+
+// relevance returns the relevance score of a distance returned by RediSearch. The L2
+// distance is the squared Euclidean distance.
+func (s Store) relevance(distance float32) float32 {
+	if s.distanceMetric == DistanceL2 {
+		return vectorstores.RelevanceFromSquaredL2Distance(distance)
+	}
+	// The inner product distance is 1 minus the inner product, like the cosine distance.
+	return vectorstores.RelevanceFromCosineDistance(distance)
+}
+
+// getFilterQuery returns the filters of the options as a RediSearch query, matching all
+// documents if there are no filters.
+func (s Store) getFilterQuery(opts vectorstores.Options) (string, error) {
+	switch filters := opts.Filters.(type) {
+	case nil:
+		return "*", nil
+	case filter.Filter:
+		return s.queryFromFilter(filters)
+	case string:
+		return filters, nil
+	default:
+		return "", fmt.Errorf("%w: %T", ErrInvalidFilter, filters)
+	}
+}
+
+// getIndex returns the index of the name space of the options.
+func (s Store) getIndex(opts vectorstores.Options) (string, error) {
+	if opts.NameSpace == "" {
+		return s.indexName, nil
+	}
+	if strings.Contains(opts.NameSpace, ":") {
+		return "", fmt.Errorf("%w: %q contains a ':'", ErrInvalidNameSpace, opts.NameSpace)
+	}
+	return s.indexName + "_" + opts.NameSpace, nil
+}
+
+func (s Store) getOptions(options ...vectorstores.Option) vectorstores.Options {
+	opts := vectorstores.Options{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	return opts
+}
+
+func (s Store) getEmbedder(opts vectorstores.Options) embeddings.Embedder {
+	if opts.Embedder != nil {
+		return opts.Embedder
+	}
+	return s.embedder
+}
+
+// key returns the key of the hash of a document of an index.
+func key(index, id string) string {
+	return index + ":" + id
+}
+
+// searchResult is a document returned by FT.SEARCH.
+type searchResult struct {
+	key    string
+	fields map[string]string
+}
+
+// parseSearchReply parses the reply of FT.SEARCH: the number of results followed by the
+// key of each document and, unless NOCONTENT is given, its fields.
+func parseSearchReply(reply any) ([]searchResult, error) {
+	values, ok := reply.([]any)
+	if !ok || len(values) == 0 {
+		return nil, fmt.Errorf("%w: %v", ErrUnexpectedReply, reply)
+	}
+	values = values[1:]
+
+	results := make([]searchResult, 0, len(values))
+	for i := 0; i < len(values); i++ {
+		k, ok := values[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: key %v", ErrUnexpectedReply, values[i])
+		}
+		result := searchResult{key: k, fields: map[string]string{}}
+
+		if i+1 < len(values) {
+			if fields, ok := values[i+1].([]any); ok {
+				for j := 0; j+1 < len(fields); j += 2 {
+					name, _ := fields[j].(string)
+					value, _ := fields[j+1].(string)
+					result.fields[name] = value
+				}
+				i++
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// encodeVector returns the little-endian float32 encoding of a vector.
+func encodeVector(vector []float32) []byte {
+	encoded := make([]byte, 4*len(vector))
+	for i, v := range vector {
+		binary.LittleEndian.PutUint32(encoded[4*i:], math.Float32bits(v))
+	}
+	return encoded
+}
+
+// decodeVector decodes a little-endian float32 encoding of a vector.
+func decodeVector(encoded string) []float32 {
+	b := []byte(encoded)
+	vector := make([]float32, len(b)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(b[4*i:]))
+	}
+	return vector
+}
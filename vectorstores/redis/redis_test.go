@@ -0,0 +1,148 @@
+package redis_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+	"github.com/tmc/langchaingo/vectorstores/filter"
+	"github.com/tmc/langchaingo/vectorstores/internal/vectorstoretest"
+	"github.com/tmc/langchaingo/vectorstores/redis"
+)
+
+func newTestStore(t *testing.T, opts ...redis.Option) redis.Store {
+	t.Helper()
+
+	// export LANGCHAINGO_TEST_REDIS=redis://localhost:6379
+	url := os.Getenv("LANGCHAINGO_TEST_REDIS")
+	if url == "" {
+		t.Skip("LANGCHAINGO_TEST_REDIS not set")
+	}
+
+	opts = append([]redis.Option{
+		redis.WithURL(url),
+		redis.WithEmbedder(vectorstoretest.WordEmbedder{}),
+		redis.WithIndexName(fmt.Sprintf("test_%s", strings.ReplaceAll(uuid.New().String(), "-", ""))),
+		redis.WithTagFields("country"),
+		redis.WithNumericFields("population"),
+	}, opts...)
+	s, err := redis.New(context.Background(), opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, s.RemoveIndex(context.Background()))
+		require.NoError(t, s.RemoveIndex(context.Background(), vectorstores.WithNameSpace("ns")))
+		require.NoError(t, s.Close())
+	})
+	return s
+}
+
+func TestRedisStore(t *testing.T) {
+	t.Parallel()
+
+	s := newTestStore(t)
+
+	err := s.AddDocuments(context.Background(), []schema.Document{
+		{PageContent: "tokyo japan", Metadata: map[string]any{"country": "japan", "population": 14}},
+		{PageContent: "kyoto japan", Metadata: map[string]any{"country": "japan", "population": 1.5}},
+		{PageContent: "paris france", Metadata: map[string]any{"country": "france", "population": 2.1}},
+		{PageContent: "potato"},
+	})
+	require.NoError(t, err)
+
+	docs, err := s.SimilaritySearch(context.Background(), "tokyo", 1)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Equal(t, "tokyo japan", docs[0].PageContent)
+	require.Equal(t, "japan", docs[0].Metadata["country"])
+	require.InDelta(t, 0.7, docs[0].Score, 0.01)
+
+	docs, err = s.SimilaritySearch(context.Background(), "japan", 4, vectorstores.WithScoreThreshold(0.5))
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+
+	docs, err = s.SimilaritySearch(context.Background(), "japan", 4,
+		vectorstores.WithFilters(filter.And(filter.Eq("country", "japan"), filter.Lt("population", 10))))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Equal(t, "kyoto japan", docs[0].PageContent)
+
+	docs, err = s.SimilaritySearch(context.Background(), "japan", 4,
+		vectorstores.WithFilters(filter.Not(filter.Eq("country", "japan"))))
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+
+	docs, err = s.SimilaritySearch(context.Background(), "japan", 4, vectorstores.WithMMR(4, 0.5))
+	require.NoError(t, err)
+	require.Len(t, docs, 4)
+
+	// Name spaces are separate indexes.
+	err = s.AddDocuments(context.Background(), []schema.Document{{PageContent: "paris"}},
+		vectorstores.WithNameSpace("ns"))
+	require.NoError(t, err)
+	docs, err = s.SimilaritySearch(context.Background(), "japan", 10, vectorstores.WithNameSpace("ns"))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+}
+
+func TestRedisStoreUpsertDeleteAndGet(t *testing.T) {
+	t.Parallel()
+
+	s := newTestStore(t, redis.WithDistanceMetric(redis.DistanceL2), redis.WithAlgorithm(redis.AlgorithmFlat))
+
+	ids := []string{"city", "vegetable"}
+	err := s.UpsertDocuments(context.Background(), ids, []schema.Document{
+		{PageContent: "tokyo", Metadata: map[string]any{"country": "japan"}},
+		{PageContent: "potato", Metadata: map[string]any{"country": "peru"}},
+	})
+	require.NoError(t, err)
+
+	// Upserting replaces the document with the same id.
+	err = s.UpsertDocuments(context.Background(), ids[:1], []schema.Document{
+		{PageContent: "kyoto", Metadata: map[string]any{"country": "japan"}},
+	})
+	require.NoError(t, err)
+
+	docs, err := s.GetDocumentsByID(context.Background(), append(ids, "missing"))
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	require.Equal(t, "kyoto", docs["city"].PageContent)
+
+	require.NoError(t, s.DeleteDocumentsByID(context.Background(), ids[:1]))
+	require.ErrorIs(t, s.DeleteDocuments(context.Background()), vectorstores.ErrDeleteWithoutFilters)
+	require.NoError(t, s.DeleteDocuments(context.Background(),
+		vectorstores.WithFilters(filter.Eq("country", "peru"))))
+
+	docs, err = s.GetDocumentsByID(context.Background(), ids)
+	require.NoError(t, err)
+	require.Empty(t, docs)
+}
+
+func TestNewWithInvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	_, err := redis.New(context.Background(), redis.WithURL("redis://localhost:6379"))
+	require.ErrorIs(t, err, redis.ErrInvalidOptions)
+
+	_, err = redis.New(context.Background(),
+		redis.WithURL("http://localhost:6379"),
+		redis.WithEmbedder(vectorstoretest.WordEmbedder{}))
+	require.ErrorIs(t, err, redis.ErrInvalidOptions)
+
+	_, err = redis.New(context.Background(),
+		redis.WithURL("redis://localhost:6379"),
+		redis.WithEmbedder(vectorstoretest.WordEmbedder{}),
+		redis.WithTagFields("country code"))
+	require.ErrorIs(t, err, redis.ErrInvalidOptions)
+
+	_, err = redis.New(context.Background(),
+		redis.WithURL("redis://localhost:6379"),
+		redis.WithEmbedder(vectorstoretest.WordEmbedder{}),
+		redis.WithIndexName("a:b"))
+	require.ErrorIs(t, err, redis.ErrInvalidOptions)
+}
@@ -0,0 +1,60 @@
+package redis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+func TestParseSearchReply(t *testing.T) {
+	t.Parallel()
+
+	results, err := parseSearchReply([]any{
+		int64(2),
+		"idx:1", []any{"content", "tokyo", "score", "0.1"},
+		"idx:2", []any{"content", "paris"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []searchResult{
+		{key: "idx:1", fields: map[string]string{"content": "tokyo", "score": "0.1"}},
+		{key: "idx:2", fields: map[string]string{"content": "paris"}},
+	}, results)
+
+	// Replies to NOCONTENT searches only have keys.
+	results, err = parseSearchReply([]any{int64(2), "idx:1", "idx:2"})
+	require.NoError(t, err)
+	assert.Equal(t, []searchResult{
+		{key: "idx:1", fields: map[string]string{}},
+		{key: "idx:2", fields: map[string]string{}},
+	}, results)
+
+	_, err = parseSearchReply("OK")
+	require.ErrorIs(t, err, ErrUnexpectedReply)
+}
+
+func TestEncodeVector(t *testing.T) {
+	t.Parallel()
+
+	vector := []float32{1, -0.5, 0.25}
+	assert.Equal(t, vector, decodeVector(string(encodeVector(vector))))
+}
+
+func TestGetIndex(t *testing.T) {
+	t.Parallel()
+
+	s := Store{indexName: "langchaingo"}
+
+	index, err := s.getIndex(vectorstores.Options{})
+	require.NoError(t, err)
+	assert.Equal(t, "langchaingo", index)
+
+	index, err = s.getIndex(vectorstores.Options{NameSpace: "ns"})
+	require.NoError(t, err)
+	assert.Equal(t, "langchaingo_ns", index)
+
+	// The key prefix of "a:b" would contain the keys of "a".
+	_, err = s.getIndex(vectorstores.Options{NameSpace: "a:b"})
+	assert.ErrorIs(t, err, ErrInvalidNameSpace)
+}
@@ -0,0 +1,33 @@
+package vectorstores
+
+// RelevanceFromSimilarity returns the relevance score of a cosine or dot product similarity.
+func RelevanceFromSimilarity(similarity float32) float32 {
+	return clampScore(similarity)
+}
+
+// RelevanceFromCosineDistance returns the relevance score of a cosine distance, 1 minus the
+// cosine similarity.
+func RelevanceFromCosineDistance(distance float32) float32 {
+	return clampScore(1 - distance)
+}
+
+// RelevanceFromL2Distance returns the relevance score of a Euclidean distance.
+func RelevanceFromL2Distance(distance float32) float32 {
+	return clampScore(1 - distance*distance/2)
+}
+
+// RelevanceFromSquaredL2Distance returns the relevance score of a squared Euclidean distance.
+func RelevanceFromSquaredL2Distance(distance float32) float32 {
+	return clampScore(1 - distance/2)
+}
+
+func clampScore(score float32) float32 {
+	switch {
+	case score < 0:
+		return 0
+	case score > 1:
+		return 1
+	default:
+		return score
+	}
+}
@@ -0,0 +1,24 @@
+package vectorstores
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelevance(t *testing.T) {
+	t.Parallel()
+
+	assert.InDelta(t, 0.8, RelevanceFromSimilarity(0.8), 1e-6)
+	assert.InDelta(t, 0, RelevanceFromSimilarity(-0.5), 1e-6)
+	assert.InDelta(t, 1, RelevanceFromSimilarity(1.2), 1e-6)
+
+	assert.InDelta(t, 0.8, RelevanceFromCosineDistance(0.2), 1e-6)
+	assert.InDelta(t, 0, RelevanceFromCosineDistance(1.5), 1e-6)
+
+	// The unit vectors (1, 0) and (0.8, 0.6) have a cosine similarity of 0.8, a squared
+	// Euclidean distance of 0.4 and a Euclidean distance of sqrt(0.4).
+	assert.InDelta(t, 0.8, RelevanceFromSquaredL2Distance(0.4), 1e-6)
+	assert.InDelta(t, 0.8, RelevanceFromL2Distance(0.63245553), 1e-6)
+	assert.InDelta(t, 0, RelevanceFromL2Distance(2), 1e-6)
+}
@@ -0,0 +1,6 @@
+// Package sqlite3 contains an implementation of the vectorStore interface using a SQLite
+// database, so documents can be stored locally in a single file. Each collection is a
+// table storing the text, the metadata, as JSON, and the embedding of the documents.
+// Searches are exact: they compare the query with every document of the collection, and
+// evaluate the filters in process.
+package sqlite3
@@ -0,0 +1,89 @@
+package sqlite3
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/tmc/langchaingo/embeddings"
+)
+
+const (
+	_defaultCollectionName = "langchaingo_documents"
+	_defaultBlockSize      = 1000
+)
+
+// _sqlIdentifier matches the collection names that can be used as table names.
+var _sqlIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`) //nolint:gochecknoglobals
+
+// ErrInvalidOptions is returned when the options given are invalid.
+var ErrInvalidOptions = errors.New("invalid options")
+
+// Option is a function type that can be used to modify the client.
+type Option func(s *Store)
+
+// WithEmbedder is an option for setting the embedder to use. Must be set.
+func WithEmbedder(e embeddings.Embedder) Option {
+	return func(s *Store) {
+		s.embedder = e
+	}
+}
+
+// WithPath is an option for setting the path of the SQLite file, or any data source name
+// of the github.com/mattn/go-sqlite3 driver. Either the path or the database must be set.
+func WithPath(path string) Option {
+	return func(s *Store) {
+		s.path = path
+	}
+}
+
+// WithDB is an option for using an open database, opened with the sqlite3 driver. The
+// store does not close it.
+func WithDB(db *sql.DB) Option {
+	return func(s *Store) {
+		s.db = db
+	}
+}
+
+// WithCollectionName is an option for setting the name of the collection, the table the
+// documents are stored in. Defaults to "langchaingo_documents".
+func WithCollectionName(name string) Option {
+	return func(s *Store) {
+		s.collectionName = name
+	}
+}
+
+// WithBlockSize is an option for setting the number of documents read at once during a
+// search, bounding the memory used by searches in large collections. Defaults to 1000.
+func WithBlockSize(blockSize int) Option {
+	return func(s *Store) {
+		s.blockSize = blockSize
+	}
+}
+
+func applyClientOptions(opts ...Option) (Store, error) {
+	s := Store{
+		collectionName: _defaultCollectionName,
+		blockSize:      _defaultBlockSize,
+	}
+
+	for _, opt := range opts {
+		opt(&s)
+	}
+
+	if s.embedder == nil {
+		return Store{}, fmt.Errorf("%w: missing embedder", ErrInvalidOptions)
+	}
+	if s.db == nil && s.path == "" {
+		return Store{}, fmt.Errorf("%w: missing path or database", ErrInvalidOptions)
+	}
+	if !_sqlIdentifier.MatchString(s.collectionName) {
+		return Store{}, fmt.Errorf("%w: invalid collection name %q", ErrInvalidOptions, s.collectionName)
+	}
+	if s.blockSize <= 0 {
+		return Store{}, fmt.Errorf("%w: block size must be positive", ErrInvalidOptions)
+	}
+
+	return s, nil
+}
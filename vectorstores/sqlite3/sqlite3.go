@@ -0,0 +1,530 @@
+package sqlite3
+
+import (
+	"container/heap"
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	_ "github.com/mattn/go-sqlite3" // sqlite3 driver
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+	"github.com/tmc/langchaingo/vectorstores/filter"
+)
+
+const (
+	_driverName = "sqlite3"
+	// _maxSQLiteVariables is the number of values bound in a single statement, below the
+	// default limit of SQLite.
+	_maxSQLiteVariables = 500
+)
+
+var (
+	// ErrEmbedderWrongNumberVectors is returned when if the embedder returns a number
+	// of vectors that is not equal to the number of documents given.
+	ErrEmbedderWrongNumberVectors = errors.New(
+		"number of vectors from embedder does not match number of documents",
+	)
+	// ErrInvalidScoreThreshold is returned when the score threshold is not between 0 and 1.
+	ErrInvalidScoreThreshold = errors.New("score threshold must be between 0 and 1")
+	// ErrUnsupportedOptions is returned when an option is not supported by the store.
+	ErrUnsupportedOptions = errors.New("unsupported options")
+	// ErrInvalidFilter is returned when the filters are neither a filter.Filter nor a
+	// map[string]any.
+	ErrInvalidFilter = errors.New("invalid filter")
+	// ErrInvalidEmbedding is returned when an embedding read from the database is invalid.
+	ErrInvalidEmbedding = errors.New("invalid embedding")
+)
+
+// Store is a vector store storing the documents of a collection, with their metadata and
+// embeddings, in a table of a SQLite database. Searches compute the similarity of the
+// query with every document of the collection, reading the documents by blocks.
+type Store struct {
+	embedder embeddings.Embedder
+	db       *sql.DB
+	ownsDB   bool
+
+	path           string
+	collectionName string
+	blockSize      int
+}
+
+var (
+	_ vectorstores.VectorStore   = Store{}
+	_ vectorstores.Upserter      = Store{}
+	_ vectorstores.IDDeleter     = Store{}
+	_ vectorstores.FilterDeleter = Store{}
+	_ vectorstores.Getter        = Store{}
+)
+
+// New creates a new Store with options. The table of the collection is created if it does
+// not exist. Options for the embedder and the path or database must be set.
+func New(ctx context.Context, opts ...Option) (Store, error) {
+	s, err := applyClientOptions(opts...)
+	if err != nil {
+		return Store{}, err
+	}
+
+	if s.db == nil {
+		s.db, err = sql.Open(_driverName, s.path)
+		if err != nil {
+			return Store{}, err
+		}
+		s.db.SetMaxOpenConns(1)
+		s.ownsDB = true
+	}
+
+	_, err = s.db.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	id TEXT PRIMARY KEY,
+	document TEXT NOT NULL,
+	metadata TEXT NOT NULL,
+	embedding BLOB NOT NULL
+)`, s.collectionName))
+	if err != nil {
+		if s.ownsDB {
+			s.db.Close()
+		}
+		return Store{}, err
+	}
+
+	return s, nil
+}
+
+// AddDocuments creates vector embeddings from the documents using the embedder and
+// inserts them in the collection under random ids.
+func (s Store) AddDocuments(ctx context.Context, docs []schema.Document, options ...vectorstores.Option) error {
+	ids := make([]string, 0, len(docs))
+	for range docs {
+		ids = append(ids, uuid.New().String())
+	}
+	return s.insertDocuments(ctx, ids, docs, false, options...)
+}
+
+// UpsertDocuments creates vector embeddings from the documents using the embedder and
+// stores them under the given ids, replacing the documents with the same ids.
+func (s Store) UpsertDocuments(
+	ctx context.Context, ids []string, docs []schema.Document, options ...vectorstores.Option,
+) error {
+	if err := vectorstores.CheckIDs(ids, docs); err != nil {
+		return err
+	}
+	return s.insertDocuments(ctx, ids, docs, true, options...)
+}
+
+func (s Store) insertDocuments(
+	ctx context.Context, ids []string, docs []schema.Document, upsert bool, options ...vectorstores.Option,
+) error {
+	opts, err := s.getOptions(options...)
+	if err != nil {
+		return err
+	}
+
+	texts := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		texts = append(texts, doc.PageContent)
+	}
+	vectors, err := s.getEmbedder(opts).EmbedDocuments(ctx, texts)
+	if err != nil {
+		return err
+	}
+	if len(vectors) != len(docs) {
+		return ErrEmbedderWrongNumberVectors
+	}
+
+	insert := "INSERT"
+	if upsert {
+		insert = "INSERT OR REPLACE"
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	statement, err := tx.PrepareContext(ctx, fmt.Sprintf(
+		"%s INTO %s (id, document, metadata, embedding) VALUES (?, ?, ?, ?)", insert, s.collectionName))
+	if err != nil {
+		return err
+	}
+	defer statement.Close()
+
+	for i, doc := range docs {
+		metadata, err := json.Marshal(doc.Metadata)
+		if err != nil {
+			return err
+		}
+		if doc.Metadata == nil {
+			metadata = []byte("{}")
+		}
+		if _, err := statement.ExecContext(ctx, ids[i], doc.PageContent, string(metadata),
+			encodeEmbedding(vectors[i])); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// SimilaritySearch creates a vector embedding from the query using the embedder and
+// returns the documents of the collection most similar to it, by cosine similarity, with
+// their relevance score. Filters are a filter.Filter or a map[string]any of metadata
+// values the documents must be equal to.
+func (s Store) SimilaritySearch(
+	ctx context.Context, query string, numDocuments int, options ...vectorstores.Option,
+) ([]schema.Document, error) {
+	opts, err := s.getOptions(options...)
+	if err != nil {
+		return nil, err
+	}
+	if opts.ScoreThreshold < 0 || opts.ScoreThreshold > 1 {
+		return nil, ErrInvalidScoreThreshold
+	}
+	match, err := getMatcher(opts.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	vector, err := s.getEmbedder(opts).EmbedQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := numDocuments
+	if opts.MMR != nil {
+		if err := opts.MMR.Validate(); err != nil {
+			return nil, err
+		}
+		limit = opts.MMR.GetFetchK(numDocuments)
+	}
+
+	var candidates candidateHeap
+	err = s.scan(ctx, s.db, func(_ string, doc schema.Document, embedding []float32) {
+		if !match(doc.Metadata) {
+			return
+		}
+		similarity := vectorstores.CosineSimilarity(vector, embedding)
+		doc.Score = vectorstores.RelevanceFromSimilarity(float32(similarity))
+		if doc.Score < opts.ScoreThreshold {
+			return
+		}
+		heap.Push(&candidates, candidate{doc: doc, embedding: embedding, similarity: similarity})
+		if candidates.Len() > limit {
+			heap.Pop(&candidates)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The heap holds the least similar candidate first.
+	sort.Sort(sort.Reverse(candidates))
+	docs := make([]schema.Document, 0, len(candidates))
+	vectors := make([][]float32, 0, len(candidates))
+	for _, c := range candidates {
+		docs = append(docs, c.doc)
+		vectors = append(vectors, c.embedding)
+	}
+
+	if opts.MMR == nil {
+		return docs, nil
+	}
+	return vectorstores.MaximalMarginalRelevanceDocuments(vector, docs, vectors, opts.MMR.Lambda, numDocuments), nil
+}
+
+// scan calls f with every document of the collection, reading them by blocks.
+func (s Store) scan(
+	ctx context.Context, db queryer, f func(id string, doc schema.Document, embedding []float32),
+) error {
+	query := fmt.Sprintf(`SELECT rowid, id, document, metadata, embedding FROM %s
+WHERE rowid > ? ORDER BY rowid LIMIT ?`, s.collectionName)
+
+	lastRowID := int64(math.MinInt64)
+	for {
+		numRows, err := s.scanBlock(ctx, db, query, &lastRowID, f)
+		if err != nil {
+			return err
+		}
+		if numRows < s.blockSize {
+			return nil
+		}
+	}
+}
+
+// scanBlock calls f with the documents of the block after lastRowID and updates it.
+func (s Store) scanBlock(
+	ctx context.Context, db queryer, query string, lastRowID *int64, f func(string, schema.Document, []float32),
+) (int, error) {
+	rows, err := db.QueryContext(ctx, query, *lastRowID, s.blockSize)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	numRows := 0
+	for rows.Next() {
+		var id string
+		var doc schema.Document
+		var metadata string
+		var embedding []byte
+		if err := rows.Scan(lastRowID, &id, &doc.PageContent, &metadata, &embedding); err != nil {
+			return 0, err
+		}
+		if err := json.Unmarshal([]byte(metadata), &doc.Metadata); err != nil {
+			return 0, err
+		}
+		vector, err := decodeEmbedding(embedding)
+		if err != nil {
+			return 0, err
+		}
+
+		f(id, doc, vector)
+		numRows++
+	}
+
+	return numRows, rows.Err()
+}
+
+// DeleteDocumentsByID deletes the documents with the given ids.
+func (s Store) DeleteDocumentsByID(ctx context.Context, ids []string, options ...vectorstores.Option) error {
+	if _, err := s.getOptions(options...); err != nil {
+		return err
+	}
+	return s.deleteByID(ctx, s.db, ids)
+}
+
+// deleteByID deletes the documents with the given ids, by batches.
+func (s Store) deleteByID(ctx context.Context, db execer, ids []string) error {
+	for start := 0; start < len(ids); start += _maxSQLiteVariables {
+		end := start + _maxSQLiteVariables
+		if end > len(ids) {
+			end = len(ids)
+		}
+		batch := ids[start:end]
+
+		args := make([]any, 0, len(batch))
+		for _, id := range batch {
+			args = append(args, id)
+		}
+		_, err := db.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE id IN (%s)",
+			s.collectionName, placeholders(len(batch))), args...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteDocuments deletes the documents matching the filters. All the documents of the
+// collection are deleted if no filters are given and vectorstores.WithDeleteAll is set.
+func (s Store) DeleteDocuments(ctx context.Context, options ...vectorstores.Option) error {
+	opts, err := s.getOptions(options...)
+	if err != nil {
+		return err
+	}
+	if err := vectorstores.CheckDeleteFilters(opts); err != nil {
+		return err
+	}
+	if opts.Filters == nil {
+		_, err := s.db.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s", s.collectionName))
+		return err
+	}
+
+	match, err := getMatcher(opts.Filters)
+	if err != nil {
+		return err
+	}
+
+	// The documents are found and deleted in a transaction, so that documents updated
+	// in between are not deleted based on their former metadata.
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	var ids []string
+	err = s.scan(ctx, tx, func(id string, doc schema.Document, _ []float32) {
+		if match(doc.Metadata) {
+			ids = append(ids, id)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.deleteByID(ctx, tx, ids); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetDocumentsByID returns the documents with the given ids.
+func (s Store) GetDocumentsByID(
+	ctx context.Context, ids []string, options ...vectorstores.Option,
+) (map[string]schema.Document, error) {
+	if _, err := s.getOptions(options...); err != nil {
+		return nil, err
+	}
+
+	docs := make(map[string]schema.Document, len(ids))
+	for start := 0; start < len(ids); start += _maxSQLiteVariables {
+		end := start + _maxSQLiteVariables
+		if end > len(ids) {
+			end = len(ids)
+		}
+		if err := s.getDocuments(ctx, ids[start:end], docs); err != nil {
+			return nil, err
+		}
+	}
+	return docs, nil
+}
+
+// getDocuments adds the documents with the given ids to docs.
+func (s Store) getDocuments(ctx context.Context, ids []string, docs map[string]schema.Document) error {
+	args := make([]any, 0, len(ids))
+	for _, id := range ids {
+		args = append(args, id)
+	}
+
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf("SELECT id, document, metadata FROM %s WHERE id IN (%s)",
+		s.collectionName, placeholders(len(ids))), args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, metadata string
+		var doc schema.Document
+		if err := rows.Scan(&id, &doc.PageContent, &metadata); err != nil {
+			return err
+		}
+		if err := json.Unmarshal([]byte(metadata), &doc.Metadata); err != nil {
+			return err
+		}
+		docs[id] = doc
+	}
+	return rows.Err()
+}
+
+// RemoveCollection drops the table of the collection.
+func (s Store) RemoveCollection(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, fmt.Sprintf("DROP TABLE IF EXISTS %s", s.collectionName))
+	return err
+}
+
+// Close closes the database if it was opened by the store.
+func (s Store) Close() error {
+	if !s.ownsDB {
+		return nil
+	}
+	return s.db.Close()
+}
+
+func (s Store) getOptions(options ...vectorstores.Option) (vectorstores.Options, error) {
+	opts := vectorstores.Options{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	if opts.NameSpace != "" {
+		return opts, fmt.Errorf("%w: name spaces, use a collection per name space", ErrUnsupportedOptions)
+	}
+	return opts, nil
+}
+
+func (s Store) getEmbedder(opts vectorstores.Options) embeddings.Embedder {
+	if opts.Embedder != nil {
+		return opts.Embedder
+	}
+	return s.embedder
+}
+
+// getMatcher returns a function reporting whether metadata matches the filters.
+func getMatcher(filters any) (func(map[string]any) bool, error) {
+	switch filters := filters.(type) {
+	case nil:
+		return func(map[string]any) bool { return true }, nil
+	case filter.Filter:
+		return filters.Match, nil
+	case map[string]any:
+		equalities := make([]filter.Filter, 0, len(filters))
+		for key, value := range filters {
+			equalities = append(equalities, filter.Eq(key, value))
+		}
+		return filter.And(equalities...).Match, nil
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrInvalidFilter, filters)
+	}
+}
+
+// placeholders returns n comma separated placeholders.
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// encodeEmbedding returns the little-endian float32 encoding of an embedding.
+func encodeEmbedding(embedding []float32) []byte {
+	encoded := make([]byte, 4*len(embedding))
+	for i, v := range embedding {
+		binary.LittleEndian.PutUint32(encoded[4*i:], math.Float32bits(v))
+	}
+	return encoded
+}
+
+// decodeEmbedding decodes a little-endian float32 encoding of an embedding.
+func decodeEmbedding(encoded []byte) ([]float32, error) {
+	if len(encoded)%4 != 0 {
+		return nil, fmt.Errorf("%w: %d bytes", ErrInvalidEmbedding, len(encoded))
+	}
+	embedding := make([]float32, len(encoded)/4)
+	for i := range embedding {
+		embedding[i] = math.Float32frombits(binary.LittleEndian.Uint32(encoded[4*i:]))
+	}
+	return embedding, nil
+}
+
+// execer is implemented by both *sql.DB and *sql.Tx.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// queryer is implemented by both *sql.DB and *sql.Tx.
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// candidate is a document found by a search, with its embedding and its similarity to
+// the query. Candidates are ranked by similarity, since the scores of the documents are
+// clamped and can be equal for different similarities.
+type candidate struct {
+	doc        schema.Document
+	embedding  []float32
+	similarity float64
+}
+
+// candidateHeap is a min-heap of candidates by similarity, keeping the best candidates
+// of a search.
+type candidateHeap []candidate
+
+func (h candidateHeap) Len() int           { return len(h) }
+func (h candidateHeap) Less(i, j int) bool { return h[i].similarity < h[j].similarity }
+func (h candidateHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *candidateHeap) Push(x any) {
+	*h = append(*h, x.(candidate)) //nolint:forcetypeassert
+}
+
+func (h *candidateHeap) Pop() any {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
@@ -0,0 +1,232 @@
+package sqlite3_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+	"github.com/tmc/langchaingo/vectorstores/filter"
+	"github.com/tmc/langchaingo/vectorstores/internal/vectorstoretest"
+	"github.com/tmc/langchaingo/vectorstores/sqlite3"
+)
+
+func newTestStore(t *testing.T, opts ...sqlite3.Option) sqlite3.Store {
+	t.Helper()
+
+	opts = append([]sqlite3.Option{
+		sqlite3.WithPath(filepath.Join(t.TempDir(), "documents.db")),
+		sqlite3.WithEmbedder(vectorstoretest.WordEmbedder{}),
+	}, opts...)
+	s, err := sqlite3.New(context.Background(), opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, s.Close())
+	})
+	return s
+}
+
+func TestSqlite3Store(t *testing.T) {
+	t.Parallel()
+
+	// A small block size makes searches read several blocks.
+	s := newTestStore(t, sqlite3.WithBlockSize(2))
+
+	err := s.AddDocuments(context.Background(), []schema.Document{
+		{PageContent: "tokyo japan", Metadata: map[string]any{"country": "japan", "population": 14}},
+		{PageContent: "kyoto japan", Metadata: map[string]any{"country": "japan", "population": 1.5}},
+		{PageContent: "paris france", Metadata: map[string]any{"country": "france", "population": 2.1}},
+		{PageContent: "potato"},
+	})
+	require.NoError(t, err)
+
+	docs, err := s.SimilaritySearch(context.Background(), "tokyo", 1)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Equal(t, "tokyo japan", docs[0].PageContent)
+	require.Equal(t, "japan", docs[0].Metadata["country"])
+	require.InDelta(t, 0.7, docs[0].Score, 0.01)
+
+	docs, err = s.SimilaritySearch(context.Background(), "japan", 4, vectorstores.WithScoreThreshold(0.5))
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	require.GreaterOrEqual(t, docs[0].Score, docs[1].Score)
+
+	docs, err = s.SimilaritySearch(context.Background(), "japan", 4,
+		vectorstores.WithFilters(filter.And(filter.Eq("country", "japan"), filter.Lt("population", 10))))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Equal(t, "kyoto japan", docs[0].PageContent)
+
+	docs, err = s.SimilaritySearch(context.Background(), "japan", 4,
+		vectorstores.WithFilters(map[string]any{"country": "france"}))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Equal(t, "paris france", docs[0].PageContent)
+
+	docs, err = s.SimilaritySearch(context.Background(), "japan", 4,
+		vectorstores.WithFilters(filter.Not(filter.Eq("country", "japan"))))
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+
+	_, err = s.SimilaritySearch(context.Background(), "japan", 4, vectorstores.WithFilters("country = 'japan'"))
+	require.ErrorIs(t, err, sqlite3.ErrInvalidFilter)
+
+	_, err = s.SimilaritySearch(context.Background(), "japan", 4, vectorstores.WithNameSpace("ns"))
+	require.ErrorIs(t, err, sqlite3.ErrUnsupportedOptions)
+}
+
+func TestSqlite3StoreUpsertDeleteAndGet(t *testing.T) {
+	t.Parallel()
+
+	s := newTestStore(t)
+
+	ids := []string{"city", "vegetable"}
+	err := s.UpsertDocuments(context.Background(), ids, []schema.Document{
+		{PageContent: "tokyo", Metadata: map[string]any{"country": "japan"}},
+		{PageContent: "potato", Metadata: map[string]any{"country": "peru"}},
+	})
+	require.NoError(t, err)
+
+	// Upserting replaces the document with the same id.
+	err = s.UpsertDocuments(context.Background(), ids[:1], []schema.Document{
+		{PageContent: "kyoto", Metadata: map[string]any{"country": "japan"}},
+	})
+	require.NoError(t, err)
+
+	docs, err := s.GetDocumentsByID(context.Background(), append(ids, "missing"))
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	require.Equal(t, "kyoto", docs["city"].PageContent)
+	require.Equal(t, "japan", docs["city"].Metadata["country"])
+
+	require.NoError(t, s.DeleteDocumentsByID(context.Background(), ids[:1]))
+	require.NoError(t, s.DeleteDocuments(context.Background(),
+		vectorstores.WithFilters(filter.Eq("country", "peru"))))
+
+	docs, err = s.GetDocumentsByID(context.Background(), ids)
+	require.NoError(t, err)
+	require.Empty(t, docs)
+
+	require.NoError(t, s.AddDocuments(context.Background(), []schema.Document{{PageContent: "paris"}}))
+	err = s.DeleteDocuments(context.Background())
+	require.ErrorIs(t, err, vectorstores.ErrDeleteWithoutFilters)
+	err = s.DeleteDocuments(context.Background(), vectorstores.WithFilters(filter.And()))
+	require.ErrorIs(t, err, vectorstores.ErrDeleteWithoutFilters)
+	found, err := s.SimilaritySearch(context.Background(), "paris", 1)
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+
+	require.NoError(t, s.DeleteDocuments(context.Background(), vectorstores.WithDeleteAll()))
+	found, err = s.SimilaritySearch(context.Background(), "paris", 1)
+	require.NoError(t, err)
+	require.Empty(t, found)
+}
+
+func TestSqlite3StorePersistence(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "documents.db")
+	s, err := sqlite3.New(context.Background(),
+		sqlite3.WithPath(path), sqlite3.WithEmbedder(vectorstoretest.WordEmbedder{}), sqlite3.WithCollectionName("cities"))
+	require.NoError(t, err)
+	require.NoError(t, s.AddDocuments(context.Background(), []schema.Document{{PageContent: "paris france"}}))
+	require.NoError(t, s.Close())
+
+	s, err = sqlite3.New(context.Background(),
+		sqlite3.WithPath(path), sqlite3.WithEmbedder(vectorstoretest.WordEmbedder{}), sqlite3.WithCollectionName("cities"))
+	require.NoError(t, err)
+	defer s.Close()
+
+	docs, err := s.SimilaritySearch(context.Background(), "paris", 1)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Equal(t, "paris france", docs[0].PageContent)
+
+	require.NoError(t, s.RemoveCollection(context.Background()))
+	_, err = s.SimilaritySearch(context.Background(), "paris", 1)
+	require.Error(t, err)
+}
+
+func TestSqlite3StoreMMR(t *testing.T) {
+	t.Parallel()
+
+	s := newTestStore(t)
+
+	err := s.AddDocuments(context.Background(), []schema.Document{
+		{PageContent: "tokyo japan"},
+		{PageContent: "tokyo japan japan"},
+		{PageContent: "kyoto japan"},
+	})
+	require.NoError(t, err)
+
+	docs, err := s.SimilaritySearch(context.Background(), "tokyo japan", 2, vectorstores.WithMMR(3, 0.3))
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	require.Equal(t, "kyoto japan", docs[1].PageContent)
+}
+
+// vectorEmbedder embeds the texts with the vectors of the map.
+type vectorEmbedder map[string][]float32
+
+func (e vectorEmbedder) EmbedDocuments(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, 0, len(texts))
+	for _, text := range texts {
+		vectors = append(vectors, e[text])
+	}
+	return vectors, nil
+}
+
+func (e vectorEmbedder) EmbedQuery(_ context.Context, text string) ([]float32, error) {
+	return e[text], nil
+}
+
+func TestSqlite3StoreNegativeSimilarities(t *testing.T) {
+	t.Parallel()
+
+	s := newTestStore(t, sqlite3.WithEmbedder(vectorEmbedder{
+		"query": {1, 0},
+		"close": {-0.1, 1},
+		"far":   {-1, 0.1},
+	}))
+
+	for _, text := range []string{"close", "far"} {
+		require.NoError(t, s.AddDocuments(context.Background(), []schema.Document{{PageContent: text}}))
+	}
+
+	// Both scores are clamped to 0, but the documents are still ranked by similarity.
+	docs, err := s.SimilaritySearch(context.Background(), "query", 2)
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	require.Equal(t, "close", docs[0].PageContent)
+	require.Equal(t, float32(0), docs[0].Score)
+
+	docs, err = s.SimilaritySearch(context.Background(), "query", 1)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Equal(t, "close", docs[0].PageContent)
+}
+
+func TestNewWithInvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	_, err := sqlite3.New(context.Background(), sqlite3.WithPath(":memory:"))
+	require.ErrorIs(t, err, sqlite3.ErrInvalidOptions)
+
+	_, err = sqlite3.New(context.Background(), sqlite3.WithEmbedder(vectorstoretest.WordEmbedder{}))
+	require.ErrorIs(t, err, sqlite3.ErrInvalidOptions)
+
+	_, err = sqlite3.New(context.Background(),
+		sqlite3.WithPath(":memory:"),
+		sqlite3.WithEmbedder(vectorstoretest.WordEmbedder{}),
+		sqlite3.WithCollectionName("documents; DROP TABLE users"))
+	require.ErrorIs(t, err, sqlite3.ErrInvalidOptions)
+
+	_, err = sqlite3.New(context.Background(),
+		sqlite3.WithPath(":memory:"),
+		sqlite3.WithEmbedder(vectorstoretest.WordEmbedder{}),
+		sqlite3.WithBlockSize(0))
+	require.ErrorIs(t, err, sqlite3.ErrInvalidOptions)
+}
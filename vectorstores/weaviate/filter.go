@@ -0,0 +1,130 @@
+package weaviate
+
+import (
+	"fmt"
+
+	"github.com/tmc/langchaingo/vectorstores/filter"
+	"github.com/weaviate/weaviate-go-client/v4/weaviate/filters"
+)
+
+// whereFromFilter translates a filter to a weaviate where filter. The filter is first
+// rewritten without negations, which weaviate lacks, and without in and not in comparisons,
+// which are expanded to equalities, then simplified so that no logical filter is sent
+// without operands. A filter matching all objects is translated to nil.
+func whereFromFilter(f filter.Filter) (*filters.WhereBuilder, error) {
+	f, err := filter.PushDownNot(f)
+	if err != nil {
+		return nil, err
+	}
+	if f, err = expandIn(f); err != nil {
+		return nil, err
+	}
+
+	f = filter.Simplify(f)
+	if filter.IsEmpty(f) {
+		if f.(filter.Logical).Operator == filter.OpOr { //nolint:forcetypeassert
+			return nil, fmt.Errorf("%w: empty or", filter.ErrUnsupportedFilter)
+		}
+		return nil, nil //nolint:nilnil
+	}
+	return translateFilter(f)
+}
+
+// expandIn rewrites in and not in comparisons as disjunctions of equalities and
+// conjunctions of inequalities.
+func expandIn(f filter.Filter) (filter.Filter, error) {
+	switch f := f.(type) {
+	case filter.Comparison:
+		if f.Operator != filter.OpIn && f.Operator != filter.OpNin {
+			return f, nil
+		}
+		values, err := f.Values()
+		if err != nil {
+			return nil, err
+		}
+		comparison, logical := filter.OpEq, filter.OpOr
+		if f.Operator == filter.OpNin {
+			comparison, logical = filter.OpNe, filter.OpAnd
+		}
+		operands := make([]filter.Filter, 0, len(values))
+		for _, value := range values {
+			operands = append(operands, filter.Comparison{Key: f.Key, Operator: comparison, Value: value})
+		}
+		return filter.Logical{Operator: logical, Filters: operands}, nil
+	case filter.Logical:
+		operands := make([]filter.Filter, 0, len(f.Filters))
+		for _, sub := range f.Filters {
+			expanded, err := expandIn(sub)
+			if err != nil {
+				return nil, err
+			}
+			operands = append(operands, expanded)
+		}
+		return filter.Logical{Operator: f.Operator, Filters: operands}, nil
+	default:
+		return f, nil
+	}
+}
+
+func translateFilter(f filter.Filter) (*filters.WhereBuilder, error) {
+	switch f := f.(type) {
+	case filter.Comparison:
+		return translateComparison(f)
+	case filter.Logical:
+		operator := filters.And
+		if f.Operator == filter.OpOr {
+			operator = filters.Or
+		}
+		operands := make([]*filters.WhereBuilder, 0, len(f.Filters))
+		for _, sub := range f.Filters {
+			operand, err := translateFilter(sub)
+			if err != nil {
+				return nil, err
+			}
+			operands = append(operands, operand)
+		}
+		return filters.Where().WithOperator(operator).WithOperands(operands), nil
+	default:
+		return nil, fmt.Errorf("%w: %T", filter.ErrUnsupportedFilter, f)
+	}
+}
+
+// _whereOperators are the weaviate operators of the comparisons on a single value.
+var _whereOperators = map[filter.Operator]filters.WhereOperator{ //nolint:gochecknoglobals
+	filter.OpEq:  filters.Equal,
+	filter.OpNe:  filters.NotEqual,
+	filter.OpGt:  filters.GreaterThan,
+	filter.OpGte: filters.GreaterThanEqual,
+	filter.OpLt:  filters.LessThan,
+	filter.OpLte: filters.LessThanEqual,
+}
+
+func translateComparison(c filter.Comparison) (*filters.WhereBuilder, error) {
+	operator, ok := _whereOperators[c.Operator]
+	if !ok {
+		return nil, fmt.Errorf("%w: operator %q", filter.ErrUnsupportedFilter, c.Operator)
+	}
+	return whereValue(filters.Where().WithPath([]string{c.Key}).WithOperator(operator), c.Value)
+}
+
+// whereValue sets the value of a where filter according to its type.
+func whereValue(where *filters.WhereBuilder, value any) (*filters.WhereBuilder, error) {
+	switch v := value.(type) {
+	case string:
+		return where.WithValueString(v), nil
+	case bool:
+		return where.WithValueBoolean(v), nil
+	case int:
+		return where.WithValueInt(int64(v)), nil
+	case int32:
+		return where.WithValueInt(int64(v)), nil
+	case int64:
+		return where.WithValueInt(v), nil
+	case float32:
+		return where.WithValueNumber(float64(v)), nil
+	case float64:
+		return where.WithValueNumber(v), nil
+	default:
+		return nil, fmt.Errorf("%w: value %v of type %T", filter.ErrUnsupportedFilter, value, value)
+	}
+}
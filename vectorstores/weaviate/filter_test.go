@@ -0,0 +1,45 @@
+package weaviate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/vectorstores/filter"
+	"github.com/weaviate/weaviate-go-client/v4/weaviate/filters"
+)
+
+func TestWhereFromFilter(t *testing.T) {
+	t.Parallel()
+
+	where, err := whereFromFilter(filter.And(
+		filter.In("location", "office", "kitchen"),
+		filter.Not(filter.Lt("square_feet", 300)),
+	))
+	require.NoError(t, err)
+
+	want := filters.Where().WithOperator(filters.And).WithOperands([]*filters.WhereBuilder{
+		filters.Where().WithOperator(filters.Or).WithOperands([]*filters.WhereBuilder{
+			filters.Where().WithPath([]string{"location"}).WithOperator(filters.Equal).WithValueString("office"),
+			filters.Where().WithPath([]string{"location"}).WithOperator(filters.Equal).WithValueString("kitchen"),
+		}),
+		filters.Where().WithPath([]string{"square_feet"}).WithOperator(filters.GreaterThanEqual).WithValueInt(300),
+	})
+	assert.Equal(t, want.String(), where.String())
+
+	_, err = whereFromFilter(filter.Eq("location", []string{"office"}))
+	assert.ErrorIs(t, err, filter.ErrUnsupportedFilter)
+
+	// No logical filter is sent without operands.
+	where, err = whereFromFilter(filter.And(filter.Nin("location"), filter.Eq("location", "office")))
+	require.NoError(t, err)
+	want = filters.Where().WithPath([]string{"location"}).WithOperator(filters.Equal).WithValueString("office")
+	assert.Equal(t, want.String(), where.String())
+
+	where, err = whereFromFilter(filter.And())
+	require.NoError(t, err)
+	assert.Nil(t, where)
+
+	_, err = whereFromFilter(filter.In("location"))
+	assert.ErrorIs(t, err, filter.ErrUnsupportedFilter)
+}
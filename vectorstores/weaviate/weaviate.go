@@ -12,8 +12,10 @@ import (
 	"github.com/tmc/langchaingo/embeddings"
 	"github.com/tmc/langchaingo/schema"
 	"github.com/tmc/langchaingo/vectorstores"
+	"github.com/tmc/langchaingo/vectorstores/filter"
 	"github.com/weaviate/weaviate-go-client/v4/weaviate"
 	"github.com/weaviate/weaviate-go-client/v4/weaviate/auth"
+	"github.com/weaviate/weaviate-go-client/v4/weaviate/fault"
 	"github.com/weaviate/weaviate-go-client/v4/weaviate/filters"
 	"github.com/weaviate/weaviate-go-client/v4/weaviate/graphql"
 	"github.com/weaviate/weaviate/entities/models"
@@ -60,7 +62,13 @@ type Store struct {
 	queryAttrs []string
 }
 
-var _ vectorstores.VectorStore = Store{}
+var (
+	_ vectorstores.VectorStore   = Store{}
+	_ vectorstores.Upserter      = Store{}
+	_ vectorstores.IDDeleter     = Store{}
+	_ vectorstores.FilterDeleter = Store{}
+	_ vectorstores.Getter        = Store{}
+)
 
 // New creates a new Store with options.
 // When using weaviate,
@@ -85,7 +93,27 @@ func New(opts ...Option) (Store, error) {
 	return s, nil
 }
 
+// AddDocuments creates vector embeddings from the documents using the embedder and stores
+// them as objects with random ids.
 func (s Store) AddDocuments(ctx context.Context, docs []schema.Document, options ...vectorstores.Option) error {
+	ids := make([]string, 0, len(docs))
+	for range docs {
+		ids = append(ids, uuid.New().String())
+	}
+	return s.UpsertDocuments(ctx, ids, docs, options...)
+}
+
+// UpsertDocuments creates vector embeddings from the documents using the embedder and stores
+// them as objects with the given ids, replacing the objects already stored under them. Since
+// weaviate only accepts UUIDs, and object ids are shared by all name spaces, the ids are
+// mapped to UUIDs derived from them and the name space, see objectID.
+func (s Store) UpsertDocuments(
+	ctx context.Context, ids []string, docs []schema.Document, options ...vectorstores.Option,
+) error {
+	if err := vectorstores.CheckIDs(ids, docs); err != nil {
+		return err
+	}
+
 	opts := s.getOptions(options...)
 	nameSpace := s.getNameSpace(opts)
 
@@ -119,7 +147,7 @@ func (s Store) AddDocuments(ctx context.Context, docs []schema.Document, options
 	for i := range docs {
 		objects = append(objects, &models.Object{
 			Class:      s.indexName,
-			ID:         strfmt.UUID(uuid.New().String()),
+			ID:         strfmt.UUID(objectID(nameSpace, ids[i])),
 			Vector:     vectors[i],
 			Properties: metadatas[i],
 		})
@@ -130,6 +158,94 @@ func (s Store) AddDocuments(ctx context.Context, docs []schema.Document, options
 	return nil
 }
 
+// DeleteDocumentsByID deletes the objects of the name space with the given ids.
+func (s Store) DeleteDocumentsByID(ctx context.Context, ids []string, options ...vectorstores.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	nameSpace := s.getNameSpace(s.getOptions(options...))
+	idFilters := make([]*filters.WhereBuilder, 0, len(ids))
+	for _, id := range ids {
+		idFilters = append(idFilters, filters.Where().WithPath([]string{"id"}).
+			WithOperator(filters.Equal).WithValueString(objectID(nameSpace, id)))
+	}
+
+	whereBuilder, err := s.createWhereBuilder(nameSpace, filters.Where().WithOperator(filters.Or).WithOperands(idFilters))
+	if err != nil {
+		return err
+	}
+	return s.deleteObjects(ctx, whereBuilder)
+}
+
+// DeleteDocuments deletes the objects of the name space matching the filters. All objects of
+// the name space are deleted if no filters are given and vectorstores.WithDeleteAll is set.
+func (s Store) DeleteDocuments(ctx context.Context, options ...vectorstores.Option) error {
+	opts := s.getOptions(options...)
+	if err := vectorstores.CheckDeleteFilters(opts); err != nil {
+		return err
+	}
+	whereBuilder, err := s.createWhereBuilder(s.getNameSpace(opts), s.getFilters(opts))
+	if err != nil {
+		return err
+	}
+	return s.deleteObjects(ctx, whereBuilder)
+}
+
+func (s Store) deleteObjects(ctx context.Context, whereBuilder *filters.WhereBuilder) error {
+	_, err := s.client.Batch().ObjectsBatchDeleter().
+		WithClassName(s.indexName).
+		WithWhere(whereBuilder).
+		Do(ctx)
+	return err
+}
+
+// GetDocumentsByID returns the documents of the name space with the given ids.
+func (s Store) GetDocumentsByID(
+	ctx context.Context, ids []string, options ...vectorstores.Option,
+) (map[string]schema.Document, error) {
+	nameSpace := s.getNameSpace(s.getOptions(options...))
+
+	docs := make(map[string]schema.Document, len(ids))
+	for _, id := range ids {
+		objects, err := s.client.Data().ObjectsGetter().
+			WithClassName(s.indexName).
+			WithID(objectID(nameSpace, id)).
+			Do(ctx)
+		var clientErr *fault.WeaviateClientError
+		if errors.As(err, &clientErr) && clientErr.StatusCode == http.StatusNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, object := range objects {
+			properties, ok := object.Properties.(map[string]any)
+			if !ok {
+				return nil, ErrInvalidResponse
+			}
+			if properties[s.nameSpaceKey] != nameSpace {
+				continue
+			}
+
+			pageContent, ok := properties[s.textKey].(string)
+			if !ok {
+				return nil, ErrMissingTextKey
+			}
+			delete(properties, s.textKey)
+			delete(properties, s.nameSpaceKey)
+			docs[id] = schema.Document{PageContent: pageContent, Metadata: properties}
+		}
+	}
+
+	return docs, nil
+}
+
+// SimilaritySearch creates a vector embedding from the query using the embedder and
+// queries to find the most similar objects of the name space, with their relevance score.
+// Relevance scores are computed from the certainty of the objects, which requires the
+// cosine distance.
 func (s Store) SimilaritySearch(
 	ctx context.Context,
 	query string,
@@ -142,8 +258,7 @@ func (s Store) SimilaritySearch(
 	if err != nil {
 		return nil, err
 	}
-	filter := s.getFilters(opts)
-	whereBuilder, err := s.createWhereBuilder(nameSpace, filter)
+	whereBuilder, err := s.createWhereBuilder(nameSpace, s.getFilters(opts))
 	if err != nil {
 		return nil, err
 	}
@@ -153,58 +268,127 @@ func (s Store) SimilaritySearch(
 		return nil, err
 	}
 
+	limit := numDocuments
+	if opts.MMR != nil {
+		if err := opts.MMR.Validate(); err != nil {
+			return nil, err
+		}
+		limit = opts.MMR.GetFetchK(numDocuments)
+	}
+
+	nearVector := s.client.GraphQL().NearVectorArgBuilder().WithVector(vector)
+	if scoreThreshold > 0 {
+		nearVector = nearVector.WithCertainty(certaintyFromRelevance(scoreThreshold))
+	}
+
 	res, err := s.client.GraphQL().
 		Get().
-		WithNearVector(s.client.GraphQL().
-			NearVectorArgBuilder().
-			WithVector(vector).
-			WithCertainty(scoreThreshold),
-		).
+		WithNearVector(nearVector).
 		WithWhere(whereBuilder).
 		WithClassName(s.indexName).
-		WithLimit(numDocuments).
-		WithFields(s.createFields()...).Do(ctx)
+		WithLimit(limit).
+		WithFields(s.createFields(opts.MMR != nil)...).Do(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return s.parseDocumentsByGraphQLResponse(res)
+	docs, vectors, err := s.parseDocumentsByGraphQLResponse(res)
+	if err != nil || opts.MMR == nil {
+		return docs, err
+	}
+	return vectorstores.MaximalMarginalRelevanceDocuments(vector, docs, vectors, opts.MMR.Lambda, numDocuments), nil
 }
 
-func (s Store) parseDocumentsByGraphQLResponse(res *models.GraphQLResponse) ([]schema.Document, error) {
+// parseDocumentsByGraphQLResponse returns the documents of the response and their vectors,
+// if they were requested.
+func (s Store) parseDocumentsByGraphQLResponse(
+	res *models.GraphQLResponse,
+) ([]schema.Document, [][]float32, error) {
 	if len(res.Errors) > 0 {
 		messages := make([]string, 0, len(res.Errors))
 		for _, e := range res.Errors {
 			messages = append(messages, e.Message)
 		}
-		return nil, fmt.Errorf("%w: %s", ErrInvalidResponse, strings.Join(messages, ", "))
+		return nil, nil, fmt.Errorf("%w: %s", ErrInvalidResponse, strings.Join(messages, ", "))
 	}
 
 	data, ok := res.Data["Get"].(map[string]any)[s.indexName]
 	if !ok || data == nil {
-		return nil, ErrEmptyResponse
+		return nil, nil, ErrEmptyResponse
 	}
 	items, ok := data.([]any)
 	if !ok || len(items) == 0 {
-		return nil, ErrEmptyResponse
+		return nil, nil, ErrEmptyResponse
 	}
 	docs := make([]schema.Document, 0, len(items))
+	vectors := make([][]float32, 0, len(items))
 	for _, item := range items {
 		itemMap, ok := item.(map[string]any)
 		if !ok {
-			return nil, ErrInvalidResponse
+			return nil, nil, ErrInvalidResponse
 		}
 		pageContent, ok := itemMap[s.textKey].(string)
 		if !ok {
-			return nil, ErrMissingTextKey
+			return nil, nil, ErrMissingTextKey
 		}
 		delete(itemMap, s.textKey)
 		doc := schema.Document{
 			PageContent: pageContent,
 			Metadata:    itemMap,
+			Score:       relevance(itemMap),
 		}
 		docs = append(docs, doc)
+		vectors = append(vectors, popVector(itemMap))
 	}
-	return docs, nil
+	return docs, vectors, nil
+}
+
+// relevance returns the relevance score of a GraphQL result item from its certainty, which
+// is (1 + cosine similarity) / 2.
+func relevance(itemMap map[string]any) float32 {
+	additional, _ := itemMap["_additional"].(map[string]any)
+	certainty, _ := additional["certainty"].(float64)
+	return vectorstores.RelevanceFromSimilarity(float32(2*certainty - 1))
+}
+
+// certaintyFromRelevance returns the certainty of a relevance score.
+func certaintyFromRelevance(score float32) float32 {
+	return (1 + score) / 2
+}
+
+// popVector removes the vector from the additional properties of a GraphQL result item
+// and returns it.
+func popVector(itemMap map[string]any) []float32 {
+	additional, ok := itemMap["_additional"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	values, ok := additional["vector"].([]any)
+	if !ok {
+		return nil
+	}
+	delete(additional, "vector")
+
+	vector := make([]float32, 0, len(values))
+	for _, value := range values {
+		if f, ok := value.(float64); ok {
+			vector = append(vector, float32(f))
+		}
+	}
+	return vector
+}
+
+// objectID returns the id of a name space as a weaviate object id. Outside of name spaces,
+// UUIDs are kept and other ids are mapped to a UUID derived from them. In a name space, ids
+// are mapped to a UUID derived from the name space and the id, so that the same id can be
+// used in several name spaces.
+func objectID(nameSpace, id string) string {
+	if nameSpace == "" {
+		if parsed, err := uuid.Parse(id); err == nil {
+			return parsed.String()
+		}
+		return uuid.NewSHA1(uuid.NameSpaceOID, []byte(id)).String()
+	}
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(nameSpace+"\x00"+id)).String()
 }
 
 func (s Store) getNameSpace(opts vectorstores.Options) string {
@@ -236,33 +420,51 @@ func (s Store) getOptions(options ...vectorstores.Option) vectorstores.Options {
 	return opts
 }
 
-func (s Store) createWhereBuilder(namespace string, filter any) (*filters.WhereBuilder, error) {
-	if filter == nil {
+// createWhereBuilder returns a where filter restricting the filters, a filter.Filter or a
+// *filters.WhereBuilder, to the name space.
+func (s Store) createWhereBuilder(namespace string, optsFilters any) (*filters.WhereBuilder, error) {
+	if optsFilters == nil {
 		return filters.Where().WithPath([]string{s.nameSpaceKey}).WithOperator(filters.Equal).WithValueString(namespace), nil
 	}
 
-	whereFilter, ok := filter.(*filters.WhereBuilder)
-	if !ok {
+	var whereFilter *filters.WhereBuilder
+	switch f := optsFilters.(type) {
+	case *filters.WhereBuilder:
+		whereFilter = f
+	case filter.Filter:
+		var err error
+		if whereFilter, err = whereFromFilter(f); err != nil {
+			return nil, err
+		}
+	default:
 		return nil, ErrInvalidFilter
 	}
+	if whereFilter == nil {
+		// The filter matches all objects.
+		return filters.Where().WithPath([]string{s.nameSpaceKey}).WithOperator(filters.Equal).WithValueString(namespace), nil
+	}
 	return filters.Where().WithOperator(filters.And).WithOperands([]*filters.WhereBuilder{
 		filters.Where().WithPath([]string{s.nameSpaceKey}).WithOperator(filters.Equal).WithValueString(namespace),
 		whereFilter,
 	}), nil
 }
 
-func (s Store) createFields() []graphql.Field {
+func (s Store) createFields(withVector bool) []graphql.Field {
 	fields := make([]graphql.Field, 0, len(s.queryAttrs))
 	for _, attr := range s.queryAttrs {
 		fields = append(fields, graphql.Field{
 			Name: attr,
 		})
 	}
+	additional := []graphql.Field{
+		{Name: "certainty"},
+	}
+	if withVector {
+		additional = append(additional, graphql.Field{Name: "vector"})
+	}
 	fields = append(fields, graphql.Field{
-		Name: "_additional",
-		Fields: []graphql.Field{
-			{Name: "certainty"},
-		},
+		Name:   "_additional",
+		Fields: additional,
 	})
 	return fields
 }
@@ -133,7 +133,7 @@ func TestWeaviateStoreRestWithScoreThreshold(t *testing.T) {
 	// test with a score threshold of 0.8, expected 6 documents
 	docs, err := store.SimilaritySearch(context.Background(),
 		"Which of these are cities in Japan", 10,
-		vectorstores.WithScoreThreshold(0.9))
+		vectorstores.WithScoreThreshold(0.8))
 	require.NoError(t, err)
 	require.Len(t, docs, 6)
 
@@ -537,3 +537,84 @@ func TestWeaviateAsRetrieverWithMetadataFilters(t *testing.T) {
 	require.NotContains(t, result, "orange", "expected not orange in result")
 	require.NotContains(t, result, "yellow", "expected not yellow in result")
 }
+
+func TestWeaviateStoreRestUpsertDeleteAndGet(t *testing.T) {
+	t.Parallel()
+
+	scheme, host := getValues(t)
+	e, err := openaiEmbeddings.NewOpenAI()
+	require.NoError(t, err)
+
+	store, err := New(
+		WithScheme(scheme),
+		WithHost(host),
+		WithEmbedder(e),
+		WithNameSpace(uuid.New().String()),
+		WithIndexName(randomizedCamelCaseClass()),
+		WithQueryAttrs([]string{"country"}),
+	)
+	require.NoError(t, err)
+
+	err = createTestClass(context.Background(), store)
+	require.NoError(t, err)
+
+	ids := []string{"city", "vegetable"}
+	err = store.UpsertDocuments(context.Background(), ids, []schema.Document{
+		{PageContent: "tokyo", Metadata: map[string]any{"country": "japan"}},
+		{PageContent: "potato", Metadata: map[string]any{"country": "peru"}},
+	})
+	require.NoError(t, err)
+
+	// Upserting replaces the document with the same id.
+	err = store.UpsertDocuments(context.Background(), ids[:1], []schema.Document{
+		{PageContent: "kyoto", Metadata: map[string]any{"country": "japan"}},
+	})
+	require.NoError(t, err)
+
+	docs, err := store.GetDocumentsByID(context.Background(), append(ids, "missing"))
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	require.Equal(t, "kyoto", docs["city"].PageContent)
+	require.Equal(t, "japan", docs["city"].Metadata["country"])
+
+	// The same id is a different object in another name space.
+	otherNameSpace := vectorstores.WithNameSpace(uuid.New().String())
+	err = store.UpsertDocuments(context.Background(), ids[:1], []schema.Document{
+		{PageContent: "paris", Metadata: map[string]any{"country": "france"}},
+	}, otherNameSpace)
+	require.NoError(t, err)
+	docs, err = store.GetDocumentsByID(context.Background(), ids[:1], otherNameSpace)
+	require.NoError(t, err)
+	require.Equal(t, "paris", docs["city"].PageContent)
+	docs, err = store.GetDocumentsByID(context.Background(), ids[:1])
+	require.NoError(t, err)
+	require.Equal(t, "kyoto", docs["city"].PageContent)
+
+	err = store.DeleteDocumentsByID(context.Background(), ids[:1])
+	require.NoError(t, err)
+
+	err = store.DeleteDocuments(context.Background(), vectorstores.WithFilters(
+		filters.Where().WithPath([]string{"country"}).WithOperator(filters.Equal).WithValueString("peru"),
+	))
+	require.NoError(t, err)
+
+	docs, err = store.GetDocumentsByID(context.Background(), ids)
+	require.NoError(t, err)
+	require.Empty(t, docs)
+}
+
+func TestObjectID(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.New().String()
+	require.Equal(t, id, objectID("", id))
+	require.Equal(t, objectID("", "docs/readme.md#0"), objectID("", "docs/readme.md#0"))
+	require.NotEqual(t, objectID("", "docs/readme.md#0"), objectID("", "docs/readme.md#1"))
+	_, err := uuid.Parse(objectID("", "docs/readme.md#0"))
+	require.NoError(t, err)
+
+	// The same id is stored under different objects in different name spaces.
+	require.NotEqual(t, objectID("a", "docs/readme.md#0"), objectID("b", "docs/readme.md#0"))
+	require.NotEqual(t, objectID("a", id), objectID("b", id))
+	require.Equal(t, objectID("a", id), objectID("a", id))
+}